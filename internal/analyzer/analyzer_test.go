@@ -113,6 +113,64 @@ func TestPatternMatcher(t *testing.T) {
 	}
 }
 
+func TestPatternConditions(t *testing.T) {
+	entry := func(level common.LogLevel, service, message string, metadata map[string]string) *common.LogEntry {
+		e := createTestEntry(time.Now(), level, level.String(), message)
+		e.Service = service
+		e.Metadata = metadata
+		return e
+	}
+	gatewayError := entry(common.LevelError, "gateway", "upstream returned an error", map[string]string{"status": "503"})
+	apiWarning := entry(common.LevelWarn, "api", "slow upstream, Retry later", map[string]string{"status": "200"})
+	errorService := entry(common.LevelInfo, "error-reporter", "report sent", nil)
+	errorService.Raw = "INFO error-reporter report sent"
+	stackTrace := entry(common.LevelError, "api", "request failed", nil)
+	stackTrace.Raw = "ERROR api request failed\n\tat db.Query(Pool.java:42)"
+	gatewayTimeout := entry(common.LevelError, "gateway", "upstream Timeout after 30s", nil)
+
+	tests := []struct {
+		name    string
+		pattern common.Pattern
+		entry   *common.LogEntry
+		want    bool
+	}{
+		{"condition only", common.Pattern{Condition: "level >= ERROR AND service in [api, gateway]"}, gatewayError, true},
+		{"condition fails", common.Pattern{Condition: "level >= ERROR AND service in [api, gateway]"}, apiWarning, false},
+		{"metadata regex", common.Pattern{Condition: `metadata.status =~ "5\d\d"`}, gatewayError, true},
+		{"keyword and condition", common.Pattern{Keywords: []string{"upstream"}, Condition: "NOT level in [DEBUG, INFO]"}, apiWarning, true},
+		{"keyword in service name", common.Pattern{Keywords: []string{"error"}}, errorService, true},
+		{"keyword in continuation line", common.Pattern{Keywords: []string{"pool.java"}}, stackTrace, true},
+		{"case-sensitive keyword in continuation line", common.Pattern{Keywords: []string{"Pool.java"}, CaseSensitive: true}, stackTrace, true},
+		{"message scoped", common.Pattern{Condition: "message ~ error"}, errorService, false},
+		{"case-insensitive keyword", common.Pattern{Keywords: []string{"retry"}}, apiWarning, true},
+		{"case-sensitive keyword", common.Pattern{Keywords: []string{"retry"}, CaseSensitive: true}, apiWarning, false},
+		{"case-sensitive regex", common.Pattern{Regex: "Retry later", CaseSensitive: true}, apiWarning, true},
+		{"case-sensitive regex fails", common.Pattern{Regex: "retry later", CaseSensitive: true}, apiWarning, false},
+		{"case-insensitive condition regex", common.Pattern{Condition: "message ~ timeout"}, gatewayTimeout, true},
+		{"case-sensitive condition regex", common.Pattern{Condition: "message ~ Timeout", CaseSensitive: true}, gatewayTimeout, true},
+		{"case-sensitive condition regex fails", common.Pattern{Condition: "message ~ timeout", CaseSensitive: true}, gatewayTimeout, false},
+		{"case-sensitive condition equality", common.Pattern{Condition: "service = Gateway", CaseSensitive: true}, gatewayTimeout, false},
+	}
+
+	for _, tt := range tests {
+		tt.pattern.ID = "p"
+		matcher := NewPatternMatcher()
+		if err := matcher.AddPattern(&tt.pattern); err != nil {
+			t.Fatalf("%s: AddPattern() failed: %v", tt.name, err)
+		}
+		if got := len(matcher.MatchSingle(tt.entry)) > 0; got != tt.want {
+			t.Errorf("%s: matched = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, condition := range []string{"level >= LOUD", "service in [api"} {
+		pattern := &common.Pattern{ID: "p", Condition: condition}
+		if err := NewPatternMatcher().AddPattern(pattern); err == nil {
+			t.Errorf("AddPattern() with condition %q succeeded, want error", condition)
+		}
+	}
+}
+
 func TestInsightGenerator(t *testing.T) {
 	gen := NewInsightGenerator()
 
@@ -0,0 +1,522 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// AnomalyMethod selects how the baseline of a bucket is estimated
+type AnomalyMethod string
+
+const (
+	AnomalyZScore AnomalyMethod = "zscore" // rolling mean and standard deviation
+	AnomalyEWMA   AnomalyMethod = "ewma"   // exponentially weighted mean and variance
+	AnomalyMAD    AnomalyMethod = "mad"    // rolling median and median absolute deviation
+)
+
+const (
+	// anomalyMinSpread floors the spread at one event per bucket, so a flat
+	// baseline does not make every change infinitely anomalous
+	anomalyMinSpread = 1.0
+
+	// madScale makes the median absolute deviation comparable to a
+	// standard deviation for normally distributed counts
+	madScale = 1.4826
+
+	// maxAnomalyBuckets caps the anomalous buckets kept per series, and the
+	// empty buckets scored across a gap in the log, by which time any
+	// baseline has settled on zero
+	maxAnomalyBuckets = 2000
+)
+
+// AnomalyOptions configures statistical anomaly detection
+type AnomalyOptions struct {
+	Enabled     bool
+	Method      AnomalyMethod
+	Sensitivity float64       // spreads above the baseline that count as anomalous
+	Window      int           // buckets in the rolling baseline of zscore and mad
+	MinBaseline int           // buckets seen before a bucket is scored
+	MinCount    int           // events an anomalous bucket must hold
+	Alpha       float64       // ewma smoothing factor, the weight of the newest bucket
+	BucketSize  time.Duration // width of the counted buckets
+}
+
+// DefaultAnomalyOptions returns the default detection settings
+func DefaultAnomalyOptions() AnomalyOptions {
+	return AnomalyOptions{
+		Enabled:     true,
+		Method:      AnomalyZScore,
+		Sensitivity: 3,
+		Window:      30,
+		MinBaseline: 5,
+		MinCount:    5,
+		Alpha:       0.3,
+		BucketSize:  time.Minute,
+	}
+}
+
+// Validate checks the options for values detection cannot work with
+func (o AnomalyOptions) Validate() error {
+	if !o.Enabled {
+		return nil
+	}
+	switch o.Method {
+	case AnomalyZScore, AnomalyEWMA, AnomalyMAD:
+	default:
+		return fmt.Errorf("unknown anomaly method %q (use zscore, ewma or mad)", o.Method)
+	}
+	if o.Sensitivity <= 0 {
+		return fmt.Errorf("anomaly sensitivity must be positive")
+	}
+	if o.MinBaseline < 2 {
+		return fmt.Errorf("anomaly baseline must span at least 2 buckets")
+	}
+	if o.Method != AnomalyEWMA && o.Window < o.MinBaseline {
+		return fmt.Errorf("anomaly window of %d buckets is shorter than the %d bucket baseline", o.Window, o.MinBaseline)
+	}
+	if o.Method == AnomalyEWMA && (o.Alpha <= 0 || o.Alpha > 1) {
+		return fmt.Errorf("anomaly alpha must be in (0, 1]")
+	}
+	if o.BucketSize <= 0 {
+		return fmt.Errorf("anomaly bucket size must be positive")
+	}
+	return nil
+}
+
+// AnomalyPoint is a bucket that rose above its baseline
+type AnomalyPoint struct {
+	Index     int
+	Value     float64
+	Baseline  float64
+	Deviation float64 // distance above the baseline, in spreads
+}
+
+// AnomalyDetector scores each bucket of a series against the buckets
+// before it
+type AnomalyDetector struct {
+	options AnomalyOptions
+}
+
+// NewAnomalyDetector creates a detector with validated options
+func NewAnomalyDetector(options AnomalyOptions) *AnomalyDetector {
+	return &AnomalyDetector{options: options}
+}
+
+// Detect returns the buckets of values that are anomalously high
+func (d *AnomalyDetector) Detect(values []float64) []AnomalyPoint {
+	if d.options.Sensitivity <= 0 {
+		return nil
+	}
+
+	var points []AnomalyPoint
+	scorer := newAnomalyScorer(d.options)
+	for _, value := range values {
+		if point, anomalous := scorer.next(value); anomalous {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// anomalyScorer scores a series one bucket at a time against the buckets
+// before it, keeping only the baseline state
+type anomalyScorer struct {
+	options        AnomalyOptions
+	seen           int
+	history        []float64 // last Window values, for zscore and mad
+	mean, variance float64   // ewma state
+}
+
+func newAnomalyScorer(options AnomalyOptions) *anomalyScorer {
+	return &anomalyScorer{options: options}
+}
+
+// next scores the next bucket of the series and adds it to the baseline
+func (s *anomalyScorer) next(value float64) (AnomalyPoint, bool) {
+	point, anomalous := s.peek(value)
+	s.seen++
+	switch s.options.Method {
+	case AnomalyEWMA:
+		if s.seen == 1 {
+			s.mean = value
+		} else {
+			diff := value - s.mean
+			s.mean += s.options.Alpha * diff
+			s.variance = (1 - s.options.Alpha) * (s.variance + s.options.Alpha*diff*diff)
+		}
+	default:
+		s.history = append(s.history, value)
+		if len(s.history) > s.options.Window {
+			s.history = s.history[len(s.history)-s.options.Window:]
+		}
+	}
+	return point, anomalous
+}
+
+// peek scores value as the next bucket without adding it to the baseline
+func (s *anomalyScorer) peek(value float64) (AnomalyPoint, bool) {
+	if s.seen < s.options.MinBaseline {
+		return AnomalyPoint{}, false
+	}
+
+	var baseline, spread float64
+	switch s.options.Method {
+	case AnomalyEWMA:
+		baseline, spread = s.mean, math.Sqrt(s.variance)
+	case AnomalyMAD:
+		baseline, spread = medianSpread(s.history)
+	default:
+		baseline, spread = meanSpread(s.history)
+	}
+	if math.Max(value, baseline) < float64(s.options.MinCount) {
+		return AnomalyPoint{}, false
+	}
+
+	deviation := (value - baseline) / math.Max(spread, anomalyMinSpread)
+	if deviation < s.options.Sensitivity {
+		return AnomalyPoint{}, false
+	}
+	return AnomalyPoint{Index: s.seen, Value: value, Baseline: baseline, Deviation: deviation}, true
+}
+
+// meanSpread returns the mean and standard deviation of values
+func meanSpread(values []float64) (float64, float64) {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+// medianSpread returns the median of values and their scaled median
+// absolute deviation
+func medianSpread(values []float64) (float64, float64) {
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, value := range values {
+		deviations[i] = math.Abs(value - median)
+	}
+	return median, madScale * medianOf(deviations)
+}
+
+// medianOf returns the median of values without reordering them
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// anomalySeries scores one count series, such as the errors per minute,
+// one bucket at a time as entries arrive. It keeps only the anomalous
+// buckets and, for those, the first few entries counted in them, so memory
+// does not grow with the time span of the log. Entries are expected in
+// time order; late ones are not counted.
+type anomalySeries struct {
+	bucketSize    time.Duration
+	evidenceLimit int
+	scorer        *anomalyScorer
+	current       time.Time          // bucket being counted
+	count         int                // entries in the current bucket
+	pending       []*common.LogEntry // first entries of the current bucket
+	anomalous     []anomalousBucket
+}
+
+// anomalousBucket is a bucket scored as anomalous, with its first entries
+type anomalousBucket struct {
+	start    time.Time
+	point    AnomalyPoint
+	evidence []*common.LogEntry
+}
+
+// newAnomalySeries creates a series scored with options, starting at the
+// bucket first and keeping up to evidenceLimit entries per anomalous bucket
+func newAnomalySeries(options AnomalyOptions, first time.Time, evidenceLimit int) *anomalySeries {
+	return &anomalySeries{
+		bucketSize:    options.BucketSize,
+		evidenceLimit: evidenceLimit,
+		scorer:        newAnomalyScorer(options),
+		current:       first,
+	}
+}
+
+// add counts an entry in its bucket
+func (a *anomalySeries) add(entry *common.LogEntry) {
+	bucket := entry.Timestamp.Truncate(a.bucketSize)
+	if bucket.After(a.current) {
+		a.advance(bucket)
+	}
+	if !bucket.Equal(a.current) {
+		return
+	}
+	a.count++
+	if len(a.pending) < a.evidenceLimit {
+		a.pending = append(a.pending, entry)
+	}
+}
+
+// advance scores the current bucket and the empty ones up to bucket, which
+// becomes the current one
+func (a *anomalySeries) advance(bucket time.Time) {
+	if point, anomalous := a.scorer.next(float64(a.count)); anomalous && len(a.anomalous) < maxAnomalyBuckets {
+		a.anomalous = append(a.anomalous, anomalousBucket{start: a.current, point: point, evidence: a.pending})
+	}
+	gap := min(int(bucket.Sub(a.current)/a.bucketSize)-1, maxAnomalyBuckets)
+	for i := 0; i < gap; i++ {
+		a.scorer.next(0)
+	}
+	a.current = bucket
+	a.count = 0
+	a.pending = nil
+}
+
+// anomalies returns the anomalous buckets in time order. The current
+// bucket is scored without completing it, as more entries may follow.
+func (a *anomalySeries) anomalies() []anomalousBucket {
+	point, anomalous := a.scorer.peek(float64(a.count))
+	if !anomalous {
+		return a.anomalous
+	}
+	current := anomalousBucket{start: a.current, point: point, evidence: a.pending}
+	return append(a.anomalous[:len(a.anomalous):len(a.anomalous)], current)
+}
+
+// anomalyWindow is a run of consecutive anomalous buckets, described by
+// its most deviant bucket
+type anomalyWindow struct {
+	start, end time.Time
+	peak       AnomalyPoint
+	evidence   []*common.LogEntry
+}
+
+// anomalyWindows joins consecutive anomalous buckets deviating at least
+// sensitivity into windows, with up to evidenceLimit entries each
+func anomalyWindows(buckets []anomalousBucket, size time.Duration, sensitivity float64, evidenceLimit int) []anomalyWindow {
+	var windows []anomalyWindow
+	for _, bucket := range buckets {
+		if bucket.point.Deviation < sensitivity {
+			continue
+		}
+		end := bucket.start.Add(size)
+		if n := len(windows); n > 0 && windows[n-1].end.Equal(bucket.start) {
+			window := &windows[n-1]
+			window.end = end
+			if bucket.point.Deviation > window.peak.Deviation {
+				window.peak = bucket.point
+			}
+			window.evidence = appendEvidence(window.evidence, bucket.evidence, evidenceLimit)
+			continue
+		}
+		windows = append(windows, anomalyWindow{
+			start:    bucket.start,
+			end:      end,
+			peak:     bucket.point,
+			evidence: appendEvidence([]*common.LogEntry{}, bucket.evidence, evidenceLimit),
+		})
+	}
+	return windows
+}
+
+// appendEvidence appends entries to evidence until it holds limit
+func appendEvidence(evidence, entries []*common.LogEntry, limit int) []*common.LogEntry {
+	return append(evidence, entries[:min(len(entries), max(limit-len(evidence), 0))]...)
+}
+
+// statisticalDetector scores the entry, error and per-pattern series for
+// anomalous buckets
+type statisticalDetector struct {
+	options AnomalyOptions
+}
+
+// NewStatisticalDetector creates the detector of statistical anomalies in
+// entry, error and pattern match volume
+func NewStatisticalDetector(options AnomalyOptions) InsightDetector {
+	return &statisticalDetector{options: options}
+}
+
+func (d *statisticalDetector) Name() string { return "statistical" }
+
+func (d *statisticalDetector) NewRun(tuning InsightTuning) DetectorRun {
+	options := d.options
+	if options.BucketSize <= 0 {
+		options.BucketSize = DefaultAnomalyOptions().BucketSize
+	}
+
+	// Pattern buckets are kept at the lowest sensitivity of any tag, and
+	// each pattern's own is applied when reporting
+	patternOptions := options
+	for _, tagged := range tuning.Tags {
+		if tagged.Sensitivity > 0 && tagged.Sensitivity < patternOptions.Sensitivity {
+			patternOptions.Sensitivity = tagged.Sensitivity
+		}
+	}
+	return &statisticalRun{
+		options:        options,
+		patternOptions: patternOptions,
+		tuning:         tuning,
+		patterns:       make(map[string]*anomalySeries),
+	}
+}
+
+// statisticalRun scores the series of one analysis
+type statisticalRun struct {
+	options        AnomalyOptions
+	patternOptions AnomalyOptions
+	tuning         InsightTuning
+	first          time.Time // bucket of the first entry
+	entries        *anomalySeries
+	errors         *anomalySeries
+	patterns       map[string]*anomalySeries // by pattern ID
+}
+
+// start returns the first bucket, the one every series starts at, so the
+// buckets before a pattern's first match count as zero. Entries without a
+// timestamp are not counted.
+func (r *statisticalRun) start(entry *common.LogEntry) bool {
+	if entry.Timestamp.IsZero() {
+		return false
+	}
+	if r.first.IsZero() {
+		r.first = entry.Timestamp.Truncate(r.options.BucketSize)
+		r.entries = newAnomalySeries(r.options, r.first, 0)
+		r.errors = newAnomalySeries(r.options, r.first, r.tuning.EvidenceLimit)
+	}
+	return true
+}
+
+func (r *statisticalRun) Add(entry *common.LogEntry) {
+	if !r.start(entry) {
+		return
+	}
+	r.entries.add(entry)
+	if entry.LogLevel >= common.LevelError {
+		r.errors.add(entry)
+	}
+}
+
+func (r *statisticalRun) AddMatch(patternID string, entry *common.LogEntry) {
+	if !r.start(entry) {
+		return
+	}
+	series, exists := r.patterns[patternID]
+	if !exists {
+		series = newAnomalySeries(r.patternOptions, r.first, r.tuning.EvidenceLimit)
+		r.patterns[patternID] = series
+	}
+	series.add(entry)
+}
+
+// Insights reports each anomalous window of the entry, error and
+// per-pattern series as an insight
+func (r *statisticalRun) Insights(summary InsightSummary) []Insight {
+	if r.first.IsZero() {
+		return nil
+	}
+	options := r.options
+	size := options.BucketSize
+
+	var insights []Insight
+	report := func(name string, series *anomalySeries, options AnomalyOptions, describe func(anomalyWindow) Insight) {
+		for _, window := range anomalyWindows(series.anomalies(), size, options.Sensitivity, r.tuning.EvidenceLimit) {
+			insight := describe(window)
+			insight.Type = InsightTypeAnomaly
+			insight.Confidence = anomalyConfidence(window.peak.Deviation, options.Sensitivity)
+			insight.Evidence = window.evidence
+			insight.Anomaly = &common.AnomalyDetail{
+				Series:    name,
+				Method:    string(options.Method),
+				Start:     window.start,
+				End:       window.end,
+				Value:     window.peak.Value,
+				Baseline:  window.peak.Baseline,
+				Deviation: window.peak.Deviation,
+			}
+			insights = append(insights, insight)
+		}
+	}
+
+	report("entries", r.entries, options, func(window anomalyWindow) Insight {
+		return Insight{
+			Severity:    common.LevelWarn,
+			Title:       "Log Volume Anomaly",
+			Description: formatSeriesAnomalyDescription("entries", window, size, options.Method),
+		}
+	})
+	report("errors", r.errors, options, func(window anomalyWindow) Insight {
+		return Insight{
+			Severity:    common.LevelError,
+			Title:       "Error Volume Anomaly",
+			Description: formatSeriesAnomalyDescription("errors", window, size, options.Method),
+		}
+	})
+	for _, match := range summary.Patterns {
+		match := match
+		series := r.patterns[match.Pattern.ID]
+		tagged := r.tuning.ForPattern(match.Pattern)
+		if series == nil || tagged.Disabled {
+			continue
+		}
+		patternOptions := options
+		if tagged.Sensitivity > 0 {
+			patternOptions.Sensitivity = tagged.Sensitivity
+		}
+		before := len(insights)
+		report("pattern:"+match.Pattern.ID, series, patternOptions, func(window anomalyWindow) Insight {
+			severity := match.Pattern.Severity
+			if severity < common.LevelWarn {
+				severity = common.LevelWarn
+			}
+			return Insight{
+				Severity:    severity,
+				Title:       "Volume Anomaly: " + match.Pattern.Name,
+				Description: formatSeriesAnomalyDescription(fmt.Sprintf("'%s' matches", match.Pattern.Name), window, size, options.Method),
+			}
+		})
+		kept := insights[:before]
+		for _, insight := range insights[before:] {
+			if insight.Confidence >= tagged.MinConfidence {
+				kept = append(kept, insight)
+			}
+		}
+		insights = kept
+	}
+	return insights
+}
+
+// anomalyConfidence grows from 0.5 at the sensitivity towards 0.95 as the
+// deviation grows
+func anomalyConfidence(deviation, sensitivity float64) float64 {
+	return minFloat(0.95, 0.5+0.45*(1-sensitivity/deviation))
+}
+
+func formatSeriesAnomalyDescription(what string, window anomalyWindow, size time.Duration, method AnomalyMethod) string {
+	return fmt.Sprintf("%.0f %s per %s between %s and %s, %.1f deviations above the baseline of %.1f (%s)",
+		window.peak.Value, what, shortDuration(size), window.start.Format("15:04:05"), window.end.Format("15:04:05"),
+		window.peak.Deviation, window.peak.Baseline, method)
+}
+
+// shortDuration formats d without trailing zero units, as 1m rather than 1m0s
+func shortDuration(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
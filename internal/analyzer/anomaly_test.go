@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func TestAnomalyDetector(t *testing.T) {
+	steady := []float64{10, 12, 9, 11, 10, 13, 10, 11, 12, 10}
+	spike := append(append([]float64(nil), steady...), 40, 11, 10)
+	// A single outlier in the baseline inflates the standard deviation
+	// but not the median absolute deviation
+	outlier := []float64{10, 11, 10, 60, 10, 11, 10, 11, 10, 25}
+
+	tests := []struct {
+		name   string
+		method AnomalyMethod
+		values []float64
+		want   []int
+	}{
+		{"zscore spike", AnomalyZScore, spike, []int{10}},
+		{"ewma spike", AnomalyEWMA, spike, []int{10}},
+		{"mad spike", AnomalyMAD, spike, []int{10}},
+		{"zscore steady", AnomalyZScore, steady, nil},
+		{"zscore after outlier", AnomalyZScore, outlier, nil},
+		{"mad after outlier", AnomalyMAD, outlier, []int{9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultAnomalyOptions()
+			options.Method = tt.method
+			points := NewAnomalyDetector(options).Detect(tt.values)
+
+			var got []int
+			for _, point := range points {
+				got = append(got, point.Index)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Detect() = %v, want buckets %v", points, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Detect() = buckets %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAnomalyDetectorFlatBaseline(t *testing.T) {
+	options := DefaultAnomalyOptions()
+	detector := NewAnomalyDetector(options)
+
+	// Without a spread floor any change from a flat baseline is anomalous
+	if points := detector.Detect([]float64{5, 5, 5, 5, 5, 5, 6}); len(points) != 0 {
+		t.Errorf("Detect() = %v, want no anomaly for a change of one", points)
+	}
+	// Buckets below MinCount are never anomalous
+	if points := detector.Detect([]float64{0, 0, 0, 0, 0, 0, 4}); len(points) != 0 {
+		t.Errorf("Detect() = %v, want no anomaly below the minimum count", points)
+	}
+	points := detector.Detect([]float64{0, 0, 0, 0, 0, 0, 8})
+	if len(points) != 1 || points[0].Baseline != 0 || points[0].Deviation != 8 {
+		t.Errorf("Detect() = %v, want one point 8 above a zero baseline", points)
+	}
+}
+
+func TestInvalidAnomalyOptions(t *testing.T) {
+	for _, change := range []func(*AnomalyOptions){
+		func(o *AnomalyOptions) { o.Method = "median" },
+		func(o *AnomalyOptions) { o.Sensitivity = 0 },
+		func(o *AnomalyOptions) { o.Window = 2 },
+		func(o *AnomalyOptions) { o.Method, o.Alpha = AnomalyEWMA, 0 },
+		func(o *AnomalyOptions) { o.BucketSize = 0 },
+	} {
+		options := DefaultAnomalyOptions()
+		change(&options)
+		if err := NewEngine().SetAnomalyOptions(options); err == nil {
+			t.Errorf("SetAnomalyOptions(%+v) succeeded, want error", options)
+		}
+	}
+}
+
+func TestStatisticalAnomalyInsights(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entries := spikeEntries()
+
+	engine := NewEngine()
+	patterns := []*common.Pattern{{ID: "timeout", Name: "Timeout", Type: common.PatternTypeError, Keywords: []string{"timeout"}}}
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range entries {
+		stream.Add(entry)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		found := make(map[string]*common.AnomalyDetail)
+		for _, insight := range analysis.Insights {
+			if insight.Anomaly == nil {
+				continue
+			}
+			found[insight.Anomaly.Series] = insight.Anomaly
+			if len(insight.Evidence) == 0 && insight.Anomaly.Series != "entries" {
+				t.Errorf("%s: %s anomaly has no evidence", name, insight.Anomaly.Series)
+			}
+		}
+
+		for _, series := range []string{"errors", "pattern:timeout"} {
+			detail := found[series]
+			if detail == nil {
+				t.Fatalf("%s: no %s anomaly in %+v", name, series, analysis.Insights)
+			}
+			start, end := base.Add(12*time.Minute), base.Add(14*time.Minute)
+			if !detail.Start.Equal(start) || !detail.End.Equal(end) {
+				t.Errorf("%s: %s window = %v to %v, want %v to %v", name, series, detail.Start, detail.End, start, end)
+			}
+			if detail.Value != 30 || detail.Baseline != 1 || detail.Method != "zscore" {
+				t.Errorf("%s: %s anomaly = %+v, want 30 against a baseline of 1", name, series, detail)
+			}
+		}
+	}
+
+	options := DefaultAnomalyOptions()
+	options.Enabled = false
+	if err := engine.SetAnomalyOptions(options); err != nil {
+		t.Fatalf("SetAnomalyOptions() failed: %v", err)
+	}
+	analysis, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	for _, insight := range analysis.Insights {
+		if insight.Anomaly != nil {
+			t.Errorf("disabled detection reported %+v", insight.Anomaly)
+		}
+	}
+}
+
+func TestAnomalySeriesKeepsEvidenceOfFlaggedBuckets(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	series := newAnomalySeries(DefaultAnomalyOptions(), base, 5)
+	for _, entry := range spikeEntries() {
+		if entry.LogLevel >= common.LevelError {
+			series.add(entry)
+		}
+	}
+
+	want := []time.Time{base.Add(12 * time.Minute), base.Add(13 * time.Minute)}
+	anomalies := series.anomalies()
+	if len(anomalies) != len(want) {
+		t.Fatalf("anomalies() = %d buckets, want %d", len(anomalies), len(want))
+	}
+	for i, bucket := range anomalies {
+		if !bucket.start.Equal(want[i]) || len(bucket.evidence) != 5 {
+			t.Errorf("bucket %d = %v with %d entries, want %v with 5", i, bucket.start, len(bucket.evidence), want[i])
+		}
+	}
+
+	windows := anomalyWindows(anomalies, time.Minute, 3, 8)
+	if len(windows) != 1 || !windows[0].end.Equal(base.Add(14*time.Minute)) || len(windows[0].evidence) != 8 {
+		t.Errorf("anomalyWindows() = %+v, want one window to 10:14 with 8 entries", windows)
+	}
+}
+
+func TestStatisticalAnomalyEvidenceLateInLongLog(t *testing.T) {
+	// Forty hours of one timeout a minute, more than the stream keeps as
+	// pattern samples, with a burst near the end
+	base := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	spike := base.Add(39 * time.Hour)
+	var entries []*common.LogEntry
+	for at := base; at.Before(base.Add(40 * time.Hour)); at = at.Add(time.Minute) {
+		count := 1
+		if at.Equal(spike) {
+			count = 30
+		}
+		for i := 0; i < count; i++ {
+			entries = append(entries, createTestEntry(at.Add(time.Duration(i)*time.Second), common.LevelError, "ERROR", "upstream timeout"))
+		}
+	}
+
+	engine := NewEngine()
+	patterns := []*common.Pattern{{ID: "timeout", Name: "Timeout", Type: common.PatternTypeError, Keywords: []string{"timeout"}}}
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range entries {
+		stream.Add(entry)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		found := make(map[string]bool)
+		for _, insight := range analysis.Insights {
+			if insight.Anomaly == nil || insight.Anomaly.Series == "entries" {
+				continue
+			}
+			found[insight.Anomaly.Series] = true
+			if !insight.Anomaly.Start.Equal(spike) {
+				t.Errorf("%s: %s window starts %v, want %v", name, insight.Anomaly.Series, insight.Anomaly.Start, spike)
+			}
+			if len(insight.Evidence) != 5 {
+				t.Errorf("%s: %s evidence = %d entries, want 5", name, insight.Anomaly.Series, len(insight.Evidence))
+			}
+			for _, entry := range insight.Evidence {
+				if entry.Timestamp.Before(insight.Anomaly.Start) || !entry.Timestamp.Before(insight.Anomaly.End) {
+					t.Errorf("%s: %s evidence at %v is outside the window", name, insight.Anomaly.Series, entry.Timestamp)
+				}
+			}
+		}
+		if !found["errors"] || !found["pattern:timeout"] {
+			t.Errorf("%s: anomalies found for %v, want errors and pattern:timeout", name, found)
+		}
+	}
+}
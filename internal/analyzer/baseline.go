@@ -0,0 +1,349 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	corrpkg "github.com/yildizm/LogSum/internal/correlation"
+)
+
+// DiffOptions sets how large a difference from a baseline must be to be
+// reported
+type DiffOptions struct {
+	RateChange      float64 // factor a rate must rise or fall by
+	MinCount        int     // occurrences, in either analysis, a rate or service needs
+	ErrorRatioShift float64 // change in the share of a service's entries that are errors
+	Limit           int     // changes reported per kind
+}
+
+// DefaultDiffOptions returns the default baseline comparison settings
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{
+		RateChange:      2.0, // doubled or halved
+		MinCount:        5,
+		ErrorRatioShift: 0.1, // 10 percentage points
+		Limit:           10,
+	}
+}
+
+// maxProfileSignatures caps the error signatures a profile counts. Errors
+// with further signatures are left out of the profile.
+const maxProfileSignatures = 10000
+
+// profileBuilder counts the services and error signatures of entries for
+// an analysis profile
+type profileBuilder struct {
+	signatures    map[string]common.SignatureProfile
+	services      map[string]common.ServiceProfile
+	maxSignatures int
+	capped        bool // signatures were left out at maxSignatures
+}
+
+func newProfileBuilder() *profileBuilder {
+	return &profileBuilder{
+		signatures:    make(map[string]common.SignatureProfile),
+		services:      make(map[string]common.ServiceProfile),
+		maxSignatures: maxProfileSignatures,
+	}
+}
+
+// add counts an entry under its service and, for errors, its signature
+func (p *profileBuilder) add(entry *common.LogEntry) {
+	isError := entry.LogLevel >= common.LevelError
+	if entry.Service != "" {
+		service := p.services[entry.Service]
+		service.Entries++
+		if isError {
+			service.Errors++
+		}
+		p.services[entry.Service] = service
+	}
+	if !isError {
+		return
+	}
+
+	// The source names the input file, which differs between a baseline
+	// and the log compared with it
+	unsourced := *entry
+	unsourced.Source = ""
+	key := corrpkg.ErrorSignature(&unsourced)
+	signature, exists := p.signatures[key]
+	if !exists {
+		if len(p.signatures) >= p.maxSignatures {
+			p.capped = true
+			return
+		}
+		signature.Example = entry.Message
+	}
+	signature.Count++
+	p.signatures[key] = signature
+}
+
+// profile completes a profile with the totals and pattern counts of
+// analysis. The counts are copied, so the builder can keep counting.
+func (p *profileBuilder) profile(analysis *Analysis) *common.AnalysisProfile {
+	profile := &common.AnalysisProfile{
+		StartTime:        analysis.StartTime,
+		EndTime:          analysis.EndTime,
+		TotalEntries:     analysis.TotalEntries,
+		ErrorCount:       analysis.ErrorCount,
+		Patterns:         make(map[string]common.PatternProfile, len(analysis.Patterns)),
+		ErrorSignatures:  make(map[string]common.SignatureProfile, len(p.signatures)),
+		Services:         make(map[string]common.ServiceProfile, len(p.services)),
+		SignaturesCapped: p.capped,
+	}
+	for _, match := range analysis.Patterns {
+		if match.Count > 0 {
+			profile.Patterns[match.Pattern.ID] = common.PatternProfile{Name: match.Pattern.Name, Count: match.Count}
+		}
+	}
+	for key, signature := range p.signatures {
+		profile.ErrorSignatures[key] = signature
+	}
+	for name, service := range p.services {
+		profile.Services[name] = service
+	}
+	return profile
+}
+
+// CompareProfiles reports how the analysis profiled by current differs
+// from baseline: volume, new patterns, pattern rate changes, new error
+// signatures and shifts in service error ratios
+func CompareProfiles(baseline, current *common.AnalysisProfile, options DiffOptions) *common.BaselineDiff {
+	diff := &common.BaselineDiff{
+		BaselineEntries: baseline.TotalEntries,
+		BaselineErrors:  baseline.ErrorCount,
+		Changes:         []common.BaselineChange{},
+	}
+	rates := newRateScale(baseline, current)
+	diff.RateUnit = rates.unit
+
+	diff.Changes = append(diff.Changes, compareVolume(baseline, current, rates, options)...)
+	diff.Changes = append(diff.Changes, comparePatterns(baseline, current, rates, options)...)
+	diff.Changes = append(diff.Changes, compareErrorSignatures(baseline, current, options)...)
+	diff.Changes = append(diff.Changes, compareServices(baseline, current, options)...)
+	return diff
+}
+
+// rateScale turns counts into rates comparable between two profiles
+type rateScale struct {
+	baseline, current float64 // factor applied to counts of each profile
+	unit              string
+	timed             bool
+}
+
+// newRateScale counts per minute when both profiles span at least a
+// minute, and per 1000 entries otherwise
+func newRateScale(baseline, current *common.AnalysisProfile) rateScale {
+	baselineSpan := baseline.EndTime.Sub(baseline.StartTime)
+	currentSpan := current.EndTime.Sub(current.StartTime)
+	if baselineSpan >= time.Minute && currentSpan >= time.Minute {
+		return rateScale{1 / baselineSpan.Minutes(), 1 / currentSpan.Minutes(), "per minute", true}
+	}
+	perThousand := func(total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return 1000 / float64(total)
+	}
+	return rateScale{perThousand(baseline.TotalEntries), perThousand(current.TotalEntries), "per 1000 entries", false}
+}
+
+// compareVolume reports significant changes in the entry and error rates.
+// Entry rates are only compared over time.
+func compareVolume(baseline, current *common.AnalysisProfile, rates rateScale, options DiffOptions) []common.BaselineChange {
+	var changes []common.BaselineChange
+	series := []struct {
+		key, subject           string
+		baseline, current      int
+		comparableWithoutTimes bool
+	}{
+		{"entries", "Entries", baseline.TotalEntries, current.TotalEntries, false},
+		{"errors", "Errors", baseline.ErrorCount, current.ErrorCount, true},
+	}
+	for _, s := range series {
+		if !rates.timed && !s.comparableWithoutTimes {
+			continue
+		}
+		before, after := float64(s.baseline)*rates.baseline, float64(s.current)*rates.current
+		if !rateChanged(before, after, s.baseline, s.current, options) {
+			continue
+		}
+		changes = append(changes, common.BaselineChange{
+			Kind:        common.BaselineChangeVolume,
+			Key:         s.key,
+			Subject:     s.subject,
+			Baseline:    before,
+			Current:     after,
+			Description: describeRateChange(before, after, rates.unit),
+		})
+	}
+	return changes
+}
+
+// comparePatterns reports patterns that did not match in the baseline and
+// patterns whose rate changed significantly
+func comparePatterns(baseline, current *common.AnalysisProfile, rates rateScale, options DiffOptions) []common.BaselineChange {
+	var added, changed []common.BaselineChange
+	var addedCounts []int
+	for id, pattern := range current.Patterns {
+		after := float64(pattern.Count) * rates.current
+		before, seen := baseline.Patterns[id]
+		if !seen || before.Count == 0 {
+			added = append(added, common.BaselineChange{
+				Kind:        common.BaselineChangeNewPattern,
+				Key:         id,
+				Subject:     pattern.Name,
+				Current:     after,
+				Description: fmt.Sprintf("matched %d times, never in the baseline", pattern.Count),
+			})
+			addedCounts = append(addedCounts, pattern.Count)
+			continue
+		}
+		beforeRate := float64(before.Count) * rates.baseline
+		if rateChanged(beforeRate, after, before.Count, pattern.Count, options) {
+			changed = append(changed, patternRateChange(id, pattern.Name, beforeRate, after, rates.unit))
+		}
+	}
+	// Patterns that stopped matching
+	for id, pattern := range baseline.Patterns {
+		if _, seen := current.Patterns[id]; seen {
+			continue
+		}
+		beforeRate := float64(pattern.Count) * rates.baseline
+		if rateChanged(beforeRate, 0, pattern.Count, 0, options) {
+			changed = append(changed, patternRateChange(id, pattern.Name, beforeRate, 0, rates.unit))
+		}
+	}
+
+	sortChanges(added, func(i int) float64 { return added[i].Current })
+	sortChanges(changed, func(i int) float64 { return rateFactor(changed[i].Baseline, changed[i].Current) })
+	return append(limitChanges(added, options.Limit), limitChanges(changed, options.Limit)...)
+}
+
+func patternRateChange(id, name string, before, after float64, unit string) common.BaselineChange {
+	return common.BaselineChange{
+		Kind:        common.BaselineChangePatternRate,
+		Key:         id,
+		Subject:     name,
+		Baseline:    before,
+		Current:     after,
+		Description: describeRateChange(before, after, unit),
+	}
+}
+
+// compareErrorSignatures reports errors whose signature never occurred in
+// the baseline. A baseline with capped signatures cannot tell, so nothing
+// is reported against it.
+func compareErrorSignatures(baseline, current *common.AnalysisProfile, options DiffOptions) []common.BaselineChange {
+	if baseline.ErrorSignatures == nil || current.ErrorSignatures == nil || baseline.SignaturesCapped {
+		return nil
+	}
+	var changes []common.BaselineChange
+	for key, signature := range current.ErrorSignatures {
+		if _, seen := baseline.ErrorSignatures[key]; seen {
+			continue
+		}
+		changes = append(changes, common.BaselineChange{
+			Kind:        common.BaselineChangeNewError,
+			Key:         key,
+			Subject:     signature.Example,
+			Current:     float64(signature.Count),
+			Description: fmt.Sprintf("%d times, never in the baseline", signature.Count),
+		})
+	}
+	sortChanges(changes, func(i int) float64 { return changes[i].Current })
+	return limitChanges(changes, options.Limit)
+}
+
+// compareServices reports services whose share of error entries shifted
+func compareServices(baseline, current *common.AnalysisProfile, options DiffOptions) []common.BaselineChange {
+	if baseline.Services == nil || current.Services == nil {
+		return nil
+	}
+	var changes []common.BaselineChange
+	for name, service := range current.Services {
+		before, seen := baseline.Services[name]
+		if !seen || before.Entries < options.MinCount || service.Entries < options.MinCount {
+			continue
+		}
+		beforeRatio := float64(before.Errors) / float64(before.Entries)
+		afterRatio := float64(service.Errors) / float64(service.Entries)
+		if math.Abs(afterRatio-beforeRatio) < options.ErrorRatioShift {
+			continue
+		}
+		direction := "rose"
+		if afterRatio < beforeRatio {
+			direction = "fell"
+		}
+		changes = append(changes, common.BaselineChange{
+			Kind:     common.BaselineChangeServiceErrors,
+			Key:      name,
+			Subject:  name,
+			Baseline: beforeRatio,
+			Current:  afterRatio,
+			Description: fmt.Sprintf("errors %s from %.1f%% to %.1f%% of entries",
+				direction, beforeRatio*100, afterRatio*100),
+		})
+	}
+	sortChanges(changes, func(i int) float64 { return math.Abs(changes[i].Current - changes[i].Baseline) })
+	return limitChanges(changes, options.Limit)
+}
+
+// rateChanged reports whether a rate rose or fell by at least the
+// configured factor, given enough occurrences to tell
+func rateChanged(before, after float64, beforeCount, afterCount int, options DiffOptions) bool {
+	if beforeCount < options.MinCount && afterCount < options.MinCount {
+		return false
+	}
+	return rateFactor(before, after) >= options.RateChange
+}
+
+// rateFactor returns how many times larger the larger of two rates is,
+// infinite when one of them is zero
+func rateFactor(before, after float64) float64 {
+	low, high := math.Min(before, after), math.Max(before, after)
+	if low == 0 {
+		if high == 0 {
+			return 1
+		}
+		return math.Inf(1)
+	}
+	return high / low
+}
+
+func describeRateChange(before, after float64, unit string) string {
+	switch {
+	case after == 0:
+		return fmt.Sprintf("stopped, from %.1f %s", before, unit)
+	case after > before:
+		return fmt.Sprintf("rose from %.1f to %.1f %s (%.1fx)", before, after, unit, after/before)
+	default:
+		return fmt.Sprintf("fell from %.1f to %.1f %s (%.1fx)", before, after, unit, after/before)
+	}
+}
+
+// sortChanges orders changes by magnitude, largest first, then by key
+func sortChanges(changes []common.BaselineChange, magnitude func(int) float64) {
+	magnitudes := make(map[string]float64, len(changes))
+	for i := range changes {
+		magnitudes[changes[i].Key] = magnitude(i)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		mi, mj := magnitudes[changes[i].Key], magnitudes[changes[j].Key]
+		if mi != mj {
+			return mi > mj
+		}
+		return changes[i].Key < changes[j].Key
+	})
+}
+
+func limitChanges(changes []common.BaselineChange, limit int) []common.BaselineChange {
+	if limit > 0 && len(changes) > limit {
+		return changes[:limit]
+	}
+	return changes
+}
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// baselineEntries returns ten minutes of api and billing traffic read
+// from source, with the given number of api timeouts and declined billing
+// payments each minute
+func baselineEntries(source string, timeouts, declined int) []*common.LogEntry {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	var entries []*common.LogEntry
+	add := func(at time.Time, service string, level common.LogLevel, message string) {
+		entry := createTestEntry(at, level, level.String(), message)
+		entry.Service = service
+		entry.Source = source
+		entries = append(entries, entry)
+	}
+	for minute := 0; minute < 10; minute++ {
+		start := base.Add(time.Duration(minute) * time.Minute)
+		for i := 0; i < 10; i++ {
+			add(start.Add(time.Duration(i)*time.Second), "api", common.LevelInfo, "request served")
+			add(start.Add(time.Duration(i)*time.Second), "billing", common.LevelInfo, "invoice created")
+		}
+		for i := 0; i < timeouts; i++ {
+			add(start.Add(20*time.Second), "api", common.LevelError, "upstream timeout after 30s")
+		}
+		for i := 0; i < declined; i++ {
+			add(start.Add(40*time.Second), "billing", common.LevelError, "card declined for order 12345")
+		}
+	}
+	return entries
+}
+
+func profileOf(t *testing.T, entries []*common.LogEntry) *common.AnalysisProfile {
+	t.Helper()
+	engine := NewEngine()
+	patterns := []*common.Pattern{
+		{ID: "timeout", Name: "Timeout", Type: common.PatternTypeError, Keywords: []string{"timeout"}},
+		{ID: "declined", Name: "Card Declined", Type: common.PatternTypeError, Keywords: []string{"declined"}},
+	}
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+	engine.SetProfiling(true)
+
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range entries {
+		stream.Add(entry)
+	}
+	if !reflect.DeepEqual(batch.Profile, stream.Analysis().Profile) {
+		t.Fatalf("batch profile %+v differs from stream profile %+v", batch.Profile, stream.Analysis().Profile)
+	}
+	return batch.Profile
+}
+
+func TestCompareProfiles(t *testing.T) {
+	baseline := profileOf(t, baselineEntries("baseline.log", 1, 0))
+	incident := profileOf(t, baselineEntries("incident.log", 5, 2))
+
+	diff := CompareProfiles(baseline, incident, DefaultDiffOptions())
+	if diff.BaselineEntries != 210 || diff.RateUnit != "per minute" {
+		t.Errorf("diff = %d baseline entries %s, want 210 per minute", diff.BaselineEntries, diff.RateUnit)
+	}
+
+	type change struct {
+		kind common.BaselineChangeKind
+		key  string
+	}
+	want := []change{
+		{common.BaselineChangeVolume, "errors"},
+		{common.BaselineChangeNewPattern, "declined"},
+		{common.BaselineChangePatternRate, "timeout"},
+		{common.BaselineChangeNewError, ""}, // the declined signature
+		{common.BaselineChangeServiceErrors, "api"},
+		{common.BaselineChangeServiceErrors, "billing"},
+	}
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("Changes = %+v, want %d changes", diff.Changes, len(want))
+	}
+	for i, w := range want {
+		got := diff.Changes[i]
+		if got.Kind != w.kind || (w.key != "" && got.Key != w.key) {
+			t.Errorf("change %d = %s %s, want %s %s", i, got.Kind, got.Key, w.kind, w.key)
+		}
+	}
+
+	if rate := diff.Changes[2]; rate.Current < 4.5*rate.Baseline {
+		t.Errorf("timeout rate = %v to %v, want about five times higher", rate.Baseline, rate.Current)
+	}
+	if newError := diff.Changes[3]; newError.Subject != "card declined for order 12345" || newError.Current != 20 {
+		t.Errorf("new error = %+v, want 20 declined cards", newError)
+	}
+
+	// Nothing differs from itself, whatever the input file is called
+	if same := CompareProfiles(baseline, profileOf(t, baselineEntries("other.log", 1, 0)), DefaultDiffOptions()); len(same.Changes) != 0 {
+		t.Errorf("Changes = %+v, want none against the same traffic", same.Changes)
+	}
+}
+
+func TestCompareProfilesWithoutTimes(t *testing.T) {
+	untimed := func(count int) *common.AnalysisProfile {
+		return &common.AnalysisProfile{
+			TotalEntries: 1000,
+			ErrorCount:   count,
+			Patterns:     map[string]common.PatternProfile{"timeout": {Name: "Timeout", Count: count}},
+		}
+	}
+
+	diff := CompareProfiles(untimed(10), untimed(40), DefaultDiffOptions())
+	if diff.RateUnit != "per 1000 entries" {
+		t.Errorf("RateUnit = %q, want per 1000 entries", diff.RateUnit)
+	}
+	// Entry volume needs times; errors and patterns compare per entry.
+	// Saved analyses without signatures and services skip those changes.
+	if len(diff.Changes) != 2 || diff.Changes[0].Key != "errors" || diff.Changes[1].Key != "timeout" {
+		t.Errorf("Changes = %+v, want the error and timeout rates", diff.Changes)
+	}
+
+	options := DefaultDiffOptions()
+	options.RateChange = 5
+	if diff := CompareProfiles(untimed(10), untimed(40), options); len(diff.Changes) != 0 {
+		t.Errorf("Changes = %+v, want none below a 5x change", diff.Changes)
+	}
+}
+
+func TestProfileCapsErrorSignatures(t *testing.T) {
+	builder := newProfileBuilder()
+	builder.maxSignatures = 3
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, message := range []string{"disk full", "card declined", "disk full", "upstream timeout", "queue overflow"} {
+		builder.add(createTestEntry(at, common.LevelError, "ERROR", message))
+	}
+
+	baseline := builder.profile(&Analysis{})
+	if len(baseline.ErrorSignatures) != 3 || !baseline.SignaturesCapped {
+		t.Fatalf("profile has %d signatures (capped %v), want 3 capped", len(baseline.ErrorSignatures), baseline.SignaturesCapped)
+	}
+	for _, signature := range baseline.ErrorSignatures {
+		if signature.Example == "queue overflow" {
+			t.Errorf("signature past the cap was counted: %+v", signature)
+		}
+		if signature.Example == "disk full" && signature.Count != 2 {
+			t.Errorf("disk full count = %d, want 2", signature.Count)
+		}
+	}
+
+	// A signature missing from a capped baseline may still have occurred
+	current := &common.AnalysisProfile{ErrorSignatures: map[string]common.SignatureProfile{"unseen": {Count: 50, Example: "unseen"}}}
+	if changes := compareErrorSignatures(baseline, current, DefaultDiffOptions()); len(changes) != 0 {
+		t.Errorf("compareErrorSignatures() = %+v, want none against a capped baseline", changes)
+	}
+}
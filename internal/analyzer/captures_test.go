@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func TestNamedCaptures(t *testing.T) {
+	entries := append(burstEntries("connection refused by db-1:5432", 0, 10, 20),
+		burstEntries("connection refused by db-2:6432", 5)...)
+	entries[0].Metadata = map[string]string{"host": "parsed-host"}
+
+	engine := NewEngine()
+	patterns := []*common.Pattern{{
+		ID: "refused", Name: "Refused", Type: common.PatternTypeError,
+		Regex: `connection refused by (?P<host>[\w-]+):(?P<port>\d+)`,
+	}}
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	if got := entries[1].Metadata; got["host"] != "db-1" || got["port"] != "5432" {
+		t.Errorf("metadata = %v, want host db-1 and port 5432", got)
+	}
+	if got := entries[0].Metadata["host"]; got != "parsed-host" {
+		t.Errorf("metadata host = %q, want the parsed value kept", got)
+	}
+
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range entries {
+		stream.Add(entry)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		if len(analysis.Patterns) != 1 {
+			t.Fatalf("%s: got %d patterns, want 1", name, len(analysis.Patterns))
+		}
+		captures := analysis.Patterns[0].Captures
+		// Counted from the regex, not from the metadata the parser set
+		if hosts := captures["host"]; hosts["db-1"] != 3 || hosts["db-2"] != 1 || len(hosts) != 2 {
+			t.Errorf("%s: host captures = %v, want db-1 three times and db-2 once", name, hosts)
+		}
+		if ports := captures["port"]; ports["5432"] != 3 || ports["6432"] != 1 {
+			t.Errorf("%s: port captures = %v, want 5432 three times and 6432 once", name, ports)
+		}
+	}
+}
+
+func TestCapturesOfSeveralPatterns(t *testing.T) {
+	engine := NewEngine()
+	patterns := []*common.Pattern{
+		{
+			ID: "refused", Name: "Refused", Type: common.PatternTypeError,
+			Regex: `connection refused by (?P<host>[\w-]+)`,
+		},
+		{
+			ID: "upstream", Name: "Upstream", Type: common.PatternTypeError,
+			Regex:   `upstream (?P<host>[\w-]+)`,
+			Exclude: []string{"upstream canary"},
+		},
+	}
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+
+	newEntries := func() []*common.LogEntry {
+		return append(burstEntries("connection refused by db-1 via upstream proxy-1", 0, 10),
+			burstEntries("timeout from upstream canary", 20)...)
+	}
+	batchEntries, streamEntries := newEntries(), newEntries()
+
+	batch, err := engine.Analyze(context.Background(), batchEntries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range streamEntries {
+		stream.Add(entry)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		captures := map[string]map[string]int{}
+		for _, match := range analysis.Patterns {
+			captures[match.Pattern.ID] = match.Captures["host"]
+		}
+		// Each pattern counts its own capture, whichever set the metadata
+		if hosts := captures["refused"]; hosts["db-1"] != 2 || len(hosts) != 1 {
+			t.Errorf("%s: refused hosts = %v, want db-1 twice", name, hosts)
+		}
+		if hosts := captures["upstream"]; hosts["proxy-1"] != 2 || len(hosts) != 1 {
+			t.Errorf("%s: upstream hosts = %v, want proxy-1 twice", name, hosts)
+		}
+	}
+
+	for name, entries := range map[string][]*common.LogEntry{"batch": batchEntries, "stream": streamEntries} {
+		if got := entries[0].Metadata["host"]; got != "db-1" {
+			t.Errorf("%s: metadata host = %q, want db-1 from the first pattern", name, got)
+		}
+		// An excluded match leaves the entry unchanged
+		if got, ok := entries[2].Metadata["host"]; ok {
+			t.Errorf("%s: metadata host = %q on an excluded match, want none", name, got)
+		}
+	}
+}
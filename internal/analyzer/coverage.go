@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yildizm/LogSum/internal/common"
+	corrpkg "github.com/yildizm/LogSum/internal/correlation"
+)
+
+// numberRegex matches the numbers message normalization leaves in place,
+// such as status codes and counts
+var numberRegex = regexp.MustCompile(`\b\d+\b`)
+
+// CoverageReport describes how much of a log the engine's patterns explain
+type CoverageReport struct {
+	Entries   int                `json:"entries"`   // entries read
+	Problems  int                `json:"problems"`  // WARN, ERROR and FATAL entries
+	Matched   int                `json:"matched"`   // problems matched by at least one pattern
+	Coverage  float64            `json:"coverage"`  // share of problems matched, 1 when there are none
+	Unmatched []UnmatchedMessage `json:"unmatched"` // most frequent unmatched signatures
+	Unfired   []*common.Pattern  `json:"unfired"`   // patterns that matched no entry
+}
+
+// UnmatchedMessage groups unmatched problem entries by normalized message
+type UnmatchedMessage struct {
+	Signature string           `json:"signature"`
+	Count     int              `json:"count"`
+	Level     common.LogLevel  `json:"level"`
+	Example   *common.LogEntry `json:"example"`
+}
+
+// Coverage measures pattern coverage over a stream of entries
+type Coverage struct {
+	engine    *AnalyzerEngine
+	limit     int
+	entries   int
+	problems  int
+	matched   int
+	fired     map[string]bool
+	unmatched map[string]*UnmatchedMessage
+}
+
+// NewCoverage creates a coverage measure that reports up to limit unmatched
+// signatures, or all of them when limit is 0
+func (e *AnalyzerEngine) NewCoverage(limit int) *Coverage {
+	return &Coverage{
+		engine:    e,
+		limit:     limit,
+		fired:     make(map[string]bool),
+		unmatched: make(map[string]*UnmatchedMessage),
+	}
+}
+
+// Add matches an entry against the engine's patterns
+func (c *Coverage) Add(entry *common.LogEntry) {
+	c.entries++
+	// Matches silenced by suppression rules are known, so they count as
+	// covered
+	matched, suppressed := c.engine.matcher.matchSingle(entry)
+	for _, id := range matched {
+		c.fired[id] = true
+	}
+	for _, key := range suppressed {
+		c.fired[key.pattern] = true
+	}
+
+	if entry.LogLevel < common.LevelWarn {
+		return
+	}
+	c.problems++
+	if len(matched) > 0 || len(suppressed) > 0 {
+		c.matched++
+		return
+	}
+
+	message, _, _ := strings.Cut(entry.Message, "\n")
+	signature := numberRegex.ReplaceAllString(corrpkg.NormalizeMessage(message), "<NUM>")
+	group, ok := c.unmatched[signature]
+	if !ok {
+		group = &UnmatchedMessage{Signature: signature, Example: entry}
+		c.unmatched[signature] = group
+	}
+	group.Count++
+	if entry.LogLevel > group.Level {
+		group.Level = entry.LogLevel
+	}
+}
+
+// Report returns the coverage of the entries added so far
+func (c *Coverage) Report() *CoverageReport {
+	report := &CoverageReport{
+		Entries:   c.entries,
+		Problems:  c.problems,
+		Matched:   c.matched,
+		Coverage:  1,
+		Unmatched: make([]UnmatchedMessage, 0, len(c.unmatched)),
+		Unfired:   []*common.Pattern{},
+	}
+	if c.problems > 0 {
+		report.Coverage = float64(c.matched) / float64(c.problems)
+	}
+
+	for _, group := range c.unmatched {
+		report.Unmatched = append(report.Unmatched, *group)
+	}
+	sort.Slice(report.Unmatched, func(i, j int) bool {
+		a, b := report.Unmatched[i], report.Unmatched[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Signature < b.Signature
+	})
+	if c.limit > 0 && len(report.Unmatched) > c.limit {
+		report.Unmatched = report.Unmatched[:c.limit]
+	}
+
+	for _, pattern := range c.engine.patterns {
+		if !c.fired[pattern.ID] {
+			report.Unfired = append(report.Unfired, pattern)
+		}
+	}
+	return report
+}
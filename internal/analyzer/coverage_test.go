@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func TestCoverageReport(t *testing.T) {
+	entries := templateEntries(
+		"connection refused by db-1",
+		"payment gateway returned status 502 for order 1001",
+		"payment gateway returned status 503 for order 1002",
+		"ledger checksum mismatch",
+		"cache warmed",
+		"connection refused by db-2",
+	)
+	for _, entry := range entries[:4] {
+		entry.LogLevel = common.LevelError
+	}
+	entries[3].LogLevel = common.LevelWarn
+
+	engine := NewEngine()
+	patterns := []*common.Pattern{
+		{ID: "conn", Name: "Connection", Type: common.PatternTypeError, Regex: "connection refused"},
+		{ID: "disk", Name: "Disk", Type: common.PatternTypeError, Keywords: []string{"disk full"}},
+	}
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+
+	coverage := engine.NewCoverage(1)
+	for _, entry := range entries {
+		coverage.Add(entry)
+	}
+	report := coverage.Report()
+
+	if report.Entries != 6 || report.Problems != 4 || report.Matched != 1 || report.Coverage != 0.25 {
+		t.Errorf("report = %d entries, %d problems, %d matched, %.2f coverage, want 6, 4, 1, 0.25",
+			report.Entries, report.Problems, report.Matched, report.Coverage)
+	}
+	if len(report.Unmatched) != 1 {
+		t.Fatalf("got %d unmatched signatures, want 1", len(report.Unmatched))
+	}
+	if got := report.Unmatched[0]; got.Signature != "payment gateway returned status <NUM> for order <ID>" || got.Count != 2 {
+		t.Errorf("top unmatched = %q (%d), want the gateway signature seen twice", got.Signature, got.Count)
+	}
+	if len(report.Unfired) != 1 || report.Unfired[0].ID != "disk" {
+		t.Errorf("unfired = %v, want only disk", report.Unfired)
+	}
+
+	empty := engine.NewCoverage(0).Report()
+	if empty.Coverage != 1 || len(empty.Unfired) != 2 {
+		t.Errorf("empty report = %.2f coverage, %d unfired, want 1 and 2", empty.Coverage, len(empty.Unfired))
+	}
+}
@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// InsightDetector finds one kind of insight. For every analysis the engine
+// starts a fresh run of each detector, feeds it the entries and pattern
+// matches, and then collects the insights it reports.
+type InsightDetector interface {
+	// Name identifies the detector; names are unique within an engine
+	Name() string
+
+	// NewRun starts a run over one analysis
+	NewRun(tuning InsightTuning) DetectorRun
+}
+
+// DetectorRun is the state of one detector over one analysis. Streams feed
+// it one entry at a time, so a run should keep counts and bounded evidence
+// rather than every entry.
+type DetectorRun interface {
+	// Add records an entry
+	Add(entry *common.LogEntry)
+
+	// AddMatch records an entry counted as a match of a pattern. Matches
+	// completing a sequence or threshold may refer to earlier entries.
+	AddMatch(patternID string, entry *common.LogEntry)
+
+	// Insights reports what the run has found. Streams may ask for
+	// insights again as more entries arrive.
+	Insights(summary InsightSummary) []common.Insight
+}
+
+// InsightSummary is the analysis the detectors report on
+type InsightSummary struct {
+	TotalEntries int
+	Patterns     []PatternMatch
+}
+
+// InsightTuning holds the settings every detector shares
+type InsightTuning struct {
+	EvidenceLimit int                   // evidence entries kept per insight
+	Tags          map[string]TagOptions // per-tag tuning of pattern insights
+}
+
+// ForPattern resolves the tag options of a pattern. When a pattern has
+// several tuned tags, the first one setting a value wins, and any of them
+// can disable insights about it.
+func (t InsightTuning) ForPattern(pattern *common.Pattern) TagOptions {
+	var resolved TagOptions
+	for _, tag := range pattern.Tags {
+		options, ok := t.Tags[tag]
+		if !ok {
+			continue
+		}
+		resolved.Disabled = resolved.Disabled || options.Disabled
+		if resolved.MinConfidence == 0 {
+			resolved.MinConfidence = options.MinConfidence
+		}
+		if resolved.Sensitivity == 0 {
+			resolved.Sensitivity = options.Sensitivity
+		}
+		if resolved.CorrelationThreshold == 0 {
+			resolved.CorrelationThreshold = options.CorrelationThreshold
+		}
+	}
+	return resolved
+}
+
+// Reports reports whether an insight about a pattern is wanted at
+// confidence, given the options of the pattern's tags
+func (t InsightTuning) Reports(pattern *common.Pattern, confidence float64) bool {
+	tagged := t.ForPattern(pattern)
+	return !tagged.Disabled && confidence >= tagged.MinConfidence
+}
+
+// NewMatchDetector creates a detector that only looks at the pattern
+// matches of an analysis, not at individual entries
+func NewMatchDetector(name string, detect func(summary InsightSummary, tuning InsightTuning) []common.Insight) InsightDetector {
+	return &matchDetector{name: name, detect: detect}
+}
+
+type matchDetector struct {
+	name   string
+	detect func(InsightSummary, InsightTuning) []common.Insight
+}
+
+func (d *matchDetector) Name() string { return d.name }
+
+func (d *matchDetector) NewRun(tuning InsightTuning) DetectorRun {
+	return &matchRun{detector: d, tuning: tuning}
+}
+
+type matchRun struct {
+	detector *matchDetector
+	tuning   InsightTuning
+}
+
+func (r *matchRun) Add(*common.LogEntry) {}
+
+func (r *matchRun) AddMatch(string, *common.LogEntry) {}
+
+func (r *matchRun) Insights(summary InsightSummary) []common.Insight {
+	return r.detector.detect(summary, r.tuning)
+}
+
+// insightRun feeds one analysis to a run of every active detector
+type insightRun struct {
+	minConfidence float64
+	totalEntries  int
+	runs          []DetectorRun
+}
+
+// add records a single entry
+func (r *insightRun) add(entry *common.LogEntry) {
+	r.totalEntries++
+	for _, run := range r.runs {
+		run.Add(entry)
+	}
+}
+
+// addMatch records an entry matched by a pattern
+func (r *insightRun) addMatch(patternID string, entry *common.LogEntry) {
+	for _, run := range r.runs {
+		run.AddMatch(patternID, entry)
+	}
+}
+
+// insights collects the confident insights of every run, most confident
+// first
+func (r *insightRun) insights(matches []PatternMatch) []Insight {
+	var insights []Insight
+	if r.totalEntries == 0 {
+		return insights
+	}
+
+	summary := InsightSummary{TotalEntries: r.totalEntries, Patterns: matches}
+	for _, run := range r.runs {
+		for _, insight := range run.Insights(summary) {
+			if insight.Confidence >= r.minConfidence {
+				insights = append(insights, insight)
+			}
+		}
+	}
+
+	// Sort insights by confidence (highest first)
+	sort.SliceStable(insights, func(i, j int) bool {
+		return insights[i].Confidence > insights[j].Confidence
+	})
+
+	return insights
+}
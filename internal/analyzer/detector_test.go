@@ -0,0 +1,152 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// retryDetector reports when more than Limit entries mention a retry
+type retryDetector struct {
+	Limit int
+}
+
+func (d *retryDetector) Name() string { return "retries" }
+
+func (d *retryDetector) NewRun(tuning InsightTuning) DetectorRun {
+	return &retryRun{limit: d.Limit, evidenceLimit: tuning.EvidenceLimit}
+}
+
+type retryRun struct {
+	limit, evidenceLimit int
+	count                int
+	evidence             []*common.LogEntry
+}
+
+func (r *retryRun) Add(entry *common.LogEntry) {
+	if strings.Contains(entry.Message, "retry") {
+		r.count++
+		if len(r.evidence) < r.evidenceLimit {
+			r.evidence = append(r.evidence, entry)
+		}
+	}
+}
+
+func (r *retryRun) AddMatch(string, *common.LogEntry) {}
+
+func (r *retryRun) Insights(InsightSummary) []common.Insight {
+	if r.count <= r.limit {
+		return nil
+	}
+	return []common.Insight{{
+		Type:        "retries",
+		Severity:    common.LevelWarn,
+		Title:       "Payment Retries",
+		Description: fmt.Sprintf("%d retries, more than %d", r.count, r.limit),
+		Evidence:    r.evidence,
+		Confidence:  0.9,
+	}}
+}
+
+func TestRegisterDetector(t *testing.T) {
+	entries := spikeEntries()
+	for i := 0; i < 4; i++ {
+		entries = append(entries, createTestEntry(entries[i].Timestamp, common.LevelWarn, "WARN", "payment retry"))
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  bool
+	}{
+		{"over the limit", 3, true},
+		{"at the limit", 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine()
+			if err := engine.RegisterDetector(&retryDetector{Limit: tt.limit}); err != nil {
+				t.Fatalf("RegisterDetector() failed: %v", err)
+			}
+
+			batch, err := engine.Analyze(context.Background(), entries)
+			if err != nil {
+				t.Fatalf("Analyze() failed: %v", err)
+			}
+			stream := engine.NewStream(DefaultStreamOptions())
+			for _, entry := range entries {
+				stream.Add(entry)
+			}
+
+			for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+				var retries *Insight
+				for i := range analysis.Insights {
+					if analysis.Insights[i].Type == "retries" {
+						retries = &analysis.Insights[i]
+					}
+				}
+				if (retries != nil) != tt.want {
+					t.Fatalf("%s: retry insight = %+v, want reported %v", name, retries, tt.want)
+				}
+				if retries != nil && len(retries.Evidence) != 4 {
+					t.Errorf("%s: retry evidence = %d entries, want 4", name, len(retries.Evidence))
+				}
+				for i := 1; i < len(analysis.Insights); i++ {
+					if analysis.Insights[i].Confidence > analysis.Insights[i-1].Confidence {
+						t.Errorf("%s: insights are not sorted by confidence", name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterDetectorNames(t *testing.T) {
+	engine := NewEngine()
+	if err := engine.RegisterDetector(&retryDetector{}); err != nil {
+		t.Fatalf("RegisterDetector() failed: %v", err)
+	}
+
+	invalid := []InsightDetector{
+		&retryDetector{Limit: 1},
+		NewMatchDetector("", nil),
+		NewMatchDetector("statistical", nil),
+	}
+	for _, detector := range invalid {
+		if err := engine.RegisterDetector(detector); err == nil {
+			t.Errorf("RegisterDetector(%q) succeeded, want error", detector.Name())
+		}
+	}
+
+	// Custom detectors survive option changes and are dropped by the
+	// min confidence filter like built-in ones
+	options := DefaultInsightOptions()
+	options.MinConfidence = 0.95
+	if err := engine.SetInsightOptions(options); err != nil {
+		t.Fatalf("SetInsightOptions() failed: %v", err)
+	}
+	entries := []*common.LogEntry{createTestEntry(spikeEntries()[0].Timestamp, common.LevelWarn, "WARN", "payment retry")}
+	analysis, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	if len(analysis.Insights) != 0 {
+		t.Errorf("insights = %+v, want none above 0.95 confidence", analysis.Insights)
+	}
+
+	options.MinConfidence = 0
+	if err := engine.SetInsightOptions(options); err != nil {
+		t.Fatalf("SetInsightOptions() failed: %v", err)
+	}
+	analysis, err = engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	if len(analysis.Insights) != 1 || analysis.Insights[0].Type != "retries" {
+		t.Errorf("insights = %+v, want the retry insight", analysis.Insights)
+	}
+}
@@ -15,7 +15,10 @@ type AnalyzerEngine struct {
 	insightGen         *InsightGenerator
 	timelineGen        *TimelineGenerator
 	timelineBucketSize time.Duration
+	timelineMaxBuckets int
 	enableInsights     bool
+	profiling          bool
+	templateOptions    TemplateOptions
 }
 
 func NewEngine() *AnalyzerEngine {
@@ -26,6 +29,7 @@ func NewEngine() *AnalyzerEngine {
 		timelineGen:        NewTimelineGenerator(),
 		timelineBucketSize: 5 * time.Minute, // Default 5-minute buckets
 		enableInsights:     true,
+		templateOptions:    DefaultTemplateOptions(),
 	}
 }
 
@@ -66,16 +70,27 @@ func (e *AnalyzerEngine) Analyze(ctx context.Context, entries []*common.LogEntry
 
 	// Pattern matching
 	if len(e.patterns) > 0 {
-		matches, err := e.matcher.MatchPatterns(ctx, e.patterns, sortedEntries)
+		matches, suppressed, err := e.matcher.matchPatterns(ctx, sortedEntries)
 		if err != nil {
 			return analysis, err
 		}
+		matches = e.applyThresholds(matches)
+		matches = append(matches, e.matchSequences(sortedEntries, suppressed)...)
 		analysis.Patterns = matches
+		analysis.Suppressed = suppressed.list()
 
 		// Update error/warning counts based on pattern matches
 		e.updateCountsFromPatterns(analysis, matches)
 	}
 
+	// Per-source breakdown when entries come from several files
+	analysis.Sources = e.breakdownBySource(sortedEntries, analysis.Patterns)
+
+	// Cluster the entries no pattern explains into message templates
+	if e.templateOptions.Limit > 0 {
+		analysis.Templates = e.mineTemplates(sortedEntries, analysis.Patterns)
+	}
+
 	// Check for context cancellation
 	select {
 	case <-ctx.Done():
@@ -98,10 +113,19 @@ func (e *AnalyzerEngine) Analyze(ctx context.Context, entries []*common.LogEntry
 
 	// Timeline analysis
 	if e.timelineBucketSize > 0 {
-		timeline := e.timelineGen.GenerateTimeline(sortedEntries, e.timelineBucketSize)
+		bucketSize := cappedBucketSize(e.timelineBucketSize, analysis.StartTime, analysis.EndTime, e.timelineMaxBuckets)
+		timeline := e.timelineGen.GenerateTimeline(sortedEntries, bucketSize)
 		analysis.Timeline = timeline
 	}
 
+	if e.profiling {
+		profile := newProfileBuilder()
+		for _, entry := range sortedEntries {
+			profile.add(entry)
+		}
+		analysis.Profile = profile.profile(analysis)
+	}
+
 	return analysis, nil
 }
 
@@ -148,8 +172,14 @@ func (e *AnalyzerEngine) calculateBasicStats(analysis *Analysis, entries []*comm
 		return
 	}
 
-	// Find time range
-	analysis.StartTime = entries[0].Timestamp
+	// Find time range. Entries are sorted, so those without a timestamp
+	// come first and are left out of it.
+	for _, entry := range entries {
+		if !entry.Timestamp.IsZero() {
+			analysis.StartTime = entry.Timestamp
+			break
+		}
+	}
 	analysis.EndTime = entries[len(entries)-1].Timestamp
 
 	// Count by severity level
@@ -168,6 +198,46 @@ func (e *AnalyzerEngine) GetPatterns() []*common.Pattern {
 	return e.patterns
 }
 
+// SetTemplateOptions configures template mining. A zero Limit disables it.
+func (e *AnalyzerEngine) SetTemplateOptions(options TemplateOptions) {
+	e.templateOptions = options
+}
+
+// SetInsightOptions configures the insight detectors
+func (e *AnalyzerEngine) SetInsightOptions(options InsightOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	e.insightGen.options = options
+	return nil
+}
+
+// RegisterDetector adds a custom insight detector, run after the built-in
+// ones on every analysis and stream started afterwards
+func (e *AnalyzerEngine) RegisterDetector(detector InsightDetector) error {
+	return e.insightGen.RegisterDetector(detector)
+}
+
+// SetAnomalyOptions configures statistical anomaly detection, leaving the
+// other detectors as they are
+func (e *AnalyzerEngine) SetAnomalyOptions(options AnomalyOptions) error {
+	insightOptions := e.insightGen.options
+	insightOptions.Statistical = options
+	return e.SetInsightOptions(insightOptions)
+}
+
+// SetProfiling sets whether analyses include a profile, the counts a
+// later analysis can be compared against with CompareProfiles
+func (e *AnalyzerEngine) SetProfiling(enabled bool) {
+	e.profiling = enabled
+}
+
+// SetTimelineMaxBuckets caps the buckets of the timeline. Longer timelines
+// use proportionally wider buckets; 0 leaves the bucket size as set.
+func (e *AnalyzerEngine) SetTimelineMaxBuckets(n int) {
+	e.timelineMaxBuckets = n
+}
+
 // SetTimelineBucketSize sets the timeline bucket size
 func (e *AnalyzerEngine) SetTimelineBucketSize(size time.Duration) {
 	e.timelineBucketSize = size
@@ -202,3 +272,41 @@ func (e *AnalyzerEngine) updateCountsFromPatterns(analysis *Analysis, matches []
 	analysis.ErrorCount += len(errorEntries)
 	analysis.WarnCount += len(warningEntries)
 }
+
+// mineTemplates clusters the messages of entries that matched no pattern
+func (e *AnalyzerEngine) mineTemplates(entries []*common.LogEntry, matches []PatternMatch) []common.LogTemplate {
+	matched := make(map[*common.LogEntry]bool)
+	for _, match := range matches {
+		for _, entry := range match.Matches {
+			matched[entry] = true
+		}
+	}
+
+	miner := NewTemplateMiner(e.templateOptions)
+	for _, entry := range entries {
+		if !matched[entry] {
+			miner.Add(entry)
+		}
+	}
+	return miner.Templates()
+}
+
+// breakdownBySource computes per-source entry, error and pattern counts
+func (e *AnalyzerEngine) breakdownBySource(entries []*common.LogEntry, matches []PatternMatch) []common.SourceSummary {
+	matchedIDs := make(map[*common.LogEntry][]string)
+	patternsByID := make(map[string]*common.Pattern, len(matches))
+	for _, match := range matches {
+		patternsByID[match.Pattern.ID] = match.Pattern
+		for _, entry := range match.Matches {
+			matchedIDs[entry] = append(matchedIDs[entry], match.Pattern.ID)
+		}
+	}
+
+	breakdown := newSourceBreakdown()
+	for _, entry := range entries {
+		ids := matchedIDs[entry]
+		errors, warns := severityCounts(entry, ids, patternsByID)
+		breakdown.add(entry, ids, errors, warns)
+	}
+	return breakdown.summaries()
+}
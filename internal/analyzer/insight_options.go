@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+)
+
+// InsightOptions configures the insight detectors
+type InsightOptions struct {
+	MinConfidence float64 // insights below this confidence are dropped
+	EvidenceLimit int     // evidence entries kept per insight
+
+	ErrorSpikes ErrorSpikeOptions
+	Performance PerformanceOptions
+	Anomalies   AnomalyRuleOptions
+	Statistical AnomalyOptions
+	RootCauses  RootCauseOptions
+
+	// Tags tunes the detectors for patterns carrying a tag. When a pattern
+	// has several tuned tags, the first one setting a value wins, and any
+	// of them can disable insights about it.
+	Tags map[string]TagOptions
+}
+
+// ErrorSpikeOptions configures error spike detection
+type ErrorSpikeOptions struct {
+	Enabled    bool
+	Threshold  float64       // error rate of a window over the average of the two before it
+	Window     time.Duration // width of the compared windows
+	MinEntries int           // entries needed before spikes are looked for
+}
+
+// PerformanceOptions configures performance issue detection
+type PerformanceOptions struct {
+	Enabled      bool
+	SlowKeywords []string // message keywords that mark slow responses
+}
+
+// AnomalyRuleOptions configures anomaly patterns and service error rates
+type AnomalyRuleOptions struct {
+	Enabled           bool
+	ServiceErrorRate  float64 // share of a service's entries that are errors
+	ServiceMinEntries int     // entries a service needs before its rate counts
+}
+
+// RootCauseOptions configures root cause correlation
+type RootCauseOptions struct {
+	Enabled              bool
+	CorrelationThreshold float64 // time overlap of two error patterns
+}
+
+// TagOptions tunes the detectors for patterns with a tag. Zero values keep
+// the general setting.
+type TagOptions struct {
+	Disabled             bool    // no insights about these patterns
+	MinConfidence        float64 // pattern insights below this confidence are dropped
+	Sensitivity          float64 // statistical anomaly sensitivity of their series
+	CorrelationThreshold float64 // root cause correlation needed when either pattern has the tag
+}
+
+// DefaultInsightOptions returns the default detector settings
+func DefaultInsightOptions() InsightOptions {
+	return InsightOptions{
+		EvidenceLimit: 5,
+		ErrorSpikes: ErrorSpikeOptions{
+			Enabled:    true,
+			Threshold:  2.0, // 2x increase in errors
+			Window:     5 * time.Minute,
+			MinEntries: 10,
+		},
+		Performance: PerformanceOptions{
+			Enabled:      true,
+			SlowKeywords: []string{"slow", "timeout", "taking too long", "high latency", "response time"},
+		},
+		Anomalies: AnomalyRuleOptions{
+			Enabled:           true,
+			ServiceErrorRate:  0.5,
+			ServiceMinEntries: 10,
+		},
+		Statistical: DefaultAnomalyOptions(),
+		RootCauses: RootCauseOptions{
+			Enabled:              true,
+			CorrelationThreshold: 0.6, // 60% correlation minimum
+		},
+	}
+}
+
+// Validate checks the options for values the detectors cannot work with
+func (o InsightOptions) Validate() error {
+	if o.MinConfidence < 0 || o.MinConfidence > 1 {
+		return fmt.Errorf("insight min confidence must be between 0 and 1")
+	}
+	if o.EvidenceLimit < 0 {
+		return fmt.Errorf("insight evidence limit must not be negative")
+	}
+	if o.ErrorSpikes.Enabled && (o.ErrorSpikes.Threshold <= 1 || o.ErrorSpikes.Window <= 0) {
+		return fmt.Errorf("error spike threshold must be above 1 and window positive")
+	}
+	if o.Anomalies.Enabled && (o.Anomalies.ServiceErrorRate <= 0 || o.Anomalies.ServiceErrorRate > 1) {
+		return fmt.Errorf("service error rate must be in (0, 1]")
+	}
+	if o.RootCauses.Enabled && (o.RootCauses.CorrelationThreshold <= 0 || o.RootCauses.CorrelationThreshold > 1) {
+		return fmt.Errorf("correlation threshold must be in (0, 1]")
+	}
+	if err := o.Statistical.Validate(); err != nil {
+		return err
+	}
+	for tag, options := range o.Tags {
+		if options.MinConfidence < 0 || options.MinConfidence > 1 || options.Sensitivity < 0 ||
+			options.CorrelationThreshold < 0 || options.CorrelationThreshold > 1 {
+			return fmt.Errorf("insight options of tag %s are out of range", tag)
+		}
+	}
+	return nil
+}
+
+// tuning returns the settings the detectors share
+func (o InsightOptions) tuning() InsightTuning {
+	return InsightTuning{EvidenceLimit: o.EvidenceLimit, Tags: o.Tags}
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// spikeEntries returns 20 minutes of traffic with one timeout a minute,
+// rising to 30 in minutes 12 and 13
+func spikeEntries() []*common.LogEntry {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	var entries []*common.LogEntry
+	for minute := 0; minute < 20; minute++ {
+		for i := 0; i < 10; i++ {
+			at := base.Add(time.Duration(minute)*time.Minute + time.Duration(i)*time.Second)
+			entries = append(entries, createTestEntry(at, common.LevelInfo, "INFO", "request served"))
+		}
+		timeouts := 1
+		if minute == 12 || minute == 13 {
+			timeouts = 30
+		}
+		for i := 0; i < timeouts; i++ {
+			at := base.Add(time.Duration(minute)*time.Minute + 30*time.Second + time.Duration(i)*time.Millisecond)
+			entries = append(entries, createTestEntry(at, common.LevelError, "ERROR", "upstream timeout"))
+		}
+	}
+	return entries
+}
+
+func TestInsightOptions(t *testing.T) {
+	patterns := []*common.Pattern{{
+		ID: "timeout", Name: "Timeout", Type: common.PatternTypePerformance,
+		Keywords: []string{"timeout"}, Tags: []string{"upstream", "network"},
+	}}
+
+	tests := []struct {
+		name    string
+		change  func(*InsightOptions)
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "defaults",
+			change: func(*InsightOptions) {},
+			want:   []string{"Error Spike Detected", "Performance Issue: Timeout", "Volume Anomaly: Timeout", "Error Volume Anomaly"},
+		},
+		{
+			name:    "detectors disabled",
+			change:  func(o *InsightOptions) { o.ErrorSpikes.Enabled, o.Performance.Enabled = false, false },
+			want:    []string{"Volume Anomaly: Timeout"},
+			notWant: []string{"Error Spike Detected", "Performance Issue: Timeout", "Slow Response Times Detected"},
+		},
+		{
+			name:    "tag disabled",
+			change:  func(o *InsightOptions) { o.Tags = map[string]TagOptions{"network": {Disabled: true}} },
+			want:    []string{"Error Volume Anomaly"},
+			notWant: []string{"Performance Issue: Timeout", "Volume Anomaly: Timeout"},
+		},
+		{
+			name: "first tag sensitivity wins",
+			change: func(o *InsightOptions) {
+				o.Tags = map[string]TagOptions{"upstream": {Sensitivity: 100}, "network": {Sensitivity: 1}}
+			},
+			want:    []string{"Error Volume Anomaly"},
+			notWant: []string{"Volume Anomaly: Timeout"},
+		},
+		{
+			name:    "min confidence",
+			change:  func(o *InsightOptions) { o.MinConfidence = 0.99 },
+			notWant: []string{"Error Spike Detected", "Error Volume Anomaly", "Slow Response Times Detected"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine()
+			if err := engine.SetPatterns(patterns); err != nil {
+				t.Fatalf("SetPatterns() failed: %v", err)
+			}
+			options := DefaultInsightOptions()
+			tt.change(&options)
+			if err := engine.SetInsightOptions(options); err != nil {
+				t.Fatalf("SetInsightOptions() failed: %v", err)
+			}
+
+			analysis, err := engine.Analyze(context.Background(), spikeEntries())
+			if err != nil {
+				t.Fatalf("Analyze() failed: %v", err)
+			}
+			titles := make(map[string]bool)
+			for _, insight := range analysis.Insights {
+				titles[insight.Title] = true
+			}
+			for _, title := range tt.want {
+				if !titles[title] {
+					t.Errorf("missing insight %q in %v", title, titles)
+				}
+			}
+			for _, title := range tt.notWant {
+				if titles[title] {
+					t.Errorf("unexpected insight %q", title)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidInsightOptions(t *testing.T) {
+	for _, change := range []func(*InsightOptions){
+		func(o *InsightOptions) { o.MinConfidence = 2 },
+		func(o *InsightOptions) { o.ErrorSpikes.Threshold = 1 },
+		func(o *InsightOptions) { o.RootCauses.CorrelationThreshold = 0 },
+		func(o *InsightOptions) { o.Tags = map[string]TagOptions{"x": {Sensitivity: -1}} },
+	} {
+		options := DefaultInsightOptions()
+		change(&options)
+		if err := NewEngine().SetInsightOptions(options); err == nil {
+			t.Errorf("SetInsightOptions(%+v) succeeded, want error", options)
+		}
+	}
+
+	options := DefaultInsightOptions()
+	options.ErrorSpikes = ErrorSpikeOptions{}
+	if err := NewEngine().SetInsightOptions(options); err != nil {
+		t.Errorf("SetInsightOptions() with a disabled detector failed: %v", err)
+	}
+}
+
+func TestTimelineMaxBuckets(t *testing.T) {
+	entries := spikeEntries()
+	engine := NewEngine()
+	engine.SetTimelineMaxBuckets(3)
+
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range entries {
+		stream.Add(entry)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		timeline := analysis.Timeline
+		if timeline.BucketSize != 10*time.Minute || len(timeline.Buckets) != 2 {
+			t.Fatalf("%s: got %d buckets of %v, want 2 of 10m", name, len(timeline.Buckets), timeline.BucketSize)
+		}
+		total := 0
+		for _, bucket := range timeline.Buckets {
+			total += bucket.EntryCount
+		}
+		if total != len(entries) {
+			t.Errorf("%s: buckets hold %d entries, want %d", name, total, len(entries))
+		}
+	}
+}
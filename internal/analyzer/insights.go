@@ -11,53 +11,158 @@ import (
 
 // InsightGenerator generates insights from log analysis
 type InsightGenerator struct {
-	// Configuration for insight generation
-	errorSpikeThreshold  float64 // Minimum error rate increase for spike detection
-	anomalyConfidence    float64 // Minimum confidence for anomaly detection
-	correlationThreshold float64 // Minimum correlation for root cause analysis
+	options   InsightOptions
+	detectors []InsightDetector // registered alongside the built-in ones
 }
 
-// NewInsightGenerator creates a new insight generator
+// NewInsightGenerator creates a new insight generator with the default options
 func NewInsightGenerator() *InsightGenerator {
-	return &InsightGenerator{
-		errorSpikeThreshold:  2.0, // 2x increase in errors
-		anomalyConfidence:    0.7, // 70% confidence minimum
-		correlationThreshold: 0.6, // 60% correlation minimum
+	return &InsightGenerator{options: DefaultInsightOptions()}
+}
+
+// builtinDetectorNames are the names of the built-in detectors, reserved
+// whether or not they are enabled
+var builtinDetectorNames = []string{"error_spikes", "performance", "anomalies", "statistical", "root_causes"}
+
+// RegisterDetector adds a detector that runs after the built-in ones
+func (g *InsightGenerator) RegisterDetector(detector InsightDetector) error {
+	name := detector.Name()
+	if name == "" {
+		return fmt.Errorf("insight detector has no name")
 	}
+	for _, builtin := range builtinDetectorNames {
+		if name == builtin {
+			return fmt.Errorf("insight detector %s is built in", name)
+		}
+	}
+	for _, registered := range g.detectors {
+		if registered.Name() == name {
+			return fmt.Errorf("insight detector %s is already registered", name)
+		}
+	}
+	g.detectors = append(g.detectors, detector)
+	return nil
+}
+
+// activeDetectors returns the enabled built-in detectors followed by the
+// registered ones
+func (g *InsightGenerator) activeDetectors() []InsightDetector {
+	var detectors []InsightDetector
+	if g.options.ErrorSpikes.Enabled {
+		detectors = append(detectors, NewErrorSpikeDetector(g.options.ErrorSpikes))
+	}
+	if g.options.Performance.Enabled {
+		detectors = append(detectors, NewPerformanceDetector(g.options.Performance))
+	}
+	if g.options.Anomalies.Enabled {
+		detectors = append(detectors, NewAnomalyRuleDetector(g.options.Anomalies))
+	}
+	if g.options.Statistical.Enabled {
+		detectors = append(detectors, NewStatisticalDetector(g.options.Statistical))
+	}
+	if g.options.RootCauses.Enabled {
+		detectors = append(detectors, NewRootCauseDetector(g.options.RootCauses))
+	}
+	return append(detectors, g.detectors...)
 }
 
 // GenerateInsights generates insights from log entries and pattern matches
 func (g *InsightGenerator) GenerateInsights(entries []*common.LogEntry, matches []PatternMatch) []Insight {
-	var insights []Insight
-
 	if len(entries) == 0 {
-		return insights
+		return nil
+	}
+
+	run := g.start()
+	for _, entry := range entries {
+		run.add(entry)
+	}
+	for _, match := range matches {
+		for _, entry := range match.Matches {
+			run.addMatch(match.Pattern.ID, entry)
+		}
 	}
 
-	// Generate different types of insights
-	insights = append(insights, g.detectErrorSpikes(entries, matches)...)
-	insights = append(insights, g.detectPerformanceIssues(entries, matches)...)
-	insights = append(insights, g.detectAnomalies(entries, matches)...)
-	insights = append(insights, g.detectRootCauses(entries, matches)...)
+	return run.insights(matches)
+}
 
-	// Sort insights by confidence (highest first)
-	sort.Slice(insights, func(i, j int) bool {
-		return insights[i].Confidence > insights[j].Confidence
-	})
+// start begins a run of every active detector
+func (g *InsightGenerator) start() *insightRun {
+	tuning := g.options.tuning()
+	detectors := g.activeDetectors()
+	run := &insightRun{minConfidence: g.options.MinConfidence, runs: make([]DetectorRun, len(detectors))}
+	for i, detector := range detectors {
+		run.runs[i] = detector.NewRun(tuning)
+	}
+	return run
+}
 
-	return insights
+// errorSpikeDetector reports windows whose error rate jumps above the two
+// windows before
+type errorSpikeDetector struct {
+	options ErrorSpikeOptions
+}
+
+// NewErrorSpikeDetector creates the detector of sudden increases in error
+// frequency
+func NewErrorSpikeDetector(options ErrorSpikeOptions) InsightDetector {
+	return &errorSpikeDetector{options: options}
+}
+
+func (d *errorSpikeDetector) Name() string { return "error_spikes" }
+
+func (d *errorSpikeDetector) NewRun(tuning InsightTuning) DetectorRun {
+	return &errorSpikeRun{
+		options:       d.options,
+		evidenceLimit: tuning.EvidenceLimit,
+		windows:       make(map[time.Time]*errorWindow),
+	}
+}
+
+// errorSpikeRun counts entries and errors per window
+type errorSpikeRun struct {
+	options       ErrorSpikeOptions
+	evidenceLimit int
+	totalEntries  int
+	windows       map[time.Time]*errorWindow
+}
+
+// errorWindow holds error statistics for a single time window
+type errorWindow struct {
+	startTime     time.Time
+	entryCount    int
+	errorCount    int
+	errorEvidence []*common.LogEntry
+}
+
+func (r *errorSpikeRun) Add(entry *common.LogEntry) {
+	r.totalEntries++
+	windowStart := entry.Timestamp.Truncate(r.options.Window)
+	window, exists := r.windows[windowStart]
+	if !exists {
+		window = &errorWindow{startTime: windowStart}
+		r.windows[windowStart] = window
+	}
+	window.entryCount++
+	if entry.LogLevel >= common.LevelError {
+		window.errorCount++
+		if len(window.errorEvidence) < r.evidenceLimit {
+			window.errorEvidence = append(window.errorEvidence, entry)
+		}
+	}
 }
 
-// detectErrorSpikes detects sudden increases in error frequency
-func (g *InsightGenerator) detectErrorSpikes(entries []*common.LogEntry, matches []PatternMatch) []Insight {
+func (r *errorSpikeRun) AddMatch(string, *common.LogEntry) {}
+
+// Insights detects sudden increases in error frequency
+func (r *errorSpikeRun) Insights(InsightSummary) []Insight {
 	var insights []Insight
 
-	if len(entries) < 10 {
+	if r.totalEntries < r.options.MinEntries {
 		return insights // Need minimum entries for spike detection
 	}
 
-	// Group entries by time windows (5-minute buckets)
-	buckets := g.groupByTimeWindow(entries, 5*time.Minute)
+	// Entries are grouped by time windows
+	buckets := r.sortedWindows()
 	if len(buckets) < 3 {
 		return insights // Need at least 3 buckets for comparison
 	}
@@ -65,14 +170,8 @@ func (g *InsightGenerator) detectErrorSpikes(entries []*common.LogEntry, matches
 	// Calculate error rates for each bucket
 	errorRates := make([]float64, len(buckets))
 	for i, bucket := range buckets {
-		errorCount := 0
-		for _, entry := range bucket.entries {
-			if entry.LogLevel >= common.LevelError {
-				errorCount++
-			}
-		}
-		if len(bucket.entries) > 0 {
-			errorRates[i] = float64(errorCount) / float64(len(bucket.entries))
+		if bucket.entryCount > 0 {
+			errorRates[i] = float64(bucket.errorCount) / float64(bucket.entryCount)
 		}
 	}
 
@@ -81,16 +180,17 @@ func (g *InsightGenerator) detectErrorSpikes(entries []*common.LogEntry, matches
 		currentRate := errorRates[i]
 		avgPreviousRate := (errorRates[i-1] + errorRates[i-2]) / 2
 
-		if avgPreviousRate > 0 && currentRate/avgPreviousRate >= g.errorSpikeThreshold {
+		threshold := r.options.Threshold
+		if avgPreviousRate > 0 && currentRate/avgPreviousRate >= threshold {
 			// Found an error spike
-			confidence := minFloat(0.95, currentRate/avgPreviousRate/g.errorSpikeThreshold)
+			confidence := minFloat(0.95, currentRate/avgPreviousRate/threshold)
 
 			insight := Insight{
 				Type:        InsightTypeErrorSpike,
 				Severity:    common.LevelError,
 				Title:       "Error Spike Detected",
-				Description: g.formatErrorSpikeDescription(currentRate, avgPreviousRate, buckets[i].startTime),
-				Evidence:    g.getErrorEntries(buckets[i].entries),
+				Description: formatErrorSpikeDescription(currentRate, avgPreviousRate, buckets[i].startTime),
+				Evidence:    buckets[i].errorEvidence,
 				Confidence:  confidence,
 			}
 			insights = append(insights, insight)
@@ -100,21 +200,81 @@ func (g *InsightGenerator) detectErrorSpikes(entries []*common.LogEntry, matches
 	return insights
 }
 
-// detectPerformanceIssues detects performance-related patterns
-func (g *InsightGenerator) detectPerformanceIssues(entries []*common.LogEntry, matches []PatternMatch) []Insight {
+// sortedWindows returns the populated windows in chronological order
+func (r *errorSpikeRun) sortedWindows() []*errorWindow {
+	windows := make([]*errorWindow, 0, len(r.windows))
+	for _, window := range r.windows {
+		windows = append(windows, window)
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].startTime.Before(windows[j].startTime)
+	})
+	return windows
+}
+
+// performanceDetector reports performance patterns and slow responses
+type performanceDetector struct {
+	options      PerformanceOptions
+	slowKeywords []string // lowercased
+}
+
+// NewPerformanceDetector creates the detector of performance patterns and
+// slow response messages
+func NewPerformanceDetector(options PerformanceOptions) InsightDetector {
+	lowered := make([]string, len(options.SlowKeywords))
+	for i, keyword := range options.SlowKeywords {
+		lowered[i] = strings.ToLower(keyword)
+	}
+	return &performanceDetector{options: options, slowKeywords: lowered}
+}
+
+func (d *performanceDetector) Name() string { return "performance" }
+
+func (d *performanceDetector) NewRun(tuning InsightTuning) DetectorRun {
+	return &performanceRun{slowKeywords: d.slowKeywords, tuning: tuning}
+}
+
+// performanceRun counts messages with slow response keywords
+type performanceRun struct {
+	slowKeywords []string
+	tuning       InsightTuning
+	slowCount    int
+	slowEvidence []*common.LogEntry
+}
+
+func (r *performanceRun) Add(entry *common.LogEntry) {
+	message := strings.ToLower(entry.Message)
+	for _, keyword := range r.slowKeywords {
+		if strings.Contains(message, keyword) {
+			r.slowCount++
+			if len(r.slowEvidence) < r.tuning.EvidenceLimit {
+				r.slowEvidence = append(r.slowEvidence, entry)
+			}
+			return
+		}
+	}
+}
+
+func (r *performanceRun) AddMatch(string, *common.LogEntry) {}
+
+// Insights detects performance-related patterns
+func (r *performanceRun) Insights(summary InsightSummary) []Insight {
 	var insights []Insight
 
 	// Look for performance-related patterns
-	for _, match := range matches {
+	for _, match := range summary.Patterns {
 		if match.Pattern.Type == common.PatternTypePerformance && match.Count > 0 {
-			confidence := g.calculatePatternConfidence(&match, len(entries))
+			confidence := calculatePatternConfidence(&match, summary.TotalEntries)
+			if !r.tuning.Reports(match.Pattern, confidence) {
+				continue
+			}
 
 			insight := Insight{
 				Type:        InsightTypePerformance,
 				Severity:    match.Pattern.Severity,
 				Title:       "Performance Issue: " + match.Pattern.Name,
-				Description: g.formatPerformanceDescription(&match),
-				Evidence:    g.limitEvidence(match.Matches, 5),
+				Description: formatPerformanceDescription(&match),
+				Evidence:    limitEvidence(match.Matches, r.tuning.EvidenceLimit),
 				Confidence:  confidence,
 			}
 			insights = append(insights, insight)
@@ -122,14 +282,13 @@ func (g *InsightGenerator) detectPerformanceIssues(entries []*common.LogEntry, m
 	}
 
 	// Detect slow response patterns in messages
-	slowResponses := g.detectSlowResponses(entries)
-	if len(slowResponses) > 0 {
+	if r.slowCount > 0 {
 		insight := Insight{
 			Type:        InsightTypePerformance,
 			Severity:    common.LevelWarn,
 			Title:       "Slow Response Times Detected",
-			Description: g.formatSlowResponseDescription(len(slowResponses)),
-			Evidence:    g.limitEvidence(slowResponses, 5),
+			Description: formatSlowResponseDescription(r.slowCount),
+			Evidence:    r.slowEvidence,
 			Confidence:  0.8,
 		}
 		insights = append(insights, insight)
@@ -138,21 +297,66 @@ func (g *InsightGenerator) detectPerformanceIssues(entries []*common.LogEntry, m
 	return insights
 }
 
-// detectAnomalies detects unusual patterns in the logs
-func (g *InsightGenerator) detectAnomalies(entries []*common.LogEntry, matches []PatternMatch) []Insight {
+// anomalyRuleDetector reports anomaly patterns and services with high
+// error rates
+type anomalyRuleDetector struct {
+	options AnomalyRuleOptions
+}
+
+// NewAnomalyRuleDetector creates the detector of anomaly patterns and
+// services with high error rates
+func NewAnomalyRuleDetector(options AnomalyRuleOptions) InsightDetector {
+	return &anomalyRuleDetector{options: options}
+}
+
+func (d *anomalyRuleDetector) Name() string { return "anomalies" }
+
+func (d *anomalyRuleDetector) NewRun(tuning InsightTuning) DetectorRun {
+	return &anomalyRuleRun{
+		options:       d.options,
+		tuning:        tuning,
+		serviceCounts: make(map[string]int),
+		serviceErrors: make(map[string]int),
+	}
+}
+
+// anomalyRuleRun counts entries and errors per service
+type anomalyRuleRun struct {
+	options       AnomalyRuleOptions
+	tuning        InsightTuning
+	serviceCounts map[string]int
+	serviceErrors map[string]int
+}
+
+func (r *anomalyRuleRun) Add(entry *common.LogEntry) {
+	if entry.Service != "" {
+		r.serviceCounts[entry.Service]++
+		if entry.LogLevel >= common.LevelError {
+			r.serviceErrors[entry.Service]++
+		}
+	}
+}
+
+func (r *anomalyRuleRun) AddMatch(string, *common.LogEntry) {}
+
+// Insights detects unusual patterns in the logs
+func (r *anomalyRuleRun) Insights(summary InsightSummary) []Insight {
 	insights := make([]Insight, 0, 10) // Pre-allocate with initial capacity
 
 	// Look for anomaly-related patterns
-	for _, match := range matches {
+	for _, match := range summary.Patterns {
 		if match.Pattern.Type == common.PatternTypeAnomaly && match.Count > 0 {
-			confidence := g.calculatePatternConfidence(&match, len(entries))
+			confidence := calculatePatternConfidence(&match, summary.TotalEntries)
+			if !r.tuning.Reports(match.Pattern, confidence) {
+				continue
+			}
 
 			insight := Insight{
 				Type:        InsightTypeAnomaly,
 				Severity:    match.Pattern.Severity,
 				Title:       "Anomaly: " + match.Pattern.Name,
-				Description: g.formatAnomalyDescription(&match),
-				Evidence:    g.limitEvidence(match.Matches, 3),
+				Description: formatAnomalyDescription(&match),
+				Evidence:    limitEvidence(match.Matches, minInt(3, r.tuning.EvidenceLimit)),
 				Confidence:  confidence,
 			}
 			insights = append(insights, insight)
@@ -160,20 +364,50 @@ func (g *InsightGenerator) detectAnomalies(entries []*common.LogEntry, matches [
 	}
 
 	// Detect unusual service patterns
-	serviceAnomalies := g.detectServiceAnomalies(entries)
+	serviceAnomalies := r.detectServiceAnomalies()
 	insights = append(insights, serviceAnomalies...)
 
 	return insights
 }
 
+func (r *anomalyRuleRun) detectServiceAnomalies() []Insight {
+	// Simple service-based anomaly detection
+	var insights []Insight
+	for service, errorCount := range r.serviceErrors {
+		totalCount := r.serviceCounts[service]
+		if totalCount > r.options.ServiceMinEntries && float64(errorCount)/float64(totalCount) > r.options.ServiceErrorRate {
+			// Service has a high error rate with significant volume
+			insight := Insight{
+				Type:        InsightTypeAnomaly,
+				Severity:    common.LevelError,
+				Title:       "High Error Rate in Service",
+				Description: formatServiceAnomalyDescription(service, errorCount, totalCount),
+				Evidence:    []*common.LogEntry{}, // Would populate with service entries
+				Confidence:  0.85,
+			}
+			insights = append(insights, insight)
+		}
+	}
+
+	return insights
+}
+
+// NewRootCauseDetector creates the detector of error patterns that occur
+// together, suggesting one causes the other
+func NewRootCauseDetector(options RootCauseOptions) InsightDetector {
+	return NewMatchDetector("root_causes", func(summary InsightSummary, tuning InsightTuning) []Insight {
+		return detectRootCauses(summary.Patterns, options, tuning)
+	})
+}
+
 // detectRootCauses attempts to find potential root causes for errors
-func (g *InsightGenerator) detectRootCauses(entries []*common.LogEntry, matches []PatternMatch) []Insight {
+func detectRootCauses(matches []PatternMatch, options RootCauseOptions, tuning InsightTuning) []Insight {
 	var insights []Insight
 
 	// Group error patterns and look for correlations
 	errorMatches := make([]PatternMatch, 0)
 	for _, match := range matches {
-		if match.Pattern.Type == common.PatternTypeError && match.Count > 0 {
+		if match.Pattern.Type == common.PatternTypeError && match.Count > 0 && !tuning.ForPattern(match.Pattern).Disabled {
 			errorMatches = append(errorMatches, match)
 		}
 	}
@@ -183,15 +417,15 @@ func (g *InsightGenerator) detectRootCauses(entries []*common.LogEntry, matches
 	}
 
 	// Find temporal correlations between error patterns
-	correlations := g.findTemporalCorrelations(errorMatches)
+	correlations := findTemporalCorrelations(errorMatches, options, tuning)
 	for _, correlation := range correlations {
-		if correlation.strength >= g.correlationThreshold {
+		if correlation.strength >= correlation.threshold {
 			insight := Insight{
 				Type:        InsightTypeRootCause,
 				Severity:    common.LevelError,
 				Title:       "Potential Root Cause",
-				Description: g.formatRootCauseDescription(correlation),
-				Evidence:    g.limitEvidence(correlation.evidence, 5),
+				Description: formatRootCauseDescription(correlation),
+				Evidence:    limitEvidence(correlation.evidence, tuning.EvidenceLimit),
 				Confidence:  correlation.strength,
 			}
 			insights = append(insights, insight)
@@ -203,85 +437,15 @@ func (g *InsightGenerator) detectRootCauses(entries []*common.LogEntry, matches
 
 // Helper types and functions
 
-type timeBucket struct {
-	startTime time.Time
-	endTime   time.Time
-	entries   []*common.LogEntry
-}
-
-// TimeBucketer efficiently groups entries by time windows
-type TimeBucketer struct {
-	windowSize time.Duration
-}
-
 type correlation struct {
-	pattern1 string
-	pattern2 string
-	strength float64
-	evidence []*common.LogEntry
+	pattern1  string
+	pattern2  string
+	strength  float64
+	threshold float64
+	evidence  []*common.LogEntry
 }
 
-// NewTimeBucketer creates a new time bucketer
-func NewTimeBucketer(windowSize time.Duration) *TimeBucketer {
-	return &TimeBucketer{
-		windowSize: windowSize,
-	}
-}
-
-// GroupEntries groups entries by time windows using O(n) algorithm
-func (tb *TimeBucketer) GroupEntries(entries []*common.LogEntry) []timeBucket {
-	if len(entries) == 0 {
-		return nil
-	}
-
-	// Use map for O(1) bucket lookup instead of O(n) linear search
-	bucketMap := make(map[time.Time]*timeBucket)
-	var bucketTimes []time.Time
-
-	for _, entry := range entries {
-		bucketStart := entry.Timestamp.Truncate(tb.windowSize)
-
-		bucket, exists := bucketMap[bucketStart]
-		if !exists {
-			bucket = &timeBucket{
-				startTime: bucketStart,
-				endTime:   bucketStart.Add(tb.windowSize),
-				entries:   make([]*common.LogEntry, 0, 10), // Pre-allocate capacity
-			}
-			bucketMap[bucketStart] = bucket
-			bucketTimes = append(bucketTimes, bucketStart)
-		}
-
-		bucket.entries = append(bucket.entries, entry)
-	}
-
-	// Convert map to sorted slice
-	return tb.sortedBuckets(bucketMap, bucketTimes)
-}
-
-// sortedBuckets converts bucket map to sorted slice
-func (tb *TimeBucketer) sortedBuckets(bucketMap map[time.Time]*timeBucket, bucketTimes []time.Time) []timeBucket {
-	// Sort bucket times chronologically
-	sort.Slice(bucketTimes, func(i, j int) bool {
-		return bucketTimes[i].Before(bucketTimes[j])
-	})
-
-	// Convert to slice in chronological order
-	buckets := make([]timeBucket, len(bucketTimes))
-	for i, bucketTime := range bucketTimes {
-		buckets[i] = *bucketMap[bucketTime]
-	}
-
-	return buckets
-}
-
-// groupByTimeWindow is the legacy method, now uses optimized TimeBucketer
-func (g *InsightGenerator) groupByTimeWindow(entries []*common.LogEntry, windowSize time.Duration) []timeBucket {
-	bucketer := NewTimeBucketer(windowSize)
-	return bucketer.GroupEntries(entries)
-}
-
-func (g *InsightGenerator) calculatePatternConfidence(match *PatternMatch, totalEntries int) float64 {
+func calculatePatternConfidence(match *PatternMatch, totalEntries int) float64 {
 	// Base confidence on frequency and pattern quality
 	frequency := float64(match.Count) / float64(totalEntries)
 
@@ -297,16 +461,6 @@ func (g *InsightGenerator) calculatePatternConfidence(match *PatternMatch, total
 	return minFloat(freqScore+patternScore, 0.95)
 }
 
-func (g *InsightGenerator) getErrorEntries(entries []*common.LogEntry) []*common.LogEntry {
-	var errorEntries []*common.LogEntry
-	for _, entry := range entries {
-		if entry.LogLevel >= common.LevelError {
-			errorEntries = append(errorEntries, entry)
-		}
-	}
-	return g.limitEvidence(errorEntries, 5)
-}
-
 // Utility functions
 func minInt(a, b int) int {
 	if a < b {
@@ -322,66 +476,25 @@ func minFloat(a, b float64) float64 {
 	return b
 }
 
-func (g *InsightGenerator) limitEvidence(entries []*common.LogEntry, limit int) []*common.LogEntry {
+func limitEvidence(entries []*common.LogEntry, limit int) []*common.LogEntry {
 	if len(entries) <= limit {
 		return entries
 	}
 	return entries[:limit]
 }
 
-func (g *InsightGenerator) detectSlowResponses(entries []*common.LogEntry) []*common.LogEntry {
-	var slowEntries []*common.LogEntry
-
-	slowKeywords := []string{"slow", "timeout", "taking too long", "high latency", "response time"}
-
-	for _, entry := range entries {
-		message := strings.ToLower(entry.Message)
-		for _, keyword := range slowKeywords {
-			if strings.Contains(message, keyword) {
-				slowEntries = append(slowEntries, entry)
-				break
-			}
+// correlationThreshold returns the correlation two error patterns need, as
+// tuned for the tags of the first pattern that sets one
+func correlationThreshold(pattern1, pattern2 *common.Pattern, options RootCauseOptions, tuning InsightTuning) float64 {
+	for _, pattern := range []*common.Pattern{pattern1, pattern2} {
+		if threshold := tuning.ForPattern(pattern).CorrelationThreshold; threshold > 0 {
+			return threshold
 		}
 	}
-
-	return slowEntries
+	return options.CorrelationThreshold
 }
 
-func (g *InsightGenerator) detectServiceAnomalies(entries []*common.LogEntry) []Insight {
-	// Simple service-based anomaly detection
-	serviceCounts := make(map[string]int)
-	serviceErrors := make(map[string]int)
-
-	for _, entry := range entries {
-		if entry.Service != "" {
-			serviceCounts[entry.Service]++
-			if entry.LogLevel >= common.LevelError {
-				serviceErrors[entry.Service]++
-			}
-		}
-	}
-
-	var insights []Insight
-	for service, errorCount := range serviceErrors {
-		totalCount := serviceCounts[service]
-		if totalCount > 10 && float64(errorCount)/float64(totalCount) > 0.5 {
-			// Service has >50% error rate with significant volume
-			insight := Insight{
-				Type:        InsightTypeAnomaly,
-				Severity:    common.LevelError,
-				Title:       "High Error Rate in Service",
-				Description: g.formatServiceAnomalyDescription(service, errorCount, totalCount),
-				Evidence:    []*common.LogEntry{}, // Would populate with service entries
-				Confidence:  0.85,
-			}
-			insights = append(insights, insight)
-		}
-	}
-
-	return insights
-}
-
-func (g *InsightGenerator) findTemporalCorrelations(matches []PatternMatch) []correlation {
+func findTemporalCorrelations(matches []PatternMatch, options RootCauseOptions, tuning InsightTuning) []correlation {
 	// Simplified correlation detection
 	var correlations []correlation
 
@@ -391,14 +504,16 @@ func (g *InsightGenerator) findTemporalCorrelations(matches []PatternMatch) []co
 			pattern2 := matches[j]
 
 			// Check if patterns occur close together in time
-			strength := g.calculateTemporalCorrelation(&pattern1, &pattern2)
-			if strength > g.correlationThreshold {
+			strength := calculateTemporalCorrelation(&pattern1, &pattern2)
+			threshold := correlationThreshold(pattern1.Pattern, pattern2.Pattern, options, tuning)
+			if strength > threshold {
 				correlations = append(correlations, correlation{
-					pattern1: pattern1.Pattern.Name,
-					pattern2: pattern2.Pattern.Name,
-					strength: strength,
-					evidence: append(g.limitEvidence(pattern1.Matches, 2),
-						g.limitEvidence(pattern2.Matches, 2)...),
+					pattern1:  pattern1.Pattern.Name,
+					pattern2:  pattern2.Pattern.Name,
+					strength:  strength,
+					threshold: threshold,
+					evidence: append(limitEvidence(pattern1.Matches, 2),
+						limitEvidence(pattern2.Matches, 2)...),
 				})
 			}
 		}
@@ -407,17 +522,17 @@ func (g *InsightGenerator) findTemporalCorrelations(matches []PatternMatch) []co
 	return correlations
 }
 
-func (g *InsightGenerator) calculateTemporalCorrelation(match1, match2 *PatternMatch) float64 {
+func calculateTemporalCorrelation(match1, match2 *PatternMatch) float64 {
 	// Simplified: check if patterns have overlapping time ranges
 	if !match1.FirstSeen.IsZero() && !match2.FirstSeen.IsZero() {
-		overlap := g.calculateTimeOverlap(match1.FirstSeen, match1.LastSeen,
+		overlap := calculateTimeOverlap(match1.FirstSeen, match1.LastSeen,
 			match2.FirstSeen, match2.LastSeen)
 		return overlap
 	}
 	return 0.0
 }
 
-func (g *InsightGenerator) calculateTimeOverlap(start1, end1, start2, end2 time.Time) float64 {
+func calculateTimeOverlap(start1, end1, start2, end2 time.Time) float64 {
 	// Calculate overlap ratio
 	latest_start := start1
 	if start2.After(start1) {
@@ -445,34 +560,32 @@ func (g *InsightGenerator) calculateTimeOverlap(start1, end1, start2, end2 time.
 
 // Format functions for descriptions
 
-func (g *InsightGenerator) formatErrorSpikeDescription(current, previous float64, timestamp time.Time) string {
+func formatErrorSpikeDescription(current, previous float64, timestamp time.Time) string {
 	increase := (current / previous) * 100
 	return fmt.Sprintf("Error rate increased by %.1f%% at %s (from %.1f%% to %.1f%%)",
 		increase-100, timestamp.Format("15:04:05"), previous*100, current*100)
 }
 
-func (g *InsightGenerator) formatPerformanceDescription(match *PatternMatch) string {
+func formatPerformanceDescription(match *PatternMatch) string {
 	return fmt.Sprintf("Pattern '%s' detected %d times, indicating potential performance issues",
 		match.Pattern.Name, match.Count)
 }
 
-func (g *InsightGenerator) formatSlowResponseDescription(count int) string {
+func formatSlowResponseDescription(count int) string {
 	return fmt.Sprintf("Detected %d log entries indicating slow response times or timeouts", count)
 }
 
-func (g *InsightGenerator) formatAnomalyDescription(match *PatternMatch) string {
+func formatAnomalyDescription(match *PatternMatch) string {
 	return fmt.Sprintf("Anomalous pattern '%s' detected %d times", match.Pattern.Name, match.Count)
 }
 
-func (g *InsightGenerator) formatServiceAnomalyDescription(service string, errors, total int) string {
+func formatServiceAnomalyDescription(service string, errors, total int) string {
 	errorRate := float64(errors) / float64(total) * 100
 	return fmt.Sprintf("Service '%s' has high error rate: %d errors out of %d entries (%.1f%%)",
 		service, errors, total, errorRate)
 }
 
-func (g *InsightGenerator) formatRootCauseDescription(corr correlation) string {
+func formatRootCauseDescription(corr correlation) string {
 	return fmt.Sprintf("Strong correlation (%.1f%%) between '%s' and '%s' patterns suggests potential causal relationship",
 		corr.strength*100, corr.pattern1, corr.pattern2)
 }
-
-// Helper functions
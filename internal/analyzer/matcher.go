@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/filter"
 )
 
 // PatternMatcher handles efficient pattern matching against log entries
@@ -20,7 +21,12 @@ type compiledPattern struct {
 	pattern       *common.Pattern
 	regex         *regexp.Regexp
 	keywords      []string
-	keywordsLower []string // Pre-computed lowercase keywords
+	keywordsLower []string           // Pre-computed lowercase keywords
+	condition     *filter.Expression // Field conditions the entry must also satisfy
+	exclude       []*regexp.Regexp   // Lines matching these are not matches
+	suppressions  []*compiledSuppression
+	captures      []string           // Names of the regex's named capture groups
+	steps         []*compiledPattern // Steps of a sequence pattern, which never matches a single line
 }
 
 // searchableEntry pre-computes search text
@@ -70,11 +76,19 @@ func (m *PatternMatcher) SetPatterns(patterns []*common.Pattern) error {
 
 // MatchPatterns matches all patterns against log entries
 func (m *PatternMatcher) MatchPatterns(ctx context.Context, patterns []*common.Pattern, entries []*common.LogEntry) ([]PatternMatch, error) {
+	matches, _, err := m.matchPatterns(ctx, entries)
+	return matches, err
+}
+
+// matchPatterns matches all patterns against log entries and counts the
+// matches that suppression rules silenced
+func (m *PatternMatcher) matchPatterns(ctx context.Context, entries []*common.LogEntry) ([]PatternMatch, suppressionCounts, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	suppressed := make(suppressionCounts)
 	if len(m.compiledPatterns) == 0 {
-		return []PatternMatch{}, nil
+		return []PatternMatch{}, suppressed, nil
 	}
 
 	// Initialize pattern matches
@@ -93,7 +107,7 @@ func (m *PatternMatcher) MatchPatterns(ctx context.Context, patterns []*common.P
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, nil, ctx.Err()
 		default:
 		}
 
@@ -103,7 +117,7 @@ func (m *PatternMatcher) MatchPatterns(ctx context.Context, patterns []*common.P
 		}
 
 		batch := entries[i:end]
-		m.processBatch(batch, matches)
+		m.processBatch(batch, matches, suppressed)
 	}
 
 	// Convert map to slice and filter out empty matches
@@ -114,12 +128,12 @@ func (m *PatternMatcher) MatchPatterns(ctx context.Context, patterns []*common.P
 		}
 	}
 
-	return result, nil
+	return result, suppressed, nil
 }
 
 // processBatch processes a batch of entries against all patterns
 // Optimized version: pre-compute search text once per entry
-func (m *PatternMatcher) processBatch(entries []*common.LogEntry, matches map[string]*PatternMatch) {
+func (m *PatternMatcher) processBatch(entries []*common.LogEntry, matches map[string]*PatternMatch, suppressed suppressionCounts) {
 	// Pre-compute search text for all entries
 	searchableEntries := m.precomputeSearchText(entries)
 
@@ -129,10 +143,19 @@ func (m *PatternMatcher) processBatch(entries []*common.LogEntry, matches map[st
 			if !m.matchSearchableEntry(searchableEntry, cp) {
 				continue
 			}
+			if rule := suppressedBy(searchableEntry.entry, cp); rule != "" {
+				suppressed.add(rule, cp.pattern.ID)
+				continue
+			}
 
 			match := matches[cp.pattern.ID]
 			match.Matches = append(match.Matches, searchableEntry.entry)
 			match.Count++
+			if len(cp.captures) > 0 {
+				values := captureValues(cp.regex, searchableEntry.entry)
+				attachCaptures(searchableEntry.entry, values)
+				countCaptures(match, values)
+			}
 
 			// Update first/last seen timestamps
 			entry := searchableEntry.entry
@@ -172,6 +195,21 @@ func (m *PatternMatcher) precomputeSearchText(entries []*common.LogEntry) []sear
 
 // matchSearchableEntry checks if a searchable entry matches a compiled pattern
 func (m *PatternMatcher) matchSearchableEntry(se searchableEntry, cp *compiledPattern) bool {
+	if cp.steps != nil {
+		return false
+	}
+
+	// Field conditions are required on top of regex and keywords. They see
+	// the entry's parsed fields, not the captures of this pattern's regex.
+	if cp.condition != nil {
+		if !cp.condition.Match(se.entry) {
+			return false
+		}
+		if cp.regex == nil && len(cp.keywords) == 0 {
+			return true
+		}
+	}
+
 	// Try regex matching first (more specific)
 	if cp.regex != nil {
 		if cp.regex.MatchString(se.entry.Raw) || cp.regex.MatchString(se.entry.Message) {
@@ -179,6 +217,16 @@ func (m *PatternMatcher) matchSearchableEntry(se searchableEntry, cp *compiledPa
 		}
 	}
 
+	// Case-sensitive keywords are looked up in the original fields
+	if cp.pattern.CaseSensitive {
+		for _, keyword := range cp.keywords {
+			if containsKeyword(se.entry, keyword) {
+				return true
+			}
+		}
+		return false
+	}
+
 	// Try keyword matching (faster for simple patterns)
 	if len(cp.keywordsLower) > 0 {
 		for _, keyword := range cp.keywordsLower {
@@ -197,13 +245,35 @@ func (m *PatternMatcher) compilePattern(pattern *common.Pattern) (*compiledPatte
 		pattern: pattern,
 	}
 
+	if pattern.Type == common.PatternTypeSequence {
+		return m.compileSequence(cp)
+	}
+
+	flags := "(?i)" // Case-insensitive unless the pattern asks otherwise
+	if pattern.CaseSensitive {
+		flags = ""
+	}
+
 	// Compile regex if present
 	if pattern.Regex != "" {
-		regex, err := regexp.Compile("(?i)" + pattern.Regex) // Case-insensitive
+		regex, err := regexp.Compile(flags + pattern.Regex)
 		if err != nil {
 			return nil, fmt.Errorf("invalid regex: %w", err)
 		}
 		cp.regex = regex
+		for _, name := range regex.SubexpNames() {
+			if name != "" {
+				cp.captures = append(cp.captures, name)
+			}
+		}
+	}
+
+	for _, exclude := range pattern.Exclude {
+		regex, err := regexp.Compile(flags + exclude)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude regex: %w", err)
+		}
+		cp.exclude = append(cp.exclude, regex)
 	}
 
 	// Prepare keywords for efficient matching
@@ -215,14 +285,132 @@ func (m *PatternMatcher) compilePattern(pattern *common.Pattern) (*compiledPatte
 		}
 	}
 
-	// Validate that pattern has either regex or keywords
-	if cp.regex == nil && len(cp.keywords) == 0 {
-		return nil, fmt.Errorf("pattern must have either regex or keywords")
+	if pattern.Condition != "" {
+		condition, err := filter.ParseCase(pattern.Condition, pattern.CaseSensitive)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition: %w", err)
+		}
+		cp.condition = condition
+	}
+
+	// Validate that pattern has something to match
+	if cp.regex == nil && len(cp.keywords) == 0 && cp.condition == nil {
+		return nil, fmt.Errorf("pattern must have a regex, keywords or a condition")
+	}
+
+	if pattern.Threshold != nil {
+		if err := pattern.Threshold.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cp.compileSuppressions(); err != nil {
+		return nil, err
 	}
 
 	return cp, nil
 }
 
+// containsKeyword reports whether an entry's message, service or raw line
+// contains a keyword, with case
+func containsKeyword(entry *common.LogEntry, keyword string) bool {
+	return strings.Contains(entry.Message, keyword) ||
+		strings.Contains(entry.Service, keyword) ||
+		strings.Contains(entry.Raw, keyword)
+}
+
+// captureValues matches a regex against an entry's message, then its raw
+// line, and returns the non-empty named captures of the first match
+func captureValues(regex *regexp.Regexp, entry *common.LogEntry) map[string]string {
+	for _, text := range []string{entry.Message, entry.Raw} {
+		match := regex.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		values := make(map[string]string)
+		for i, name := range regex.SubexpNames() {
+			if name != "" && match[i] != "" {
+				values[name] = match[i]
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// attachCaptures stores capture values in an entry's metadata, where
+// queries and filters can use them. Existing metadata keys are left
+// unchanged.
+func attachCaptures(entry *common.LogEntry, values map[string]string) {
+	for name, value := range values {
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]string)
+		}
+		if _, exists := entry.Metadata[name]; !exists {
+			entry.Metadata[name] = value
+		}
+	}
+}
+
+// compileSequence compiles the steps of a sequence pattern
+func (m *PatternMatcher) compileSequence(cp *compiledPattern) (*compiledPattern, error) {
+	sequence := cp.pattern.Sequence
+	if sequence == nil {
+		return nil, fmt.Errorf("sequence pattern must have a sequence")
+	}
+	if err := sequence.Validate(); err != nil {
+		return nil, err
+	}
+
+	cp.steps = make([]*compiledPattern, len(sequence.Steps))
+	for i, step := range sequence.Steps {
+		compiled, err := m.compilePattern(&common.Pattern{
+			ID:            fmt.Sprintf("%s#%d", cp.pattern.ID, i+1),
+			Regex:         step.Regex,
+			Keywords:      step.Keywords,
+			Condition:     step.Condition,
+			CaseSensitive: cp.pattern.CaseSensitive,
+			Exclude:       cp.pattern.Exclude,
+			Suppressions:  cp.pattern.Suppressions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		cp.steps[i] = compiled
+	}
+	return cp, nil
+}
+
+// compiled returns the compiled form of a pattern, or nil if it is unknown
+func (m *PatternMatcher) compiled(id string) *compiledPattern {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, cp := range m.compiledPatterns {
+		if cp.pattern.ID == id {
+			return cp
+		}
+	}
+	return nil
+}
+
+// captureRegex returns the regex of a pattern if it has named captures,
+// or nil otherwise
+func (m *PatternMatcher) captureRegex(id string) *regexp.Regexp {
+	if cp := m.compiled(id); cp != nil && len(cp.captures) > 0 {
+		return cp.regex
+	}
+	return nil
+}
+
+// patternRegex returns the compiled regex of a pattern, or nil if it has none
+func (m *PatternMatcher) patternRegex(id string) *regexp.Regexp {
+	if cp := m.compiled(id); cp != nil {
+		return cp.regex
+	}
+	return nil
+}
+
 // GetCompiledPatterns returns the compiled patterns (for testing)
 func (m *PatternMatcher) GetCompiledPatterns() []*compiledPattern {
 	m.mu.RLock()
@@ -232,6 +420,13 @@ func (m *PatternMatcher) GetCompiledPatterns() []*compiledPattern {
 
 // MatchSingle matches a single entry against all patterns (useful for real-time analysis)
 func (m *PatternMatcher) MatchSingle(entry *common.LogEntry) []string {
+	matchedPatterns, _ := m.matchSingle(entry)
+	return matchedPatterns
+}
+
+// matchSingle matches a single entry against all patterns, returning the
+// matches that suppression rules silenced separately
+func (m *PatternMatcher) matchSingle(entry *common.LogEntry) ([]string, []suppressionKey) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -240,11 +435,42 @@ func (m *PatternMatcher) MatchSingle(entry *common.LogEntry) []string {
 	se := searchableEntries[0]
 
 	var matchedPatterns []string
+	var suppressed []suppressionKey
 	for _, cp := range m.compiledPatterns {
-		if m.matchSearchableEntry(se, cp) {
-			matchedPatterns = append(matchedPatterns, cp.pattern.ID)
+		if !m.matchSearchableEntry(se, cp) {
+			continue
+		}
+		if rule := suppressedBy(entry, cp); rule != "" {
+			suppressed = append(suppressed, suppressionKey{rule: rule, pattern: cp.pattern.ID})
+			continue
+		}
+		if len(cp.captures) > 0 {
+			attachCaptures(entry, captureValues(cp.regex, entry))
 		}
+		matchedPatterns = append(matchedPatterns, cp.pattern.ID)
 	}
 
-	return matchedPatterns
+	return matchedPatterns, suppressed
+}
+
+// maxCaptureValues bounds the distinct values counted per capture, so
+// captures of unique IDs do not grow without limit
+const maxCaptureValues = 1000
+
+// countCaptures adds the capture values of one matched entry to a match's
+// per-capture value counts
+func countCaptures(match *PatternMatch, values map[string]string) {
+	for name, value := range values {
+		if match.Captures == nil {
+			match.Captures = make(map[string]map[string]int)
+		}
+		counts := match.Captures[name]
+		if counts == nil {
+			counts = make(map[string]int)
+			match.Captures[name] = counts
+		}
+		if _, seen := counts[value]; seen || len(counts) < maxCaptureValues {
+			counts[value]++
+		}
+	}
 }
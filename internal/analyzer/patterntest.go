@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/ingest"
+	"github.com/yildizm/go-logparser"
+)
+
+// PatternTestResult is the outcome of one sample line in a pattern's tests
+type PatternTestResult struct {
+	Line      string
+	WantMatch bool
+	Passed    bool
+}
+
+// PatternTestReport holds the test results of one pattern
+type PatternTestReport struct {
+	Pattern *common.Pattern
+	Results []PatternTestResult
+	Err     error // set when the pattern does not compile
+}
+
+// Failed returns the number of failed tests, counting every test as failed
+// when the pattern does not compile
+func (r *PatternTestReport) Failed() int {
+	if r.Err != nil {
+		return len(r.Results)
+	}
+	failed := 0
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// TestPattern runs a pattern's tests through a PatternMatcher holding only
+// that pattern. Sample lines are parsed like lines of a log file.
+func TestPattern(pattern *common.Pattern) *PatternTestReport {
+	report := &PatternTestReport{Pattern: pattern}
+	if pattern.Tests == nil {
+		return report
+	}
+
+	matcher := NewPatternMatcher()
+	err := matcher.AddPattern(pattern)
+	for _, line := range pattern.Tests.Match {
+		report.Results = append(report.Results, PatternTestResult{Line: line, WantMatch: true})
+	}
+	for _, line := range pattern.Tests.NoMatch {
+		report.Results = append(report.Results, PatternTestResult{Line: line})
+	}
+	if err != nil {
+		report.Err = err
+		return report
+	}
+
+	for i := range report.Results {
+		result := &report.Results[i]
+		matched := len(matcher.MatchSingle(sampleEntry(result.Line))) > 0
+		result.Passed = matched == result.WantMatch
+	}
+	return report
+}
+
+// sampleEntry parses a test line the way it would be read from a file, so
+// conditions on its level or service can be tested. Lines the parser
+// rejects are kept as an unparsed message.
+func sampleEntry(line string) *common.LogEntry {
+	parser := logparser.NewWithFormat(ingest.DetectFormat([]string{line}))
+	if parsed, err := parser.ParseString(line); err == nil && len(parsed) == 1 {
+		entry := common.ConvertToCommonLogEntry(&parsed[0], 1)
+		entry.Raw = line
+		return entry
+	}
+	return &common.LogEntry{
+		LogEntry: logparser.LogEntry{Message: line},
+		Raw:      line,
+	}
+}
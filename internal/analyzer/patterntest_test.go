@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func TestTestPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern *common.Pattern
+		results []bool
+		failed  int
+	}{
+		{
+			name: "regex",
+			pattern: &common.Pattern{ID: "db", Regex: `connection refused.*:5432`, Tests: &common.PatternTests{
+				Match:   []string{"ERROR connection refused by 10.0.0.5:5432", "connection refused :3306"},
+				NoMatch: []string{"INFO pool resized"},
+			}},
+			results: []bool{true, false, true},
+			failed:  1,
+		},
+		{
+			name: "keywords",
+			pattern: &common.Pattern{ID: "oom", Keywords: []string{"out of memory"}, Tests: &common.PatternTests{
+				Match:   []string{`{"level":"fatal","msg":"Out Of Memory"}`},
+				NoMatch: []string{"out of memory"},
+			}},
+			results: []bool{true, false},
+			failed:  1,
+		},
+		{
+			name: "condition",
+			pattern: &common.Pattern{ID: "api", Condition: "level>=ERROR AND service in [api]", Tests: &common.PatternTests{
+				Match:   []string{"level=error service=api msg=failed"},
+				NoMatch: []string{"level=warn service=api msg=slow", "level=error service=web msg=failed"},
+			}},
+			results: []bool{true, true, true},
+		},
+		{
+			name:    "untested",
+			pattern: &common.Pattern{ID: "none", Keywords: []string{"x"}},
+		},
+		{
+			name: "invalid regex",
+			pattern: &common.Pattern{ID: "bad", Regex: "(", Tests: &common.PatternTests{
+				Match: []string{"("}, NoMatch: []string{")"},
+			}},
+			results: []bool{false, false},
+			failed:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		report := TestPattern(tt.pattern)
+		if len(report.Results) != len(tt.results) {
+			t.Fatalf("%s: got %d results, want %d", tt.name, len(report.Results), len(tt.results))
+		}
+		for i, passed := range tt.results {
+			if report.Results[i].Passed != passed {
+				t.Errorf("%s: result %d (%q) passed = %v, want %v", tt.name, i, report.Results[i].Line, report.Results[i].Passed, passed)
+			}
+		}
+		if report.Failed() != tt.failed {
+			t.Errorf("%s: Failed() = %d, want %d", tt.name, report.Failed(), tt.failed)
+		}
+	}
+}
+
+func TestAttachPatternTests(t *testing.T) {
+	pattern := &common.Pattern{ID: "db", Tests: &common.PatternTests{Match: []string{"inline"}}}
+	cases := []*common.PatternTestCase{
+		{Pattern: "db", PatternTests: common.PatternTests{Match: []string{"sidecar"}, NoMatch: []string{"other"}}},
+	}
+
+	if err := common.AttachPatternTests([]*common.Pattern{pattern}, cases); err != nil {
+		t.Fatalf("AttachPatternTests() failed: %v", err)
+	}
+	if len(pattern.Tests.Match) != 2 || len(pattern.Tests.NoMatch) != 1 {
+		t.Errorf("tests = %+v, want inline and sidecar lines", pattern.Tests)
+	}
+
+	unknown := []*common.PatternTestCase{{Pattern: "missing"}}
+	if err := common.AttachPatternTests([]*common.Pattern{pattern}, unknown); err == nil {
+		t.Error("AttachPatternTests() with an unknown pattern succeeded, want error")
+	}
+}
@@ -0,0 +1,217 @@
+package analyzer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/filter"
+)
+
+// sequenceTracker evaluates a sequence pattern over entries in time order.
+// It keeps one sequence in progress per join key.
+type sequenceTracker struct {
+	matcher      *PatternMatcher
+	patternID    string
+	sequence     *common.Sequence
+	steps        []*compiledPattern
+	pending      map[string]*sequenceProgress
+	nextDeadline time.Time // earliest time a pending sequence may expire
+	suppressed   suppressionCounts
+}
+
+// sequenceProgress is a sequence whose first steps have been seen
+type sequenceProgress struct {
+	entries []*common.LogEntry
+	last    time.Time
+}
+
+// newSequenceTracker creates a tracker for a compiled sequence pattern
+func newSequenceTracker(matcher *PatternMatcher, cp *compiledPattern) *sequenceTracker {
+	return &sequenceTracker{
+		matcher:    matcher,
+		patternID:  cp.pattern.ID,
+		sequence:   cp.pattern.Sequence,
+		steps:      cp.steps,
+		pending:    make(map[string]*sequenceProgress),
+		suppressed: make(suppressionCounts),
+	}
+}
+
+// add feeds an entry and returns the sequences it completes, including
+// absence sequences whose last step is now overdue
+func (t *sequenceTracker) add(se searchableEntry) []common.SequenceOccurrence {
+	entry := se.entry
+	completed := t.expire(entry.Timestamp)
+
+	// Later steps first, so an entry advances a sequence in progress
+	// rather than restarting it
+	advanced := make(map[string]bool)
+	silenced := false
+	for i := len(t.steps) - 1; i >= 0; i-- {
+		if !t.matcher.matchSearchableEntry(se, t.steps[i]) {
+			continue
+		}
+		// A silenced entry takes no step, and is counted once
+		if rule := suppressedBy(entry, t.steps[i]); rule != "" {
+			if !silenced {
+				t.suppressed.add(rule, t.patternID)
+				silenced = true
+			}
+			continue
+		}
+		key, ok := t.joinKey(entry, t.steps[i])
+		if !ok || advanced[key] {
+			continue
+		}
+
+		progress := t.pending[key]
+		switch {
+		case progress != nil && len(progress.entries) == i:
+			advanced[key] = true
+			if i == len(t.steps)-1 {
+				delete(t.pending, key)
+				if !t.sequence.Absent {
+					entries := append(progress.entries, entry)
+					completed = append(completed, common.SequenceOccurrence{Key: key, Entries: entries})
+				}
+				continue
+			}
+			progress.entries = append(progress.entries, entry)
+			progress.last = entry.Timestamp
+		case i == 0 && t.canStart(progress):
+			// A repeated first step restarts the sequence, except for
+			// absence rules, which time the earliest unanswered step
+			advanced[key] = true
+			t.pending[key] = &sequenceProgress{entries: []*common.LogEntry{entry}, last: entry.Timestamp}
+			t.noteDeadline(entry.Timestamp)
+		}
+	}
+	return completed
+}
+
+// canStart reports whether a first step may replace the progress of its key
+func (t *sequenceTracker) canStart(progress *sequenceProgress) bool {
+	return progress == nil || (len(progress.entries) == 1 && !t.sequence.Absent)
+}
+
+// noteDeadline lowers the next expiry check to cover a step seen at last
+func (t *sequenceTracker) noteDeadline(last time.Time) {
+	deadline := last.Add(t.sequence.MaxGap)
+	if t.nextDeadline.IsZero() || deadline.Before(t.nextDeadline) {
+		t.nextDeadline = deadline
+	}
+}
+
+// expire drops sequences whose next step is overdue at now, returning the
+// absence sequences that were waiting only for their last step
+func (t *sequenceTracker) expire(now time.Time) []common.SequenceOccurrence {
+	if t.nextDeadline.IsZero() || !now.After(t.nextDeadline) {
+		return nil
+	}
+
+	var missing []common.SequenceOccurrence
+	t.nextDeadline = time.Time{}
+	for key, progress := range t.pending {
+		if now.Sub(progress.last) <= t.sequence.MaxGap {
+			t.noteDeadline(progress.last)
+			continue
+		}
+		delete(t.pending, key)
+		if t.sequence.Absent && len(progress.entries) == len(t.steps)-1 {
+			missing = append(missing, common.SequenceOccurrence{Key: key, Entries: progress.entries, Missing: true})
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].Entries[0].Timestamp.Before(missing[j].Entries[0].Timestamp)
+	})
+	return missing
+}
+
+// joinKey returns the join_by value of an entry matching a step: the named
+// capture of the step regex, or else the entry field of that name. It
+// reports false when the sequence joins and the entry has no value.
+func (t *sequenceTracker) joinKey(entry *common.LogEntry, step *compiledPattern) (string, bool) {
+	joinBy := t.sequence.JoinBy
+	if joinBy == "" {
+		return "", true
+	}
+	if step.regex != nil {
+		if index := step.regex.SubexpIndex(joinBy); index > 0 {
+			for _, text := range []string{entry.Message, entry.Raw} {
+				if match := step.regex.FindStringSubmatch(text); match != nil && match[index] != "" {
+					return match[index], true
+				}
+			}
+		}
+	}
+	value, ok := filter.FieldValue(entry, joinBy)
+	return value, ok && value != ""
+}
+
+// sequenceTrackers creates a tracker for each sequence pattern, by ID
+func (e *AnalyzerEngine) sequenceTrackers() map[string]*sequenceTracker {
+	trackers := make(map[string]*sequenceTracker)
+	for _, pattern := range e.patterns {
+		if pattern.Type != common.PatternTypeSequence {
+			continue
+		}
+		if cp := e.matcher.compiled(pattern.ID); cp != nil && cp.steps != nil {
+			trackers[pattern.ID] = newSequenceTracker(e.matcher, cp)
+		}
+	}
+	return trackers
+}
+
+// matchSequences evaluates the sequence patterns over entries in time order,
+// adding the steps that suppression rules silenced to suppressed
+func (e *AnalyzerEngine) matchSequences(entries []*common.LogEntry, suppressed suppressionCounts) []PatternMatch {
+	trackers := e.sequenceTrackers()
+	if len(trackers) == 0 {
+		return nil
+	}
+
+	occurrences := make(map[string][]common.SequenceOccurrence, len(trackers))
+	for _, entry := range entries {
+		se := e.matcher.precomputeSearchText([]*common.LogEntry{entry})[0]
+		for id, tracker := range trackers {
+			occurrences[id] = append(occurrences[id], tracker.add(se)...)
+		}
+	}
+	for _, tracker := range trackers {
+		suppressed.merge(tracker.suppressed)
+	}
+
+	var matches []PatternMatch
+	for _, pattern := range e.patterns {
+		found := occurrences[pattern.ID]
+		delete(occurrences, pattern.ID)
+		if len(found) == 0 {
+			continue
+		}
+		match := PatternMatch{Pattern: pattern, Matches: []*common.LogEntry{}}
+		for _, occurrence := range found {
+			recordSequence(&match, occurrence, -1)
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// recordSequence adds an occurrence to a sequence pattern's match, keeping
+// at most limit occurrences as evidence unless limit is negative
+func recordSequence(match *PatternMatch, occurrence common.SequenceOccurrence, limit int) {
+	match.Count++
+	if limit < 0 || len(match.Sequences) < limit {
+		match.Sequences = append(match.Sequences, occurrence)
+		match.Matches = append(match.Matches, occurrence.Entries...)
+	}
+	for _, entry := range occurrence.Entries {
+		if match.FirstSeen.IsZero() || entry.Timestamp.Before(match.FirstSeen) {
+			match.FirstSeen = entry.Timestamp
+		}
+		if entry.Timestamp.After(match.LastSeen) {
+			match.LastSeen = entry.Timestamp
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func sequenceEntries() []*common.LogEntry {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entry := func(offset int, message, trace string) *common.LogEntry {
+		e := createTestEntry(base.Add(time.Duration(offset)*time.Second), common.LevelInfo, "INFO", message)
+		e.TraceID = trace
+		return e
+	}
+	return []*common.LogEntry{
+		entry(0, "deploy started", ""),
+		entry(10, "deploy started", ""),
+		entry(100, "readiness probe failed", ""),
+		entry(200, "retrying job 7", "t1"),
+		entry(205, "retrying job 8", "t2"),
+		entry(210, "retrying job 7", "t1"),
+		entry(215, "job 8 succeeded", "t2"),
+		entry(400, "deploy started", ""),
+		entry(600, "readiness probe failed", ""),
+	}
+}
+
+func TestSequencePatterns(t *testing.T) {
+	patterns := []*common.Pattern{
+		{
+			ID: "deploy", Name: "Failed Deploy", Type: common.PatternTypeSequence,
+			Sequence: &common.Sequence{
+				Steps:  []common.SequenceStep{{Keywords: []string{"deploy started"}}, {Regex: "probe failed"}},
+				MaxGap: 2 * time.Minute,
+			},
+		},
+		{
+			ID: "retry", Name: "Unanswered Retry", Type: common.PatternTypeSequence,
+			Sequence: &common.Sequence{
+				Steps:  []common.SequenceStep{{Keywords: []string{"retrying"}}, {Keywords: []string{"succeeded"}}},
+				MaxGap: 30 * time.Second,
+				JoinBy: "trace_id",
+				Absent: true,
+			},
+		},
+	}
+	engine := NewEngine()
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+
+	entries := sequenceEntries()
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream := engine.NewStream(DefaultStreamOptions())
+	var completed []string
+	for _, entry := range entries {
+		completed = append(completed, stream.Add(entry)...)
+	}
+	if len(completed) != 2 {
+		t.Errorf("Add() reported %d completed sequences, want 2: %v", len(completed), completed)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		matches := make(map[string]PatternMatch)
+		for _, match := range analysis.Patterns {
+			matches[match.Pattern.ID] = match
+		}
+
+		deploy := matches["deploy"]
+		if deploy.Count != 1 || len(deploy.Sequences) != 1 {
+			t.Fatalf("%s: deploy = %d occurrences, want 1", name, deploy.Count)
+		}
+		if steps := deploy.Sequences[0].Entries; len(steps) != 2 || steps[0] != entries[1] || steps[1] != entries[2] {
+			t.Errorf("%s: deploy sequence = %v, want the second deploy and the probe failure", name, steps)
+		}
+
+		retry := matches["retry"]
+		if retry.Count != 1 || len(retry.Sequences) != 1 {
+			t.Fatalf("%s: retry = %d occurrences, want 1", name, retry.Count)
+		}
+		missing := retry.Sequences[0]
+		if !missing.Missing || missing.Key != "t1" || missing.Entries[0] != entries[3] {
+			t.Errorf("%s: retry sequence = %+v, want the first t1 retry without success", name, missing)
+		}
+	}
+}
+
+func TestInvalidSequence(t *testing.T) {
+	for _, sequence := range []*common.Sequence{
+		nil,
+		{Steps: []common.SequenceStep{{Keywords: []string{"a"}}}, MaxGap: time.Second},
+		{Steps: []common.SequenceStep{{Keywords: []string{"a"}}, {}}, MaxGap: time.Second},
+		{Steps: []common.SequenceStep{{Keywords: []string{"a"}}, {Keywords: []string{"b"}}}},
+	} {
+		pattern := &common.Pattern{ID: "seq", Type: common.PatternTypeSequence, Sequence: sequence}
+		if err := NewEngine().SetPatterns([]*common.Pattern{pattern}); err == nil {
+			t.Errorf("SetPatterns() with sequence %+v succeeded, want error", sequence)
+		}
+	}
+}
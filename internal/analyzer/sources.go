@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// sourceBreakdown accumulates entry, error and pattern counts per input
+// source so that analyses of several files can be compared file by file
+type sourceBreakdown struct {
+	sources map[string]*common.SourceSummary
+}
+
+func newSourceBreakdown() *sourceBreakdown {
+	return &sourceBreakdown{
+		sources: make(map[string]*common.SourceSummary),
+	}
+}
+
+// add records an entry, the error and warning counts it contributes and
+// the patterns it matched
+func (b *sourceBreakdown) add(entry *common.LogEntry, matchedIDs []string, errors, warns int) {
+	summary, exists := b.sources[entry.Source]
+	if !exists {
+		summary = &common.SourceSummary{
+			Source:        entry.Source,
+			PatternCounts: make(map[string]int),
+		}
+		b.sources[entry.Source] = summary
+	}
+
+	summary.TotalEntries++
+	summary.ErrorCount += errors
+	summary.WarnCount += warns
+	for _, id := range matchedIDs {
+		summary.PatternCounts[id]++
+	}
+}
+
+// summaries returns the per-source breakdown sorted by source name. It is
+// nil unless entries came from more than one source.
+func (b *sourceBreakdown) summaries() []common.SourceSummary {
+	if len(b.sources) < 2 {
+		return nil
+	}
+
+	result := make([]common.SourceSummary, 0, len(b.sources))
+	for _, summary := range b.sources {
+		snapshot := *summary
+		snapshot.PatternCounts = make(map[string]int, len(summary.PatternCounts))
+		for id, count := range summary.PatternCounts {
+			snapshot.PatternCounts[id] = count
+		}
+		result = append(result, snapshot)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Source < result[j].Source
+	})
+	return result
+}
+
+// severityCounts returns how much an entry adds to the error and warning
+// counts: its level counts first, and matched error, anomaly and
+// performance patterns only add to the counts for entries not already
+// counted by level
+func severityCounts(entry *common.LogEntry, matchedIDs []string, patternsByID map[string]*common.Pattern) (errors, warns int) {
+	switch entry.LogLevel {
+	case common.LevelError, common.LevelFatal:
+		return 1, 0
+	case common.LevelWarn:
+		warns = 1
+	}
+
+	var errorHit, warnHit bool
+	for _, id := range matchedIDs {
+		pattern := patternsByID[id]
+		if pattern == nil {
+			continue
+		}
+		switch pattern.Type {
+		case common.PatternTypeError:
+			errorHit = true
+		case common.PatternTypeAnomaly, common.PatternTypePerformance:
+			warnHit = warnHit || entry.LogLevel != common.LevelWarn
+		}
+	}
+
+	if errorHit {
+		errors++
+	}
+	if warnHit {
+		warns++
+	}
+	return errors, warns
+}
@@ -0,0 +1,376 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// EntrySource yields log entries one at a time.
+// Next returns io.EOF once the source is exhausted.
+type EntrySource interface {
+	Next() (*common.LogEntry, error)
+}
+
+// StreamOptions bounds the memory a StreamAnalyzer retains
+type StreamOptions struct {
+	MaxPatternSamples int // matched entries kept per pattern as evidence
+	MaxRawEntries     int // warning, error and pattern-matched entries kept for correlation
+}
+
+// DefaultStreamOptions returns the default streaming limits
+func DefaultStreamOptions() StreamOptions {
+	return StreamOptions{
+		MaxPatternSamples: 100,
+		MaxRawEntries:     1000,
+	}
+}
+
+// streamCancelCheckPeriod is the number of entries between context checks
+const streamCancelCheckPeriod = 1000
+
+// StreamAnalyzer incrementally updates pattern counts, timeline buckets and
+// insight statistics as entries arrive. Only aggregates and a bounded sample
+// of entries are retained, so memory does not grow with the input size.
+type StreamAnalyzer struct {
+	engine  *AnalyzerEngine
+	options StreamOptions
+
+	mu           sync.Mutex
+	totalEntries int
+	errorCount   int
+	warnCount    int
+	startTime    time.Time
+	endTime      time.Time
+	patternsByID map[string]*common.Pattern
+	captures     map[string]*regexp.Regexp // regexes with named captures by pattern ID
+	matches      map[string]*PatternMatch
+	thresholds   map[string]*thresholdTracker // by ID of patterns with a threshold
+	sequences    map[string]*sequenceTracker  // by ID of sequence patterns
+	suppressed   suppressionCounts
+	timeline     *timelineAccumulator
+	insights     *insightRun
+	sources      *sourceBreakdown
+	templates    *TemplateMiner  // nil when template mining is disabled
+	profile      *profileBuilder // nil unless the engine profiles analyses
+	rawEntries   []*common.LogEntry
+}
+
+// NewStream creates a streaming analyzer that uses the engine's patterns,
+// timeline and insight settings
+func (e *AnalyzerEngine) NewStream(options StreamOptions) *StreamAnalyzer {
+	patternsByID := make(map[string]*common.Pattern, len(e.patterns))
+	captures := make(map[string]*regexp.Regexp)
+	for _, pattern := range e.patterns {
+		patternsByID[pattern.ID] = pattern
+		if regex := e.matcher.captureRegex(pattern.ID); regex != nil {
+			captures[pattern.ID] = regex
+		}
+	}
+
+	stream := &StreamAnalyzer{
+		engine:       e,
+		options:      options,
+		patternsByID: patternsByID,
+		captures:     captures,
+		matches:      make(map[string]*PatternMatch),
+		thresholds:   make(map[string]*thresholdTracker),
+		sequences:    e.sequenceTrackers(),
+		suppressed:   make(suppressionCounts),
+		timeline:     newTimelineAccumulator(e.timelineGen, e.timelineBucketSize, e.timelineMaxBuckets),
+		insights:     e.insightGen.start(),
+		sources:      newSourceBreakdown(),
+	}
+	for _, pattern := range e.patterns {
+		if pattern.Threshold != nil {
+			stream.thresholds[pattern.ID] = newThresholdTracker(pattern, e.matcher.patternRegex(pattern.ID))
+		}
+	}
+	if e.templateOptions.Limit > 0 {
+		stream.templates = NewTemplateMiner(e.templateOptions)
+	}
+	if e.profiling {
+		stream.profile = newProfileBuilder()
+	}
+	return stream
+}
+
+// AnalyzeStream analyzes every entry produced by source without retaining
+// the full entry list
+func (e *AnalyzerEngine) AnalyzeStream(ctx context.Context, source EntrySource, options StreamOptions) (*Analysis, error) {
+	stream := e.NewStream(options)
+
+	for count := 0; ; count++ {
+		if count%streamCancelCheckPeriod == 0 {
+			select {
+			case <-ctx.Done():
+				return stream.Analysis(), ctx.Err()
+			default:
+			}
+		}
+
+		entry, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stream.Analysis(), err
+		}
+
+		stream.Add(entry)
+	}
+
+	return stream.Analysis(), nil
+}
+
+// Add feeds a single entry into the analysis and returns the IDs of the
+// patterns it matched. Threshold patterns count as matched once the entry
+// crosses or extends one of their bursts, and sequence patterns once the
+// entry completes one of their sequences.
+func (s *StreamAnalyzer) Add(entry *common.LogEntry) []string {
+	matchedIDs, suppressed := s.engine.matcher.matchSingle(entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range suppressed {
+		s.suppressed[key]++
+	}
+
+	matchedIDs = s.applyThresholds(entry, matchedIDs)
+	sequenceIDs := s.applySequences(entry)
+
+	s.totalEntries++
+	// Entries without a timestamp do not widen the time range
+	if !entry.Timestamp.IsZero() {
+		if s.startTime.IsZero() || entry.Timestamp.Before(s.startTime) {
+			s.startTime = entry.Timestamp
+		}
+		if entry.Timestamp.After(s.endTime) {
+			s.endTime = entry.Timestamp
+		}
+	}
+
+	s.recordMatches(entry, matchedIDs)
+	s.updateCounts(entry, matchedIDs)
+	s.timeline.add(entry)
+	s.insights.add(entry)
+	if s.templates != nil && len(matchedIDs) == 0 {
+		s.templates.Add(entry)
+	}
+	if s.profile != nil {
+		s.profile.add(entry)
+	}
+
+	notable := entry.LogLevel >= common.LevelWarn || len(matchedIDs) > 0
+	if notable && len(s.rawEntries) < s.options.MaxRawEntries {
+		s.rawEntries = append(s.rawEntries, entry)
+	}
+
+	return append(matchedIDs, sequenceIDs...)
+}
+
+// applyThresholds feeds threshold pattern matches to their trackers and
+// returns matchedIDs without the threshold patterns that are not in a
+// burst. Entries a burst pulls in from before the current one are
+// recorded as that pattern's evidence.
+func (s *StreamAnalyzer) applyThresholds(entry *common.LogEntry, matchedIDs []string) []string {
+	if len(s.thresholds) == 0 {
+		return matchedIDs
+	}
+
+	reported := matchedIDs[:0:0]
+	for _, id := range matchedIDs {
+		tracker, ok := s.thresholds[id]
+		if !ok {
+			reported = append(reported, id)
+			continue
+		}
+
+		evidence := tracker.add(entry)
+		if len(evidence) == 0 {
+			continue
+		}
+		for _, earlier := range evidence[:len(evidence)-1] {
+			s.recordMatch(id, earlier)
+		}
+		reported = append(reported, id)
+	}
+	return reported
+}
+
+// applySequences feeds an entry to the sequence trackers, records the
+// sequences it completes and returns their pattern IDs
+func (s *StreamAnalyzer) applySequences(entry *common.LogEntry) []string {
+	if len(s.sequences) == 0 {
+		return nil
+	}
+
+	var completedIDs []string
+	se := s.engine.matcher.precomputeSearchText([]*common.LogEntry{entry})[0]
+	for id, tracker := range s.sequences {
+		occurrences := tracker.add(se)
+		if len(occurrences) == 0 {
+			continue
+		}
+		match, exists := s.matches[id]
+		if !exists {
+			match = &PatternMatch{Pattern: s.patternsByID[id], Matches: []*common.LogEntry{}}
+			s.matches[id] = match
+		}
+		for _, occurrence := range occurrences {
+			recordSequence(match, occurrence, s.options.MaxPatternSamples)
+			for _, step := range occurrence.Entries {
+				s.insights.addMatch(id, step)
+			}
+		}
+		completedIDs = append(completedIDs, id)
+	}
+	return completedIDs
+}
+
+// recordMatches updates per-pattern counts and evidence samples
+func (s *StreamAnalyzer) recordMatches(entry *common.LogEntry, matchedIDs []string) {
+	for _, id := range matchedIDs {
+		s.recordMatch(id, entry)
+	}
+}
+
+// recordMatch counts an entry as a match of one pattern
+func (s *StreamAnalyzer) recordMatch(id string, entry *common.LogEntry) {
+	match, exists := s.matches[id]
+	if !exists {
+		match = &PatternMatch{
+			Pattern: s.patternsByID[id],
+			Matches: []*common.LogEntry{},
+		}
+		s.matches[id] = match
+	}
+
+	match.Count++
+	if len(match.Matches) < s.options.MaxPatternSamples {
+		match.Matches = append(match.Matches, entry)
+	}
+	if regex := s.captures[id]; regex != nil {
+		countCaptures(match, captureValues(regex, entry))
+	}
+	s.insights.addMatch(id, entry)
+	if match.FirstSeen.IsZero() || entry.Timestamp.Before(match.FirstSeen) {
+		match.FirstSeen = entry.Timestamp
+	}
+	if match.LastSeen.IsZero() || entry.Timestamp.After(match.LastSeen) {
+		match.LastSeen = entry.Timestamp
+	}
+}
+
+// updateCounts applies the same error/warning accounting as
+// AnalyzerEngine.updateCountsFromPatterns for a single entry
+func (s *StreamAnalyzer) updateCounts(entry *common.LogEntry, matchedIDs []string) {
+	errors, warns := severityCounts(entry, matchedIDs, s.patternsByID)
+	s.errorCount += errors
+	s.warnCount += warns
+	s.sources.add(entry, matchedIDs, errors, warns)
+}
+
+// Analysis returns a snapshot of the analysis so far. It can be called
+// repeatedly while entries are still being added.
+func (s *StreamAnalyzer) Analysis() *Analysis {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.totalEntries == 0 {
+		return &Analysis{
+			Patterns:   []PatternMatch{},
+			Insights:   []Insight{},
+			RawEntries: []*common.LogEntry{},
+		}
+	}
+
+	analysis := &Analysis{
+		StartTime:    s.startTime,
+		EndTime:      s.endTime,
+		TotalEntries: s.totalEntries,
+		ErrorCount:   s.errorCount,
+		WarnCount:    s.warnCount,
+		Patterns:     s.patternSnapshot(),
+		Insights:     []Insight{},
+		Sources:      s.sources.summaries(),
+		Suppressed:   s.suppressedMatches(),
+		RawEntries:   append([]*common.LogEntry(nil), s.rawEntries...),
+	}
+
+	if s.engine.enableInsights {
+		analysis.Insights = s.insights.insights(analysis.Patterns)
+	}
+
+	if s.engine.timelineBucketSize > 0 {
+		analysis.Timeline = s.timeline.timeline()
+	}
+
+	if s.templates != nil {
+		analysis.Templates = s.templates.Templates()
+	}
+
+	if s.profile != nil {
+		analysis.Profile = s.profile.profile(analysis)
+	}
+
+	return analysis
+}
+
+// suppressedMatches totals the matches silenced so far, including the
+// steps of sequence patterns
+func (s *StreamAnalyzer) suppressedMatches() []common.SuppressedMatches {
+	total := make(suppressionCounts)
+	total.merge(s.suppressed)
+	for _, tracker := range s.sequences {
+		total.merge(tracker.suppressed)
+	}
+	return total.list()
+}
+
+// patternSnapshot copies matched patterns in the engine's pattern order
+func (s *StreamAnalyzer) patternSnapshot() []PatternMatch {
+	result := []PatternMatch{}
+	seen := make(map[string]bool, len(s.matches))
+
+	for _, pattern := range s.engine.patterns {
+		match, exists := s.matches[pattern.ID]
+		if !exists || seen[pattern.ID] {
+			continue
+		}
+		seen[pattern.ID] = true
+
+		snapshot := *match
+		snapshot.Matches = append([]*common.LogEntry(nil), match.Matches...)
+		snapshot.Sequences = append([]common.SequenceOccurrence(nil), match.Sequences...)
+		snapshot.Captures = copyCaptures(match.Captures)
+		if tracker, ok := s.thresholds[pattern.ID]; ok {
+			snapshot.Windows = tracker.burstWindows()
+		}
+		result = append(result, snapshot)
+	}
+
+	return result
+}
+
+// copyCaptures deep-copies capture value counts, so snapshots stay
+// unchanged as the stream continues
+func copyCaptures(captures map[string]map[string]int) map[string]map[string]int {
+	if captures == nil {
+		return nil
+	}
+	copied := make(map[string]map[string]int, len(captures))
+	for name, counts := range captures {
+		copied[name] = make(map[string]int, len(counts))
+		for value, count := range counts {
+			copied[name][value] = count
+		}
+	}
+	return copied
+}
@@ -0,0 +1,232 @@
+package analyzer
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// sliceSource feeds a fixed list of entries to a stream
+type sliceSource struct {
+	entries []*common.LogEntry
+	next    int
+}
+
+func (s *sliceSource) Next() (*common.LogEntry, error) {
+	if s.next >= len(s.entries) {
+		return nil, io.EOF
+	}
+	entry := s.entries[s.next]
+	s.next++
+	return entry, nil
+}
+
+func TestAnalyzeStreamMatchesBatch(t *testing.T) {
+	patterns := []*common.Pattern{
+		{ID: "error_kw", Name: "Error", Type: common.PatternTypeError, Keywords: []string{"error"}},
+		{ID: "slow_kw", Name: "Slow", Type: common.PatternTypePerformance, Keywords: []string{"slow"}},
+	}
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	var entries []*common.LogEntry
+	for i := 0; i < 60; i++ {
+		level, levelStr, message := common.LevelInfo, "INFO", "Normal operation"
+		switch {
+		case i >= 40:
+			level, levelStr, message = common.LevelError, "ERROR", "Error occurred"
+		case i%10 == 0:
+			message = "slow query detected"
+		case i%7 == 0:
+			message = "unexpected error in handler"
+		}
+		entries = append(entries, createTestEntry(baseTime.Add(time.Duration(i)*30*time.Second), level, levelStr, message))
+	}
+
+	batchEngine := NewEngine()
+	if err := batchEngine.SetPatterns(patterns); err != nil {
+		t.Fatalf("Failed to set patterns: %v", err)
+	}
+	batch, err := batchEngine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Batch analysis failed: %v", err)
+	}
+
+	streamEngine := NewEngine()
+	if err := streamEngine.SetPatterns(patterns); err != nil {
+		t.Fatalf("Failed to set patterns: %v", err)
+	}
+	options := StreamOptions{MaxPatternSamples: 3, MaxRawEntries: 5}
+	stream, err := streamEngine.AnalyzeStream(context.Background(), &sliceSource{entries: entries}, options)
+	if err != nil {
+		t.Fatalf("Stream analysis failed: %v", err)
+	}
+
+	if stream.TotalEntries != batch.TotalEntries {
+		t.Errorf("TotalEntries: stream %d, batch %d", stream.TotalEntries, batch.TotalEntries)
+	}
+	if stream.ErrorCount != batch.ErrorCount {
+		t.Errorf("ErrorCount: stream %d, batch %d", stream.ErrorCount, batch.ErrorCount)
+	}
+	if stream.WarnCount != batch.WarnCount {
+		t.Errorf("WarnCount: stream %d, batch %d", stream.WarnCount, batch.WarnCount)
+	}
+	if !stream.StartTime.Equal(batch.StartTime) || !stream.EndTime.Equal(batch.EndTime) {
+		t.Errorf("Time range differs: stream %v-%v, batch %v-%v",
+			stream.StartTime, stream.EndTime, batch.StartTime, batch.EndTime)
+	}
+	if len(stream.Insights) != len(batch.Insights) {
+		t.Errorf("Insights: stream %d, batch %d", len(stream.Insights), len(batch.Insights))
+	}
+
+	batchCounts := make(map[string]int)
+	for _, match := range batch.Patterns {
+		batchCounts[match.Pattern.ID] = match.Count
+	}
+	for _, match := range stream.Patterns {
+		if match.Count != batchCounts[match.Pattern.ID] {
+			t.Errorf("Pattern %s: stream count %d, batch count %d",
+				match.Pattern.ID, match.Count, batchCounts[match.Pattern.ID])
+		}
+		if len(match.Matches) > options.MaxPatternSamples {
+			t.Errorf("Pattern %s kept %d samples, limit is %d",
+				match.Pattern.ID, len(match.Matches), options.MaxPatternSamples)
+		}
+	}
+	if len(stream.Patterns) != len(batch.Patterns) {
+		t.Errorf("Patterns: stream %d, batch %d", len(stream.Patterns), len(batch.Patterns))
+	}
+
+	if len(stream.RawEntries) > options.MaxRawEntries {
+		t.Errorf("Kept %d raw entries, limit is %d", len(stream.RawEntries), options.MaxRawEntries)
+	}
+
+	if len(stream.Timeline.Buckets) != len(batch.Timeline.Buckets) {
+		t.Fatalf("Timeline buckets: stream %d, batch %d", len(stream.Timeline.Buckets), len(batch.Timeline.Buckets))
+	}
+	for i := range batch.Timeline.Buckets {
+		if stream.Timeline.Buckets[i] != batch.Timeline.Buckets[i] {
+			t.Errorf("Bucket %d differs: stream %+v, batch %+v", i, stream.Timeline.Buckets[i], batch.Timeline.Buckets[i])
+		}
+	}
+}
+
+func TestStreamAnalyzerSnapshot(t *testing.T) {
+	engine := NewEngine()
+	stream := engine.NewStream(DefaultStreamOptions())
+
+	empty := stream.Analysis()
+	if empty.TotalEntries != 0 || empty.Patterns == nil {
+		t.Errorf("Expected empty analysis, got %+v", empty)
+	}
+
+	stream.Add(createTestEntry(time.Now(), common.LevelError, "ERROR", "boom"))
+	first := stream.Analysis()
+	stream.Add(createTestEntry(time.Now(), common.LevelInfo, "INFO", "ok"))
+	second := stream.Analysis()
+
+	if first.TotalEntries != 1 || second.TotalEntries != 2 {
+		t.Errorf("Expected snapshots of 1 and 2 entries, got %d and %d", first.TotalEntries, second.TotalEntries)
+	}
+	if second.ErrorCount != 1 {
+		t.Errorf("Expected 1 error, got %d", second.ErrorCount)
+	}
+}
+
+func TestSourceBreakdown(t *testing.T) {
+	patterns := []*common.Pattern{
+		{ID: "timeout", Name: "Timeout", Type: common.PatternTypeError, Keywords: []string{"timeout"}},
+	}
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newEntry := func(offset int, source string, level common.LogLevel, levelStr, message string) *common.LogEntry {
+		entry := createTestEntry(baseTime.Add(time.Duration(offset)*time.Second), level, levelStr, message)
+		entry.Source = source
+		return entry
+	}
+	entries := []*common.LogEntry{
+		newEntry(0, "api.log", common.LevelInfo, "INFO", "request timeout"),
+		newEntry(1, "worker.log", common.LevelError, "ERROR", "job failed"),
+		newEntry(2, "api.log", common.LevelWarn, "WARN", "slow request"),
+		newEntry(3, "worker.log", common.LevelError, "ERROR", "job timeout"),
+	}
+
+	engine := NewEngine()
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("Failed to set patterns: %v", err)
+	}
+
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Batch analysis failed: %v", err)
+	}
+	stream, err := engine.AnalyzeStream(context.Background(), &sliceSource{entries: entries}, DefaultStreamOptions())
+	if err != nil {
+		t.Fatalf("Stream analysis failed: %v", err)
+	}
+
+	for _, analysis := range []*Analysis{batch, stream} {
+		if len(analysis.Sources) != 2 {
+			t.Fatalf("Expected 2 sources, got %d", len(analysis.Sources))
+		}
+
+		api, worker := analysis.Sources[0], analysis.Sources[1]
+		if api.Source != "api.log" || worker.Source != "worker.log" {
+			t.Fatalf("Expected sources sorted by name, got %s, %s", api.Source, worker.Source)
+		}
+		if api.TotalEntries != 2 || api.ErrorCount != 1 || api.WarnCount != 1 {
+			t.Errorf("Unexpected api.log breakdown: %+v", api)
+		}
+		if worker.TotalEntries != 2 || worker.ErrorCount != 2 || worker.PatternCounts["timeout"] != 1 {
+			t.Errorf("Unexpected worker.log breakdown: %+v", worker)
+		}
+	}
+}
+
+func TestSourceBreakdownSingleSource(t *testing.T) {
+	entry := createTestEntry(time.Now(), common.LevelError, "ERROR", "failed")
+	entry.Source = "app.log"
+
+	analysis, err := NewEngine().Analyze(context.Background(), []*common.LogEntry{entry})
+	if err != nil {
+		t.Fatalf("Analysis failed: %v", err)
+	}
+	if analysis.Sources != nil {
+		t.Errorf("Expected no breakdown for a single source, got %+v", analysis.Sources)
+	}
+}
+
+func TestTimelineSkipsEntriesWithoutTimestamp(t *testing.T) {
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []*common.LogEntry{
+		createTestEntry(time.Time{}, common.LevelError, "ERROR", "unparsed time"),
+		createTestEntry(baseTime, common.LevelInfo, "INFO", "first"),
+		createTestEntry(baseTime.Add(5*time.Minute), common.LevelError, "ERROR", "second"),
+		createTestEntry(time.Time{}, common.LevelInfo, "INFO", "unparsed again"),
+	}
+
+	engine := NewEngine()
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream, err := engine.AnalyzeStream(context.Background(), &sliceSource{entries: entries}, DefaultStreamOptions())
+	if err != nil {
+		t.Fatalf("AnalyzeStream() failed: %v", err)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream} {
+		if analysis.TotalEntries != 4 || analysis.Timeline == nil || len(analysis.Timeline.Buckets) == 0 {
+			t.Fatalf("%s: analysis = %d entries, timeline %+v", name, analysis.TotalEntries, analysis.Timeline)
+		}
+		counted := 0
+		for _, bucket := range analysis.Timeline.Buckets {
+			counted += bucket.EntryCount
+		}
+		if counted != 2 || !analysis.Timeline.Buckets[0].Start.Equal(baseTime) {
+			t.Errorf("%s: timeline counts %d entries from %v, want 2 from %v", name, counted, analysis.Timeline.Buckets[0].Start, baseTime)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/yildizm/LogSum/internal/ai"
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-promptfmt"
+)
+
+// suggestedTag marks patterns written by the suggester
+const suggestedTag = "suggested"
+
+// SuggestOptions configures pattern suggestion
+type SuggestOptions struct {
+	MinCount int // entries a template needs before it is suggested
+	Limit    int // suggestions returned, most frequent first
+}
+
+// DefaultSuggestOptions returns the default suggestion settings
+func DefaultSuggestOptions() SuggestOptions {
+	return SuggestOptions{MinCount: 2, Limit: 20}
+}
+
+// PatternSuggestion is a proposed pattern and the template it came from
+type PatternSuggestion struct {
+	Pattern  *common.Pattern
+	Template common.LogTemplate
+}
+
+// PatternSuggester clusters the ERROR and FATAL entries that none of the
+// engine's patterns match and proposes a pattern for each cluster
+type PatternSuggester struct {
+	engine  *AnalyzerEngine
+	options SuggestOptions
+	miner   *TemplateMiner
+}
+
+// NewPatternSuggester creates a suggester that skips entries the engine's
+// patterns already match
+func (e *AnalyzerEngine) NewPatternSuggester(options SuggestOptions) *PatternSuggester {
+	templateOptions := DefaultTemplateOptions()
+	templateOptions.Limit = templateOptions.MaxClusters
+	return &PatternSuggester{
+		engine:  e,
+		options: options,
+		miner:   NewTemplateMiner(templateOptions),
+	}
+}
+
+// Add considers an entry for suggestion
+func (s *PatternSuggester) Add(entry *common.LogEntry) {
+	if entry.LogLevel < common.LevelError {
+		return
+	}
+	if len(s.engine.matcher.MatchSingle(entry)) > 0 {
+		return
+	}
+	s.miner.Add(entry)
+}
+
+// Suggestions returns a pattern for each template seen at least MinCount
+// times. IDs are unique among the suggestions and the engine's patterns.
+func (s *PatternSuggester) Suggestions() []PatternSuggestion {
+	taken := s.takenIDs()
+	var suggestions []PatternSuggestion
+	for _, template := range s.miner.Templates() {
+		if template.Count < s.options.MinCount || (s.options.Limit > 0 && len(suggestions) >= s.options.Limit) {
+			continue
+		}
+		pattern := patternFromTemplate(template)
+		pattern.ID = uniqueID(pattern.ID, taken)
+		suggestions = append(suggestions, PatternSuggestion{Pattern: pattern, Template: template})
+	}
+	return suggestions
+}
+
+// takenIDs returns the IDs of the engine's patterns
+func (s *PatternSuggester) takenIDs() map[string]bool {
+	taken := make(map[string]bool, len(s.engine.patterns))
+	for _, pattern := range s.engine.patterns {
+		taken[pattern.ID] = true
+	}
+	return taken
+}
+
+// patternFromTemplate builds an error pattern matching a template. Fixed
+// templates become a keyword; templates with wildcards become a regex.
+func patternFromTemplate(template common.LogTemplate) *common.Pattern {
+	words := templateWords(template.Template)
+	pattern := &common.Pattern{
+		ID:       patternID(words, template.ID),
+		Name:     patternName(words),
+		Type:     common.PatternTypeError,
+		Severity: template.Level,
+		Tags:     []string{suggestedTag},
+	}
+
+	if strings.Contains(template.Template, templateWildcard) {
+		pattern.Regex = templateRegex(template.Template)
+	} else {
+		pattern.Keywords = []string{template.Template}
+	}
+
+	description := fmt.Sprintf("Suggested from %d unmatched %s entries", template.Count, template.Level)
+	if len(template.Examples) > 0 {
+		description += fmt.Sprintf(", e.g. %q", truncate(firstLine(template.Examples[0].Message), 120))
+	}
+	pattern.Description = description
+
+	if service := commonService(template.Examples); service != "" {
+		pattern.Tags = append(pattern.Tags, strings.ToLower(service))
+	}
+	return pattern
+}
+
+// templateRegex turns a template into a regex, with wildcards matching a
+// single token and any run of whitespace between tokens
+func templateRegex(template string) string {
+	tokens := strings.Fields(template)
+	parts := make([]string, len(tokens))
+	for i, token := range tokens {
+		literals := strings.Split(token, templateWildcard)
+		for j := range literals {
+			literals[j] = regexp.QuoteMeta(literals[j])
+		}
+		parts[i] = strings.Join(literals, `\S+`)
+	}
+	return strings.Join(parts, `\s+`)
+}
+
+// templateWords returns the fixed words of a template, without wildcards
+// and punctuation
+func templateWords(template string) []string {
+	var words []string
+	for _, token := range strings.Fields(strings.ReplaceAll(template, templateWildcard, " ")) {
+		word := strings.TrimFunc(token, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// nonIDChars are the characters replaced in generated pattern IDs
+var nonIDChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// patternID builds a snake_case ID from the first words of a template,
+// falling back to the template ID
+func patternID(words []string, templateID string) string {
+	if len(words) > 4 {
+		words = words[:4]
+	}
+	id := strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(strings.Join(words, " ")), "_"), "_")
+	if id == "" {
+		return "suggested_" + strings.ToLower(templateID)
+	}
+	return id
+}
+
+// patternName builds a readable name from the first words of a template
+func patternName(words []string) string {
+	if len(words) == 0 {
+		return "Suggested Pattern"
+	}
+	if len(words) > 6 {
+		words = words[:6]
+	}
+	name := strings.Join(words, " ")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// uniqueID returns id, or id with a numeric suffix if it is taken, and
+// marks the result as taken
+func uniqueID(id string, taken map[string]bool) string {
+	candidate := id
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s_%d", id, n)
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// commonService returns the service shared by all entries, if any
+func commonService(entries []*common.LogEntry) string {
+	service := ""
+	for i, entry := range entries {
+		if i > 0 && entry.Service != service {
+			return ""
+		}
+		service = entry.Service
+	}
+	return service
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// truncate shortens s to at most maxLen characters
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+// suggestionNaming is the reply expected when asking an AI provider to
+// name suggested patterns
+type suggestionNaming struct {
+	Patterns []struct {
+		Index       int      `json:"index"`
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Tags        []string `json:"tags"`
+	} `json:"patterns"`
+}
+
+// NameWithAI asks an AI provider for IDs, names, descriptions and tags for
+// the suggestions. Matching rules are left as generated, and suggestions
+// the reply does not cover keep their generated names.
+func (s *PatternSuggester) NameWithAI(ctx context.Context, provider ai.Provider, suggestions []PatternSuggestion) error {
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	request := ai.AnalysisRequest{
+		AnalysisType: ai.AnalysisTypePatternExtraction,
+		MaxResults:   len(suggestions),
+	}
+	for i, suggestion := range suggestions {
+		line := fmt.Sprintf("%d. [%d entries] %s", i+1, suggestion.Template.Count, suggestion.Template.Template)
+		if len(suggestion.Template.Examples) > 0 {
+			line += fmt.Sprintf("\n   example: %s", truncate(firstLine(suggestion.Template.Examples[0].Message), 200))
+		}
+		request.LogEntries = append(request.LogEntries, line)
+	}
+
+	prompt := promptfmt.New().
+		System("You are a LogSum AI assistant that writes log detection patterns. Name recurring error messages the way an on-call engineer would.").
+		User("These error message templates were found in logs that no existing pattern covers. <*> marks a variable token. "+
+			"For each numbered template, propose a short snake_case id, a concise title-case name, a one-sentence description of the failure, and up to 3 lowercase tags.\n\n%s",
+			strings.Join(request.LogEntries, "\n")).
+		ExpectJSON(&suggestionNaming{}).
+		Build()
+
+	resp, err := provider.Complete(ctx, &ai.CompletionRequest{
+		Prompt:       prompt.String(),
+		SystemPrompt: prompt.SystemPrompt,
+		MaxTokens:    150 * request.MaxResults,
+		Temperature:  0.2,
+		Metadata:     map[string]string{"analysis_type": string(request.AnalysisType)},
+	})
+	if err != nil {
+		return err
+	}
+
+	var naming suggestionNaming
+	if !promptfmt.NewResponse(resp.Content).TryParseJSON(&naming).Success {
+		return fmt.Errorf("could not parse pattern names from AI response")
+	}
+
+	named := make(map[int]bool, len(naming.Patterns))
+	for _, proposal := range naming.Patterns {
+		i := proposal.Index - 1
+		if i < 0 || i >= len(suggestions) || named[i] {
+			continue
+		}
+		named[i] = true
+		pattern := suggestions[i].Pattern
+		if id := strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(proposal.ID), "_"), "_"); id != "" {
+			pattern.ID = id
+		}
+		if proposal.Name != "" {
+			pattern.Name = proposal.Name
+		}
+		if proposal.Description != "" {
+			pattern.Description = proposal.Description
+		}
+		for _, tag := range proposal.Tags {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" && !containsString(pattern.Tags, tag) {
+				pattern.Tags = append(pattern.Tags, tag)
+			}
+		}
+	}
+
+	// Proposed IDs may collide with each other or with loaded patterns
+	taken := s.takenIDs()
+	for _, suggestion := range suggestions {
+		suggestion.Pattern.ID = uniqueID(suggestion.Pattern.ID, taken)
+	}
+	return nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/ai"
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func suggestionEntries() []*common.LogEntry {
+	entries := templateEntries(
+		"payment gateway returned status 502 for order 1001",
+		"payment gateway returned status 503 for order 1002",
+		"connection refused by db-1",
+		"connection refused by db-2",
+		"ledger checksum mismatch",
+		"ledger checksum mismatch",
+		"disk quota exceeded on vol7",
+		"payment gateway returned status 200 for order 1003",
+	)
+	for i, entry := range entries[:7] {
+		entry.LogLevel = common.LevelError
+		entry.Service = "payments"
+		if i == 5 {
+			entry.LogLevel = common.LevelFatal
+		}
+	}
+	return entries
+}
+
+func newTestSuggester(t *testing.T, options SuggestOptions) *PatternSuggester {
+	t.Helper()
+	engine := NewEngine()
+	patterns := []*common.Pattern{
+		{ID: "conn", Name: "Connection", Type: common.PatternTypeError, Regex: "connection refused"},
+		{ID: "ledger_checksum_mismatch", Name: "Taken", Type: common.PatternTypeError, Keywords: []string{"no such message"}},
+	}
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+	suggester := engine.NewPatternSuggester(options)
+	for _, entry := range suggestionEntries() {
+		suggester.Add(entry)
+	}
+	return suggester
+}
+
+func TestPatternSuggestions(t *testing.T) {
+	suggestions := newTestSuggester(t, DefaultSuggestOptions()).Suggestions()
+	if len(suggestions) != 2 {
+		t.Fatalf("Suggestions() returned %d suggestions, want 2: %+v", len(suggestions), suggestions)
+	}
+
+	gateway := suggestions[0].Pattern
+	if gateway.ID != "payment_gateway_returned_status" || gateway.Severity != common.LevelError {
+		t.Errorf("gateway pattern = %s (%v), want payment_gateway_returned_status (ERROR)", gateway.ID, gateway.Severity)
+	}
+	if !reflect.DeepEqual(gateway.Tags, []string{"suggested", "payments"}) {
+		t.Errorf("gateway tags = %v, want [suggested payments]", gateway.Tags)
+	}
+	re, err := regexp.Compile(gateway.Regex)
+	if err != nil {
+		t.Fatalf("regexp.Compile(%q) failed: %v", gateway.Regex, err)
+	}
+	for _, example := range suggestions[0].Template.Examples {
+		if !re.MatchString(example.Message) {
+			t.Errorf("regex %q does not match %q", gateway.Regex, example.Message)
+		}
+	}
+
+	ledger := suggestions[1].Pattern
+	if ledger.ID != "ledger_checksum_mismatch_2" {
+		t.Errorf("ledger ID = %s, want ledger_checksum_mismatch_2", ledger.ID)
+	}
+	if ledger.Regex != "" || !reflect.DeepEqual(ledger.Keywords, []string{"ledger checksum mismatch"}) {
+		t.Errorf("ledger pattern = regex %q keywords %v, want the template as keyword", ledger.Regex, ledger.Keywords)
+	}
+	if ledger.Severity != common.LevelFatal {
+		t.Errorf("ledger severity = %v, want FATAL", ledger.Severity)
+	}
+}
+
+func TestPatternSuggestionsOptions(t *testing.T) {
+	all := newTestSuggester(t, SuggestOptions{MinCount: 1}).Suggestions()
+	if len(all) != 3 {
+		t.Errorf("Suggestions() with MinCount 1 returned %d suggestions, want 3", len(all))
+	}
+	limited := newTestSuggester(t, SuggestOptions{MinCount: 1, Limit: 1}).Suggestions()
+	if len(limited) != 1 || limited[0].Template.Count != 2 {
+		t.Errorf("Suggestions() with Limit 1 = %+v, want the most frequent template", limited)
+	}
+}
+
+func TestPatternSuggesterNameWithAI(t *testing.T) {
+	suggester := newTestSuggester(t, DefaultSuggestOptions())
+	suggestions := suggester.Suggestions()
+
+	var analysisType string
+	provider := &MockProvider{
+		name: "test-provider",
+		completionFunc: func(ctx context.Context, req *ai.CompletionRequest) (*ai.CompletionResponse, error) {
+			analysisType = req.Metadata["analysis_type"]
+			if !strings.Contains(req.Prompt, "payment gateway returned status <*> for order <*>") {
+				t.Errorf("prompt does not list the templates: %s", req.Prompt)
+			}
+			return &ai.CompletionResponse{Content: `{"patterns": [
+				{"index": 1, "id": "Gateway Error", "name": "Payment Gateway Error", "description": "The payment gateway rejected a request.", "tags": ["Gateway", "suggested"]},
+				{"index": 2, "id": "conn", "name": "Ledger Corruption"},
+				{"index": 9, "id": "ignored"}
+			]}`, CreatedAt: time.Now()}, nil
+		},
+	}
+
+	if err := suggester.NameWithAI(context.Background(), provider, suggestions); err != nil {
+		t.Fatalf("NameWithAI() failed: %v", err)
+	}
+	if analysisType != string(ai.AnalysisTypePatternExtraction) {
+		t.Errorf("analysis type = %q, want %q", analysisType, ai.AnalysisTypePatternExtraction)
+	}
+
+	gateway := suggestions[0].Pattern
+	if gateway.ID != "gateway_error" || gateway.Name != "Payment Gateway Error" || gateway.Description != "The payment gateway rejected a request." {
+		t.Errorf("gateway pattern = %s %q %q, want the AI naming", gateway.ID, gateway.Name, gateway.Description)
+	}
+	if !reflect.DeepEqual(gateway.Tags, []string{"suggested", "payments", "gateway"}) {
+		t.Errorf("gateway tags = %v, want [suggested payments gateway]", gateway.Tags)
+	}
+	if gateway.Regex == "" {
+		t.Error("NameWithAI() cleared the regex")
+	}
+
+	ledger := suggestions[1].Pattern
+	if ledger.ID != "conn_2" || ledger.Name != "Ledger Corruption" {
+		t.Errorf("ledger pattern = %s %q, want conn_2 \"Ledger Corruption\"", ledger.ID, ledger.Name)
+	}
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/filter"
+)
+
+// excludeRule is the rule name under which matches dropped by a pattern's
+// own exclude regexes are counted
+const excludeRule = "exclude"
+
+// compiledSuppression is a suppression rule prepared for matching
+type compiledSuppression struct {
+	rule      *common.SuppressionRule
+	regex     *regexp.Regexp
+	condition *filter.Expression
+	since     time.Time
+	until     time.Time
+}
+
+// compileSuppression validates a rule and compiles its criteria. Relative
+// times are resolved against the current time.
+func compileSuppression(rule *common.SuppressionRule) (*compiledSuppression, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	cs := &compiledSuppression{rule: rule}
+	if rule.Regex != "" {
+		regex, err := regexp.Compile("(?i)" + rule.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("suppression rule %s: invalid regex: %w", rule.ID, err)
+		}
+		cs.regex = regex
+	}
+	if rule.Condition != "" {
+		condition, err := filter.Parse(rule.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("suppression rule %s: invalid condition: %w", rule.ID, err)
+		}
+		cs.condition = condition
+	}
+	for _, source := range rule.Sources {
+		if _, err := filepath.Match(source, ""); err != nil {
+			return nil, fmt.Errorf("suppression rule %s: invalid source %q: %w", rule.ID, source, err)
+		}
+	}
+
+	now := time.Now()
+	var err error
+	if rule.Since != "" {
+		if cs.since, err = filter.ParseTime(rule.Since, now); err != nil {
+			return nil, fmt.Errorf("suppression rule %s: invalid since: %w", rule.ID, err)
+		}
+	}
+	if rule.Until != "" {
+		if cs.until, err = filter.ParseTime(rule.Until, now); err != nil {
+			return nil, fmt.Errorf("suppression rule %s: invalid until: %w", rule.ID, err)
+		}
+	}
+	return cs, nil
+}
+
+// compileSuppressions compiles the suppression rules of a pattern
+func (cp *compiledPattern) compileSuppressions() error {
+	for _, rule := range cp.pattern.Suppressions {
+		suppression, err := compileSuppression(rule)
+		if err != nil {
+			return err
+		}
+		cp.suppressions = append(cp.suppressions, suppression)
+	}
+	return nil
+}
+
+// match reports whether an entry satisfies every criterion of the rule.
+// Like --since and --until, both ends of the time window are inclusive.
+func (s *compiledSuppression) match(entry *common.LogEntry) bool {
+	if !s.since.IsZero() && entry.Timestamp.Before(s.since) {
+		return false
+	}
+	if !s.until.IsZero() && entry.Timestamp.After(s.until) {
+		return false
+	}
+	if len(s.rule.Sources) > 0 && !matchSource(s.rule.Sources, entry.Source) {
+		return false
+	}
+	if s.regex != nil && !s.regex.MatchString(entry.Message) && !s.regex.MatchString(entry.Raw) {
+		return false
+	}
+	return s.condition == nil || s.condition.Match(entry)
+}
+
+// matchSource reports whether a source, or its base name, matches one of
+// the glob patterns
+func matchSource(globs []string, source string) bool {
+	for _, glob := range globs {
+		if ok, _ := filepath.Match(glob, source); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(glob, filepath.Base(source)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressedBy returns the rule that silences a pattern's match of an
+// entry: excludeRule for the pattern's exclude regexes, else the ID of the
+// first suppression rule the entry satisfies, or "" if none does
+func suppressedBy(entry *common.LogEntry, cp *compiledPattern) string {
+	for _, exclude := range cp.exclude {
+		if exclude.MatchString(entry.Message) || exclude.MatchString(entry.Raw) {
+			return excludeRule
+		}
+	}
+	for _, suppression := range cp.suppressions {
+		if suppression.match(entry) {
+			return suppression.rule.ID
+		}
+	}
+	return ""
+}
+
+// suppressionKey identifies the matches of one pattern silenced by one rule
+type suppressionKey struct {
+	rule    string
+	pattern string
+}
+
+// suppressionCounts counts silenced matches by rule and pattern
+type suppressionCounts map[suppressionKey]int
+
+// add counts one silenced match
+func (c suppressionCounts) add(rule, pattern string) {
+	c[suppressionKey{rule: rule, pattern: pattern}]++
+}
+
+// merge adds the counts of another set
+func (c suppressionCounts) merge(other suppressionCounts) {
+	for key, count := range other {
+		c[key] += count
+	}
+}
+
+// list returns the counts, largest first
+func (c suppressionCounts) list() []common.SuppressedMatches {
+	if len(c) == 0 {
+		return nil
+	}
+	result := make([]common.SuppressedMatches, 0, len(c))
+	for key, count := range c {
+		result = append(result, common.SuppressedMatches{Rule: key.rule, Pattern: key.pattern, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if result[i].Rule != result[j].Rule {
+			return result[i].Rule < result[j].Rule
+		}
+		return result[i].Pattern < result[j].Pattern
+	})
+	return result
+}
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func TestSuppressionRules(t *testing.T) {
+	entries := burstEntries("health probe: connection refused by db-1", 0, 10)
+	entries = append(entries, burstEntries("query failed: connection refused by db-1", 20, 30)...)
+	entries = append(entries, burstEntries("query failed: connection refused by db-2", 40)...)
+	entries = append(entries, burstEntries("disk full", 50, 60)...)
+	entries[3].Source = "/var/log/canary.log"
+	entries[5].Service = "batch"
+
+	base := entries[0].Timestamp
+	rules := []*common.SuppressionRule{
+		{ID: "health", Regex: "health probe", Patterns: []string{"refused"}},
+		{ID: "canary", Sources: []string{"canary.*"}},
+		{ID: "batch", Condition: "service=batch", Since: base.Add(45 * time.Second).Format(time.RFC3339), Until: base.Add(50 * time.Second).Format(time.RFC3339)},
+		{ID: "unused", Regex: "disk full", Patterns: []string{"other"}},
+	}
+	patterns := []*common.Pattern{
+		{ID: "refused", Name: "Refused", Type: common.PatternTypeError, Keywords: []string{"connection refused"}, Exclude: []string{"db-2"}},
+		{ID: "disk", Name: "Disk", Type: common.PatternTypeError, Keywords: []string{"disk full"}},
+	}
+	common.AttachSuppressions(patterns, rules)
+
+	engine := NewEngine()
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range entries {
+		stream.Add(entry)
+	}
+
+	want := []common.SuppressedMatches{
+		{Rule: "health", Pattern: "refused", Count: 2},
+		{Rule: "batch", Pattern: "disk", Count: 1},
+		{Rule: "canary", Pattern: "refused", Count: 1},
+		{Rule: "exclude", Pattern: "refused", Count: 1},
+	}
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		counts := make(map[string]int)
+		for _, match := range analysis.Patterns {
+			counts[match.Pattern.ID] = match.Count
+		}
+		if counts["refused"] != 1 || counts["disk"] != 1 {
+			t.Errorf("%s: counts = %v, want one unsuppressed match of each pattern", name, counts)
+		}
+		if len(analysis.Suppressed) != len(want) {
+			t.Fatalf("%s: suppressed = %+v, want %+v", name, analysis.Suppressed, want)
+		}
+		for i := range want {
+			if analysis.Suppressed[i] != want[i] {
+				t.Errorf("%s: suppressed[%d] = %+v, want %+v", name, i, analysis.Suppressed[i], want[i])
+			}
+		}
+	}
+}
+
+func TestSuppressedSequenceSteps(t *testing.T) {
+	entries := sequenceEntries()
+	entries[2].Service = "canary"
+	pattern := &common.Pattern{
+		ID: "deploy", Name: "Failed Deploy", Type: common.PatternTypeSequence,
+		Sequence: &common.Sequence{
+			Steps:  []common.SequenceStep{{Keywords: []string{"deploy started"}}, {Regex: "probe failed"}},
+			MaxGap: 2 * time.Minute,
+		},
+		Suppressions: []*common.SuppressionRule{{ID: "canary", Condition: "service=canary"}},
+	}
+	engine := NewEngine()
+	if err := engine.SetPatterns([]*common.Pattern{pattern}); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+
+	analysis, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	if len(analysis.Patterns) != 0 {
+		t.Errorf("got %d sequence matches, want none once the probe failure is silenced", len(analysis.Patterns))
+	}
+	if len(analysis.Suppressed) != 1 || analysis.Suppressed[0].Count != 1 {
+		t.Errorf("suppressed = %+v, want one silenced canary step", analysis.Suppressed)
+	}
+}
+
+func TestInvalidSuppressionRule(t *testing.T) {
+	for _, rule := range []*common.SuppressionRule{
+		{ID: "empty"},
+		{ID: "regex", Regex: "("},
+		{ID: "condition", Condition: "level>=LOUD"},
+		{ID: "source", Sources: []string{"["}},
+		{ID: "since", Since: "whenever"},
+	} {
+		pattern := &common.Pattern{ID: "p", Keywords: []string{"x"}, Suppressions: []*common.SuppressionRule{rule}}
+		if err := NewEngine().SetPatterns([]*common.Pattern{pattern}); err == nil {
+			t.Errorf("SetPatterns() with rule %s succeeded, want error", rule.ID)
+		}
+	}
+}
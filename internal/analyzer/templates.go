@@ -0,0 +1,221 @@
+package analyzer
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// templateWildcard replaces tokens that vary between messages of a template
+const templateWildcard = "<*>"
+
+// TemplateOptions configures template mining
+type TemplateOptions struct {
+	Limit       int     // templates reported, 0 disables mining
+	Depth       int     // prefix tree depth, counting the token-count level and the leaf
+	Similarity  float64 // share of equal tokens needed to join a template
+	MaxChildren int     // children per tree node before tokens share a wildcard branch
+	MaxClusters int     // templates tracked, so memory stays bounded on diverse input
+	MaxExamples int     // example entries kept per template
+}
+
+// DefaultTemplateOptions returns the default mining settings, which follow
+// the parameters recommended for Drain
+func DefaultTemplateOptions() TemplateOptions {
+	return TemplateOptions{
+		Limit:       10,
+		Depth:       4,
+		Similarity:  0.4,
+		MaxChildren: 100,
+		MaxClusters: 5000,
+		MaxExamples: 3,
+	}
+}
+
+// TemplateMiner clusters log messages into templates with the Drain
+// algorithm: messages are routed through a fixed-depth prefix tree by token
+// count and leading tokens, then joined to the most similar template in the
+// leaf, masking the tokens where they differ.
+type TemplateMiner struct {
+	options  TemplateOptions
+	roots    map[int]*drainNode
+	clusters []*templateCluster
+}
+
+// drainNode is an inner node of the prefix tree, or a leaf holding clusters
+type drainNode struct {
+	children map[string]*drainNode
+	clusters []*templateCluster
+}
+
+// templateCluster is one mined template and what it has matched
+type templateCluster struct {
+	tokens   []string
+	template common.LogTemplate
+}
+
+// NewTemplateMiner creates an empty miner
+func NewTemplateMiner(options TemplateOptions) *TemplateMiner {
+	return &TemplateMiner{
+		options: options,
+		roots:   make(map[int]*drainNode),
+	}
+}
+
+// Add clusters the first line of an entry's message
+func (m *TemplateMiner) Add(entry *common.LogEntry) {
+	message, _, _ := strings.Cut(entry.Message, "\n")
+	tokens := templateTokens(message)
+	if len(tokens) == 0 {
+		return
+	}
+
+	leaf := m.leaf(tokens)
+	cluster := m.closestCluster(leaf, tokens)
+	if cluster == nil {
+		if len(m.clusters) >= m.options.MaxClusters {
+			return
+		}
+		cluster = &templateCluster{tokens: tokens}
+		leaf.clusters = append(leaf.clusters, cluster)
+		m.clusters = append(m.clusters, cluster)
+	} else {
+		for i, token := range tokens {
+			if cluster.tokens[i] != token {
+				cluster.tokens[i] = templateWildcard
+			}
+		}
+	}
+	m.record(cluster, entry)
+}
+
+// leaf walks the prefix tree to the leaf for tokens, creating nodes as
+// needed. Once a node is full, further tokens share its wildcard child.
+func (m *TemplateMiner) leaf(tokens []string) *drainNode {
+	node, ok := m.roots[len(tokens)]
+	if !ok {
+		node = &drainNode{children: make(map[string]*drainNode)}
+		m.roots[len(tokens)] = node
+	}
+
+	for i := 0; i < m.options.Depth-2 && i < len(tokens); i++ {
+		key := tokens[i]
+		child, ok := node.children[key]
+		if !ok {
+			if len(node.children) >= m.options.MaxChildren {
+				key = templateWildcard
+				child = node.children[key]
+			}
+			if child == nil {
+				child = &drainNode{children: make(map[string]*drainNode)}
+				node.children[key] = child
+			}
+		}
+		node = child
+	}
+	return node
+}
+
+// closestCluster returns the leaf cluster most similar to tokens, or nil
+// when none reaches the similarity threshold. Ties go to the template
+// with more wildcards, which is the more general one.
+func (m *TemplateMiner) closestCluster(leaf *drainNode, tokens []string) *templateCluster {
+	var best *templateCluster
+	bestSimilarity, bestWildcards := -1.0, -1
+	for _, cluster := range leaf.clusters {
+		equal, wildcards := 0, 0
+		for i, token := range cluster.tokens {
+			switch {
+			case token == templateWildcard:
+				wildcards++
+			case token == tokens[i]:
+				equal++
+			}
+		}
+		similarity := float64(equal) / float64(len(tokens))
+		if similarity > bestSimilarity || (similarity == bestSimilarity && wildcards > bestWildcards) {
+			best, bestSimilarity, bestWildcards = cluster, similarity, wildcards
+		}
+	}
+	if best == nil || bestSimilarity < m.options.Similarity {
+		return nil
+	}
+	return best
+}
+
+// record updates a cluster's counts, level, time range and examples
+func (m *TemplateMiner) record(cluster *templateCluster, entry *common.LogEntry) {
+	template := &cluster.template
+	template.Count++
+	if entry.LogLevel > template.Level {
+		template.Level = entry.LogLevel
+	}
+	if !entry.Timestamp.IsZero() {
+		if template.FirstSeen.IsZero() || entry.Timestamp.Before(template.FirstSeen) {
+			template.FirstSeen = entry.Timestamp
+		}
+		if entry.Timestamp.After(template.LastSeen) {
+			template.LastSeen = entry.Timestamp
+		}
+	}
+	if len(template.Examples) < m.options.MaxExamples {
+		template.Examples = append(template.Examples, entry)
+	}
+}
+
+// Templates returns the most frequent templates, up to the configured limit
+func (m *TemplateMiner) Templates() []common.LogTemplate {
+	templates := make([]common.LogTemplate, 0, len(m.clusters))
+	for _, cluster := range m.clusters {
+		template := cluster.template
+		template.Template = strings.Join(cluster.tokens, " ")
+		template.ID = templateID(template.Template)
+		template.Examples = append([]*common.LogEntry(nil), template.Examples...)
+		templates = append(templates, template)
+	}
+
+	sort.SliceStable(templates, func(i, j int) bool {
+		return templates[i].Count > templates[j].Count
+	})
+	if len(templates) > m.options.Limit {
+		templates = templates[:m.options.Limit]
+	}
+	return templates
+}
+
+// templateID derives a stable ID from a template's text, so the same
+// template gets the same ID across runs
+func templateID(template string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(template))
+	return fmt.Sprintf("T%08x", hash.Sum32())
+}
+
+// templateTokens splits a message into tokens and masks the ones that are
+// likely variables: anything containing a digit, such as IDs, counts,
+// durations, addresses and timestamps. The value of a key=value token is
+// masked on its own so the key stays part of the template.
+func templateTokens(message string) []string {
+	tokens := strings.Fields(message)
+	for i, token := range tokens {
+		if key, value, found := strings.Cut(token, "="); found && key != "" {
+			if isVariableToken(value) {
+				tokens[i] = key + "=" + templateWildcard
+			}
+			continue
+		}
+		if isVariableToken(token) {
+			tokens[i] = templateWildcard
+		}
+	}
+	return tokens
+}
+
+// isVariableToken reports whether a token contains a digit
+func isVariableToken(token string) bool {
+	return strings.IndexFunc(token, unicode.IsDigit) >= 0
+}
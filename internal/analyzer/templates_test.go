@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func templateEntries(messages ...string) []*common.LogEntry {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entries := make([]*common.LogEntry, len(messages))
+	for i, message := range messages {
+		entries[i] = createTestEntry(base.Add(time.Duration(i)*time.Second), common.LevelInfo, "INFO", message)
+	}
+	return entries
+}
+
+func TestTemplateMinerClustersMessages(t *testing.T) {
+	entries := templateEntries(
+		"User 1001 logged in from 10.0.0.1",
+		"User 1002 logged in from 10.0.0.2",
+		"Cache miss for key session after 12ms",
+		"User 1003 logged in from 10.0.0.9",
+		"Cache miss for key profile after 15ms",
+		"request done status=200 path=/api",
+		"request done status=404 path=/api",
+	)
+	entries[4].LogLevel = common.LevelWarn
+
+	miner := NewTemplateMiner(DefaultTemplateOptions())
+	for _, entry := range entries {
+		miner.Add(entry)
+	}
+	templates := miner.Templates()
+
+	want := []struct {
+		template string
+		count    int
+		level    common.LogLevel
+	}{
+		{"User <*> logged in from <*>", 3, common.LevelInfo},
+		{"Cache miss for key <*> after <*>", 2, common.LevelWarn},
+		{"request done status=<*> path=/api", 2, common.LevelInfo},
+	}
+	if len(templates) != len(want) {
+		t.Fatalf("Templates() returned %d templates, want %d: %+v", len(templates), len(want), templates)
+	}
+	for i, w := range want {
+		got := templates[i]
+		if got.Template != w.template || got.Count != w.count || got.Level != w.level {
+			t.Errorf("template %d = %q (%d, %v), want %q (%d, %v)", i, got.Template, got.Count, got.Level, w.template, w.count, w.level)
+		}
+		if got.ID != templateID(w.template) {
+			t.Errorf("template %d ID = %s, want %s", i, got.ID, templateID(w.template))
+		}
+	}
+
+	first := templates[0]
+	if !first.FirstSeen.Equal(entries[0].Timestamp) || !first.LastSeen.Equal(entries[3].Timestamp) {
+		t.Errorf("first template seen %v - %v, want %v - %v", first.FirstSeen, first.LastSeen, entries[0].Timestamp, entries[3].Timestamp)
+	}
+	if len(first.Examples) != 3 || first.Examples[0] != entries[0] {
+		t.Errorf("first template has %d examples, want the first 3 entries", len(first.Examples))
+	}
+}
+
+func TestTemplateMinerLimits(t *testing.T) {
+	options := DefaultTemplateOptions()
+	options.Limit = 2
+	options.MaxClusters = 3
+	options.MaxExamples = 1
+
+	miner := NewTemplateMiner(options)
+	for _, entry := range templateEntries(
+		"alpha one", "beta two words", "gamma three more words", "delta four five six seven", "alpha one",
+	) {
+		miner.Add(entry)
+	}
+
+	templates := miner.Templates()
+	if len(templates) != 2 {
+		t.Fatalf("Templates() returned %d templates, want 2", len(templates))
+	}
+	if templates[0].Template != "alpha one" || templates[0].Count != 2 || len(templates[0].Examples) != 1 {
+		t.Errorf("top template = %+v, want alpha one seen twice with one example", templates[0])
+	}
+	if len(miner.clusters) != 3 {
+		t.Errorf("miner tracks %d clusters, want at most 3", len(miner.clusters))
+	}
+}
+
+func TestAnalyzeMinesUnmatchedEntries(t *testing.T) {
+	entries := templateEntries(
+		"connection refused by db-1",
+		"Worker 1 finished job 10",
+		"Worker 2 finished job 11",
+	)
+	pattern := &common.Pattern{ID: "conn", Name: "Connection", Type: common.PatternTypeError, Regex: "connection refused"}
+
+	engine := NewEngine()
+	if err := engine.SetPatterns([]*common.Pattern{pattern}); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range entries {
+		stream.Add(entry)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		if len(analysis.Templates) != 1 {
+			t.Fatalf("%s: got %d templates, want 1: %+v", name, len(analysis.Templates), analysis.Templates)
+		}
+		if got := analysis.Templates[0]; got.Template != "Worker <*> finished job <*>" || got.Count != 2 {
+			t.Errorf("%s: template = %q (%d), want the worker template seen twice", name, got.Template, got.Count)
+		}
+	}
+
+	engine.SetTemplateOptions(TemplateOptions{})
+	disabled, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	if len(disabled.Templates) != 0 {
+		t.Errorf("got %d templates with mining disabled, want none", len(disabled.Templates))
+	}
+}
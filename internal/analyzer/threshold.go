@@ -0,0 +1,199 @@
+package analyzer
+
+import (
+	"container/list"
+	"regexp"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/filter"
+)
+
+// maxThresholdGroups bounds the group_by values a tracker keeps matches
+// for, so that a high-cardinality key such as a request ID cannot grow it
+// without limit. The least recently matched group is dropped first.
+const maxThresholdGroups = 10000
+
+// thresholdTracker evaluates a threshold pattern over its matching entries.
+// Per group it keeps the last Count matches, and groups whose matches have
+// all left the window are dropped, so memory is bounded by the threshold
+// rather than the input.
+//
+// Matches are expected in time order, as produced by merged input. An
+// entry earlier than those already seen is counted as if it arrived in
+// order, and may miss a burst whose group was already dropped.
+type thresholdTracker struct {
+	threshold *common.Threshold
+	capture   *regexp.Regexp // pattern regex, when GroupBy names one of its captures
+	groups    map[string]*list.Element
+	recency   *list.List // groups, most recently matched first
+	latest    time.Time  // latest match timestamp seen
+	windows   []common.BurstWindow
+}
+
+// thresholdGroup is the recent matches of one group_by value
+type thresholdGroup struct {
+	key     string
+	recent  []*common.LogEntry
+	burst   int              // index of the open burst in windows, or -1
+	emitted *common.LogEntry // last entry returned as burst evidence
+}
+
+// newThresholdTracker creates a tracker for a pattern with a threshold
+func newThresholdTracker(pattern *common.Pattern, regex *regexp.Regexp) *thresholdTracker {
+	tracker := &thresholdTracker{
+		threshold: pattern.Threshold,
+		groups:    make(map[string]*list.Element),
+		recency:   list.New(),
+	}
+	if regex != nil && pattern.Threshold.GroupBy != "" && regex.SubexpIndex(pattern.Threshold.GroupBy) > 0 {
+		tracker.capture = regex
+	}
+	return tracker
+}
+
+// add records a matching entry and returns the entries it brings into a
+// burst: the entries of the window when the threshold is crossed, the
+// entry alone while the burst continues, and nothing otherwise. Entries
+// are returned once even when windows of two bursts overlap.
+func (t *thresholdTracker) add(entry *common.LogEntry) []*common.LogEntry {
+	if entry.Timestamp.After(t.latest) {
+		t.latest = entry.Timestamp
+	}
+	t.expireGroups()
+	group := t.group(t.groupKey(entry))
+
+	group.recent = append(group.recent, entry)
+	if len(group.recent) > t.threshold.Count {
+		group.recent = group.recent[1:]
+	}
+
+	oldest := group.recent[0]
+	crossed := len(group.recent) == t.threshold.Count &&
+		entry.Timestamp.Sub(oldest.Timestamp) <= t.threshold.Window
+	if !crossed {
+		group.burst = -1
+		return nil
+	}
+
+	if group.burst >= 0 {
+		window := &t.windows[group.burst]
+		window.End = entry.Timestamp
+		window.Count++
+		group.emitted = entry
+		return []*common.LogEntry{entry}
+	}
+
+	group.burst = len(t.windows)
+	t.windows = append(t.windows, common.BurstWindow{
+		Group: group.key,
+		Start: oldest.Timestamp,
+		End:   entry.Timestamp,
+		Count: len(group.recent),
+	})
+
+	evidence := group.recent
+	for i, recent := range group.recent {
+		if recent == group.emitted {
+			evidence = group.recent[i+1:]
+		}
+	}
+	group.emitted = entry
+	return append([]*common.LogEntry(nil), evidence...)
+}
+
+// group returns the group of key, creating it and dropping the least
+// recently matched group if there are too many
+func (t *thresholdTracker) group(key string) *thresholdGroup {
+	if element, ok := t.groups[key]; ok {
+		t.recency.MoveToFront(element)
+		return element.Value.(*thresholdGroup)
+	}
+
+	group := &thresholdGroup{key: key, burst: -1}
+	t.groups[key] = t.recency.PushFront(group)
+	if t.recency.Len() > maxThresholdGroups {
+		t.removeGroup(t.recency.Back())
+	}
+	return group
+}
+
+// expireGroups drops the groups whose last match is more than a window
+// before the latest one, since no later match can form a burst with them
+func (t *thresholdTracker) expireGroups() {
+	cutoff := t.latest.Add(-t.threshold.Window)
+	for element := t.recency.Back(); element != nil; element = t.recency.Back() {
+		group := element.Value.(*thresholdGroup)
+		if !group.recent[len(group.recent)-1].Timestamp.Before(cutoff) {
+			return
+		}
+		t.removeGroup(element)
+	}
+}
+
+func (t *thresholdTracker) removeGroup(element *list.Element) {
+	t.recency.Remove(element)
+	delete(t.groups, element.Value.(*thresholdGroup).key)
+}
+
+// groupKey returns the group_by value of an entry: the named capture of
+// the pattern regex, or else the entry field of that name
+func (t *thresholdTracker) groupKey(entry *common.LogEntry) string {
+	groupBy := t.threshold.GroupBy
+	if groupBy == "" {
+		return ""
+	}
+	if t.capture != nil {
+		index := t.capture.SubexpIndex(groupBy)
+		for _, text := range []string{entry.Message, entry.Raw} {
+			if match := t.capture.FindStringSubmatch(text); match != nil {
+				return match[index]
+			}
+		}
+	}
+	value, _ := filter.FieldValue(entry, groupBy)
+	return value
+}
+
+// burstWindows returns a copy of the bursts found so far
+func (t *thresholdTracker) burstWindows() []common.BurstWindow {
+	return append([]common.BurstWindow(nil), t.windows...)
+}
+
+// applyThresholds replaces the matches of threshold patterns with the
+// entries in their bursts, dropping patterns that never crossed their
+// threshold. Matches must be in time order.
+func (e *AnalyzerEngine) applyThresholds(matches []PatternMatch) []PatternMatch {
+	result := make([]PatternMatch, 0, len(matches))
+	for _, match := range matches {
+		if match.Pattern.Threshold == nil {
+			result = append(result, match)
+			continue
+		}
+
+		tracker := newThresholdTracker(match.Pattern, e.matcher.patternRegex(match.Pattern.ID))
+		var evidence []*common.LogEntry
+		for _, entry := range match.Matches {
+			evidence = append(evidence, tracker.add(entry)...)
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+
+		burst := PatternMatch{
+			Pattern:   match.Pattern,
+			Matches:   evidence,
+			Count:     len(evidence),
+			FirstSeen: evidence[0].Timestamp,
+			LastSeen:  evidence[len(evidence)-1].Timestamp,
+			Windows:   tracker.burstWindows(),
+		}
+		if regex := e.matcher.captureRegex(match.Pattern.ID); regex != nil {
+			for _, entry := range evidence {
+				countCaptures(&burst, captureValues(regex, entry))
+			}
+		}
+		result = append(result, burst)
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// burstEntries returns error entries at the given offsets in seconds
+func burstEntries(message string, offsets ...int) []*common.LogEntry {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entries := make([]*common.LogEntry, len(offsets))
+	for i, offset := range offsets {
+		entries[i] = createTestEntry(base.Add(time.Duration(offset)*time.Second), common.LevelError, "ERROR", message)
+	}
+	return entries
+}
+
+func TestThresholdPatterns(t *testing.T) {
+	entries := append(burstEntries("connection refused by db-1:5432", 0, 10, 20, 30, 200, 300),
+		burstEntries("connection refused by db-2:5432", 5, 15, 25)...)
+	entries = append(entries, burstEntries("auth failed", 0, 50)...)
+
+	patterns := []*common.Pattern{
+		{
+			ID: "refused", Name: "Refused", Type: common.PatternTypeError,
+			Regex:     `connection refused by (?P<host>[\w-]+)`,
+			Threshold: &common.Threshold{Count: 3, Window: time.Minute, GroupBy: "host"},
+		},
+		{
+			ID: "auth", Name: "Auth", Type: common.PatternTypeSecurity,
+			Keywords:  []string{"auth failed"},
+			Threshold: &common.Threshold{Count: 3, Window: time.Minute},
+		},
+	}
+	engine := NewEngine()
+	if err := engine.SetPatterns(patterns); err != nil {
+		t.Fatalf("SetPatterns() failed: %v", err)
+	}
+
+	batch, err := engine.Analyze(context.Background(), entries)
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+	sorted := append([]*common.LogEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+	stream := engine.NewStream(DefaultStreamOptions())
+	for _, entry := range sorted {
+		stream.Add(entry)
+	}
+
+	for name, analysis := range map[string]*Analysis{"batch": batch, "stream": stream.Analysis()} {
+		if len(analysis.Patterns) != 1 {
+			t.Fatalf("%s: got %d patterns, want only refused: %+v", name, len(analysis.Patterns), analysis.Patterns)
+		}
+		match := analysis.Patterns[0]
+		if match.Count != 7 {
+			t.Errorf("%s: count = %d, want the 7 entries in bursts", name, match.Count)
+		}
+		if len(match.Windows) != 2 {
+			t.Fatalf("%s: got %d windows, want 2: %+v", name, len(match.Windows), match.Windows)
+		}
+		db1, db2 := match.Windows[0], match.Windows[1]
+		if db1.Group != "db-1" || db1.Count != 4 || db1.End.Sub(db1.Start) != 30*time.Second {
+			t.Errorf("%s: first window = %+v, want 4 db-1 entries over 30s", name, db1)
+		}
+		if db2.Group != "db-2" || db2.Count != 3 {
+			t.Errorf("%s: second window = %+v, want 3 db-2 entries", name, db2)
+		}
+	}
+}
+
+func TestThresholdTrackerOverlappingBursts(t *testing.T) {
+	pattern := &common.Pattern{ID: "p", Threshold: &common.Threshold{Count: 3, Window: 10 * time.Second}}
+	tracker := newThresholdTracker(pattern, nil)
+
+	var evidence []*common.LogEntry
+	entries := burstEntries("x", 0, 1, 2, 12, 12)
+	for _, entry := range entries {
+		evidence = append(evidence, tracker.add(entry)...)
+	}
+
+	if len(tracker.windows) != 2 {
+		t.Fatalf("got %d windows, want 2: %+v", len(tracker.windows), tracker.windows)
+	}
+	if len(evidence) != len(entries) {
+		t.Errorf("got %d evidence entries, want each of the %d entries once", len(evidence), len(entries))
+	}
+}
+
+func TestThresholdTrackerDropsIdleGroups(t *testing.T) {
+	pattern := &common.Pattern{ID: "p", Threshold: &common.Threshold{Count: 3, Window: 10 * time.Second, GroupBy: "service"}}
+	tracker := newThresholdTracker(pattern, nil)
+
+	// A distinct group every second: only those matched within the window stay
+	offsets := make([]int, 100)
+	for i := range offsets {
+		offsets[i] = i
+	}
+	for i, entry := range burstEntries("x", offsets...) {
+		entry.Service = fmt.Sprintf("request-%d", i)
+		tracker.add(entry)
+	}
+	if len(tracker.groups) != 11 || tracker.recency.Len() != 11 {
+		t.Errorf("got %d groups, want the 11 matched within the window", len(tracker.groups))
+	}
+
+	// Groups matched at the same time are capped
+	at := burstEntries("x", 200)[0].Timestamp
+	for i := 0; i < maxThresholdGroups+5; i++ {
+		entry := createTestEntry(at, common.LevelError, "ERROR", "x")
+		entry.Service = fmt.Sprintf("burst-%d", i)
+		tracker.add(entry)
+	}
+	if len(tracker.groups) != maxThresholdGroups {
+		t.Errorf("got %d groups, want at most %d", len(tracker.groups), maxThresholdGroups)
+	}
+	if _, ok := tracker.groups["burst-0"]; ok {
+		t.Error("least recently matched group was kept, want it dropped")
+	}
+}
+
+func TestInvalidThreshold(t *testing.T) {
+	pattern := &common.Pattern{ID: "p", Keywords: []string{"x"}, Threshold: &common.Threshold{Count: 5}}
+	if err := NewEngine().SetPatterns([]*common.Pattern{pattern}); err == nil {
+		t.Error("SetPatterns() with a zero window succeeded, want error")
+	}
+}
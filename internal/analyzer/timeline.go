@@ -17,7 +17,14 @@ func NewTimelineGenerator() *TimelineGenerator {
 
 // GenerateTimeline creates a timeline analysis with specified bucket size
 func (g *TimelineGenerator) GenerateTimeline(entries []*common.LogEntry, bucketSize time.Duration) *Timeline {
-	if len(entries) == 0 || bucketSize <= 0 {
+	// Entries without a timestamp have no place on the timeline
+	sortedEntries := make([]*common.LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Timestamp.IsZero() {
+			sortedEntries = append(sortedEntries, entry)
+		}
+	}
+	if len(sortedEntries) == 0 || bucketSize <= 0 {
 		return &Timeline{
 			Buckets:    []TimeBucket{},
 			BucketSize: bucketSize,
@@ -25,8 +32,6 @@ func (g *TimelineGenerator) GenerateTimeline(entries []*common.LogEntry, bucketS
 	}
 
 	// Sort entries by timestamp to ensure proper timeline ordering
-	sortedEntries := make([]*common.LogEntry, len(entries))
-	copy(sortedEntries, entries)
 	sort.Slice(sortedEntries, func(i, j int) bool {
 		return sortedEntries[i].Timestamp.Before(sortedEntries[j].Timestamp)
 	})
@@ -281,3 +286,105 @@ type TimelineTrend struct {
 	StartTime time.Time `json:"start_time"`
 	EndTime   time.Time `json:"end_time"`
 }
+
+// cappedBucketSize widens bucketSize to a multiple of itself so the range
+// from first to last spans at most maxBuckets buckets, but no fewer than
+// two. A maxBuckets of 0 leaves it unchanged.
+func cappedBucketSize(bucketSize time.Duration, first, last time.Time, maxBuckets int) time.Duration {
+	if bucketSize <= 0 || maxBuckets <= 0 {
+		return bucketSize
+	}
+	maxBuckets = max(maxBuckets, 2)
+
+	// Truncating the start to a wider bucket can add one, so widen until
+	// the buckets created fit
+	for factor := int(last.Sub(first)/bucketSize)/maxBuckets + 1; ; factor++ {
+		size := bucketSize * time.Duration(factor)
+		if int(last.Truncate(size).Sub(first.Truncate(size))/size)+1 <= maxBuckets {
+			return size
+		}
+	}
+}
+
+// timelineAccumulator builds timeline buckets incrementally, one entry at a time
+type timelineAccumulator struct {
+	generator  *TimelineGenerator
+	bucketSize time.Duration
+	maxBuckets int
+	counts     map[time.Time]*TimeBucket
+	first      time.Time
+	last       time.Time
+}
+
+// newTimelineAccumulator creates an accumulator for the given bucket size,
+// widening buckets on output to keep at most maxBuckets
+func newTimelineAccumulator(generator *TimelineGenerator, bucketSize time.Duration, maxBuckets int) *timelineAccumulator {
+	return &timelineAccumulator{
+		generator:  generator,
+		bucketSize: bucketSize,
+		maxBuckets: maxBuckets,
+		counts:     make(map[time.Time]*TimeBucket),
+	}
+}
+
+// add records a single entry in its bucket. Entries without a timestamp
+// are not counted.
+func (a *timelineAccumulator) add(entry *common.LogEntry) {
+	if a.bucketSize <= 0 || entry.Timestamp.IsZero() {
+		return
+	}
+
+	if a.first.IsZero() || entry.Timestamp.Before(a.first) {
+		a.first = entry.Timestamp
+	}
+	if a.last.IsZero() || entry.Timestamp.After(a.last) {
+		a.last = entry.Timestamp
+	}
+
+	start := entry.Timestamp.Truncate(a.bucketSize)
+	bucket, exists := a.counts[start]
+	if !exists {
+		bucket = &TimeBucket{Start: start, End: start.Add(a.bucketSize)}
+		a.counts[start] = bucket
+	}
+
+	bucket.EntryCount++
+	switch entry.LogLevel {
+	case common.LevelError, common.LevelFatal:
+		bucket.ErrorCount++
+	case common.LevelWarn:
+		bucket.WarnCount++
+	}
+}
+
+// timeline returns a contiguous timeline covering all recorded entries
+func (a *timelineAccumulator) timeline() *Timeline {
+	if len(a.counts) == 0 {
+		return &Timeline{
+			Buckets:    []TimeBucket{},
+			BucketSize: a.bucketSize,
+		}
+	}
+
+	size := cappedBucketSize(a.bucketSize, a.first, a.last, a.maxBuckets)
+	startTime := a.first.Truncate(size)
+	endTime := a.last.Truncate(size).Add(size)
+	buckets := a.generator.createBuckets(startTime, endTime, size)
+
+	// Buckets of size are whole multiples of the counted ones
+	for start, counted := range a.counts {
+		index := int(start.Truncate(size).Sub(startTime) / size)
+		if index < 0 || index >= len(buckets) {
+			continue
+		}
+		bucket := &buckets[index]
+		bucket.EntryCount += counted.EntryCount
+		bucket.ErrorCount += counted.ErrorCount
+		bucket.WarnCount += counted.WarnCount
+	}
+
+	return &Timeline{
+		Buckets:    buckets,
+		BucketSize: size,
+	}
+}
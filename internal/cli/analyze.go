@@ -1,9 +1,9 @@
 package cli
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,18 +14,24 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/yildizm/LogSum/internal/analyzer"
 	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/config"
 	"github.com/yildizm/LogSum/internal/correlation"
 	"github.com/yildizm/LogSum/internal/docstore"
 	"github.com/yildizm/LogSum/internal/formatter"
+	"github.com/yildizm/LogSum/internal/ingest"
 	"github.com/yildizm/LogSum/internal/monitor"
 	"github.com/yildizm/LogSum/internal/ui"
-	"github.com/yildizm/go-logparser"
 )
 
 var (
 	analyzeFormat      string
+	analyzeFormatFile  string
+	analyzeSince       string
+	analyzeUntil       string
+	analyzeWhere       string
 	analyzePatterns    string
 	analyzeFollow      bool
+	analyzeRefresh     time.Duration
 	analyzeTimeout     time.Duration
 	analyzeMaxLines    int
 	analyzeNoTUI       bool
@@ -35,35 +41,73 @@ var (
 	analyzeAI          bool
 	analyzeMonitor     bool
 	analyzeMonitorFile string
+
+	analyzeNoInsights         bool
+	analyzeAnomalyMethod      string
+	analyzeAnomalySensitivity float64
 )
 
 func newAnalyzeCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "analyze [file]",
+		Use:   "analyze [file|glob]...",
 		Short: "Analyze log files or stdin",
 		Long: `Analyze log files for patterns, anomalies, and insights.
 
 If no file is specified, reads from stdin. Supports auto-detection of log formats
-or manual format specification.
+or manual format specification. Besides JSON, logfmt and plain text, nginx and
+Apache access logs, RFC 3164/5424 syslog, journalctl -o json exports, Docker
+json-file logs and Kubernetes CRI container logs are recognized.
+
+Several files or globs can be analyzed together. Their entries are merged into
+a single timestamp-ordered stream with a shared timeline, and the report breaks
+errors and pattern matches down per file.
+
+Gzip, bzip2 and zstd compressed input is detected and decompressed on the fly.
+Tar archives such as support bundles are unpacked to temporary files and their
+members merged like several input files, with each file in the archive reported
+as its own source. --max-lines caps the lines read from all inputs combined,
+archive members included.
+
+Custom formats declared under formats: in the config file or in a --format-file
+are selected by name with --format. Each is a regex with named groups or a grok
+expression; captures named timestamp, level, message, service or trace_id fill
+those entry fields and all other captures are kept as metadata.
+
+Entries can be narrowed down before analysis. --since and --until take an
+absolute time (2024-01-15 14:30, 14:30) or a relative one (2h, 3d ago, today),
+all on the local clock. Log timestamps without a zone are read as UTC.
+--where takes an expression over level, message, service, trace_id, source,
+timestamp and metadata fields, using = != > >= < <= ~ (regex) and !~, combined
+with AND, OR, NOT and parentheses.
 
 Examples:
   logsum analyze app.log
   logsum analyze --format json access.log
+  logsum analyze --format-file formats.yaml --format legacy app.log
+  logsum analyze api.log worker.log gateway.log
+  logsum analyze '/var/log/myapp/*.log'
+  logsum analyze app.log.1.gz app.log
+  logsum analyze --since 2h app.log
+  logsum analyze --since "2024-01-15 14:00" --until "2024-01-15 15:00" app.log
+  logsum analyze --where 'service=payments AND level>=WARN' app.log
+  logsum analyze support-bundle.tar.gz
   logsum analyze --ai app.log
   logsum analyze --ai --docs ./docs/ app.log
   logsum analyze --monitor app.log
+  logsum analyze --baseline yesterday.log app.log
+  logsum analyze --follow --no-tui --refresh 10s app.log
   logsum analyze --ai --monitor --monitor-file metrics.json app.log
   cat app.log | logsum analyze
   logsum analyze --patterns ./patterns/ app.log`,
-		Args: cobra.MaximumNArgs(1),
+		Args: cobra.ArbitraryArgs,
 		RunE: runAnalyze,
 	}
 
-	cmd.Flags().StringVarP(&analyzeFormat, "format", "f", "auto", "log format (auto, json, logfmt, text)")
+	addInputFlags(cmd)
 	cmd.Flags().StringVarP(&analyzePatterns, "patterns", "p", "", "pattern file or directory")
-	cmd.Flags().BoolVar(&analyzeFollow, "follow", false, "follow file for new entries")
-	cmd.Flags().DurationVar(&analyzeTimeout, "timeout", 30*time.Second, "analysis timeout")
-	cmd.Flags().IntVar(&analyzeMaxLines, "max-lines", 100000, "maximum lines to analyze")
+	cmd.Flags().BoolVar(&analyzeFollow, "follow", false, "follow file for new entries (--max-lines does not apply)")
+	cmd.Flags().DurationVar(&analyzeRefresh, "refresh", 5*time.Second, "interval between result updates with --follow")
+	cmd.Flags().DurationVar(&analyzeTimeout, "timeout", 30*time.Second, "timeout for analysis and correlation, not counting the time spent reading input")
 	cmd.Flags().BoolVar(&analyzeNoTUI, "no-tui", false, "disable terminal UI, output to stdout")
 	cmd.Flags().StringVar(&analyzeOutputFile, "output-file", "", "save output to file instead of stdout")
 	cmd.Flags().StringVar(&analyzeDocsPath, "docs", "", "path to documentation directory for correlation")
@@ -71,11 +115,33 @@ Examples:
 	cmd.Flags().BoolVar(&analyzeAI, "ai", false, "enable AI-powered analysis with LLM integration")
 	cmd.Flags().BoolVar(&analyzeMonitor, "monitor", false, "enable real-time performance monitoring during analysis")
 	cmd.Flags().StringVar(&analyzeMonitorFile, "monitor-file", "", "save monitoring metrics to file (optional)")
+	cmd.Flags().BoolVar(&analyzeNoInsights, "no-insights", false, "skip insight generation")
+	cmd.Flags().StringVar(&analyzeAnomalyMethod, "anomaly-method", "", "statistical anomaly baseline (zscore, ewma or mad)")
+	cmd.Flags().Float64Var(&analyzeAnomalySensitivity, "anomaly-sensitivity", 0, "deviations above the baseline that count as anomalous (default from config, 3)")
+	cmd.Flags().StringVar(&analyzeBaseline, "baseline", "", "compare with a known-good log or a saved JSON analysis")
+	cmd.Flags().BoolVar(&analyzeSaveProfile, "save-profile", false, "include a profile in JSON output, so it can serve as a baseline")
 
 	return cmd
 }
 
+// addInputFlags adds the flags that control how input is read, parsed and
+// filtered, shared by every command that reads logs like analyze does
+func addInputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&analyzeFormat, "format", "f", "auto", "log format (auto, json, logfmt, text, nginx, apache, syslog, journald, docker, cri, or a custom format name)")
+	cmd.Flags().StringVar(&analyzeFormatFile, "format-file", "", "YAML file with custom format definitions")
+	cmd.Flags().StringVar(&analyzeSince, "since", "", "only read entries at or after this time on the local clock (e.g. 2h, \"2024-01-15 14:30\")")
+	cmd.Flags().StringVar(&analyzeUntil, "until", "", "only read entries at or before this time on the local clock")
+	cmd.Flags().StringVar(&analyzeWhere, "where", "", "only read entries matching this expression (e.g. 'service=api AND level>=WARN')")
+	cmd.Flags().IntVar(&analyzeMaxLines, "max-lines", 0, "maximum lines to read across all inputs (0 = unlimited)")
+}
+
 func runAnalyze(cmd *cobra.Command, args []string) error {
+	return analyzeInputs(cmd, args, analyzeBaseline)
+}
+
+// analyzeInputs analyzes the inputs named by args, comparing them with the
+// baseline input at baselinePath unless it is empty
+func analyzeInputs(cmd *cobra.Command, args []string, baselinePath string) error {
 	// Get configuration
 	cfg := GetGlobalConfig()
 
@@ -83,55 +149,145 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	if !cmd.Flag("timeout").Changed {
 		analyzeTimeout = cfg.Analysis.Timeout
 	}
-	if !cmd.Flag("max-lines").Changed {
-		analyzeMaxLines = cfg.Analysis.MaxEntries
+	// Load patterns using pattern loader
+	patternLoader := NewPatternLoader()
+	patterns := patternLoader.LoadAnalysisPatterns()
+
+	// Reject bad filters and insight settings before any input is read
+	entryFilter, err := newEntryFilter()
+	if err != nil {
+		return err
+	}
+	if _, err := insightOptions(cfg); err != nil {
+		return fmt.Errorf("invalid insight settings: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), analyzeTimeout)
-	defer cancel()
+	// Follow mode runs until interrupted, so it is not bound by --timeout
+	if analyzeFollow {
+		if baselinePath != "" {
+			return fmt.Errorf("--baseline cannot be combined with --follow")
+		}
+		return runFollowAnalysis(args, patterns, entryFilter)
+	}
+
+	// Reading input is not bound by --timeout, which only limits the
+	// analysis steps that follow it, so large logs are read in full
+	ctx := context.Background()
+
+	// Profile the baseline first, so a bad baseline fails fast
+	var base *baseline
+	if baselinePath != "" {
+		base, err = loadBaseline(ctx, baselinePath, patterns)
+		if err != nil {
+			return err
+		}
+	}
 
-	// Get input reader
-	reader, _, cleanup, err := setupInputReader(args)
+	// Get input entry stream
+	source, cleanup, err := setupEntrySource(args)
 	if err != nil {
 		return err
 	}
-	if cleanup != nil {
-		defer cleanup()
+	defer cleanup()
+	source = filterSource(source, entryFilter)
+
+	// The TUI and AI analysis need every entry in memory; everything else
+	// is analyzed as a stream
+	if !requiresAllEntries(base) {
+		return runStreamingCLIAnalysis(ctx, source, patterns, base)
 	}
 
 	// Read and parse logs
-	entries, err := readAndParseInput(reader)
+	entries, err := readAllEntries(source, inMemoryEntryLimit())
 	if err != nil {
 		return err
 	}
 
-	// Load patterns using pattern loader
-	patternLoader := NewPatternLoader()
-	patterns := patternLoader.LoadAnalysisPatterns()
-
 	// Run analysis
-	return runAnalysisAndOutput(ctx, entries, patterns)
+	return runAnalysisAndOutput(ctx, entries, patterns, base)
 }
 
-func readLines(reader io.Reader, maxLines int) ([]string, error) {
-	var lines []string
-	scanner := bufio.NewScanner(reader)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024) // 1MB buffer
+// requiresAllEntries reports whether the selected mode needs the full entry list
+func requiresAllEntries(base *baseline) bool {
+	return (base == nil && shouldUseTUIMode()) || analyzeAI
+}
 
-	lineCount := 0
-	for scanner.Scan() && lineCount < maxLines {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			lines = append(lines, line)
-			lineCount++
-		}
+// newEntryReader creates a streaming entry reader using the analyze flags.
+// Entries are tagged with source, the name of the input they came from.
+func newEntryReader(reader io.Reader, source string) (*ingest.Reader, error) {
+	options, err := entryReaderOptions(source)
+	if err != nil {
+		return nil, err
+	}
+	return ingest.NewReader(reader, options)
+}
+
+// newEntrySource is like newEntryReader, but also decompresses gzip, bzip2
+// and zstd input and merges the members of tar archives by timestamp. Lines
+// are drawn from budget, which is shared by every input of the run.
+func newEntrySource(reader io.Reader, source string, budget *ingest.LineBudget) (ingest.Source, func(), error) {
+	options, err := entryReaderOptions(source)
+	if err != nil {
+		return nil, nil, err
 	}
+	options.Budget = budget
+	return ingest.Open(reader, options)
+}
 
-	if err := scanner.Err(); err != nil {
-		return lines, fmt.Errorf("scanner error: %w", err)
+// entryReaderOptions returns the reader options set by the analyze flags
+func entryReaderOptions(source string) (ingest.Options, error) {
+	cfg := GetGlobalConfig()
+	formats, err := lineFormats(analyzeFormatFile)
+	if err != nil {
+		return ingest.Options{}, err
+	}
+	return ingest.Options{
+		Format:        analyzeFormat,
+		MaxLines:      analyzeMaxLines,
+		MaxLineLength: cfg.Analysis.MaxLineLength,
+		Source:        source,
+		Multiline:     multilineOptions(),
+		Formats:       formats,
+	}, nil
+}
+
+// multilineOptions returns how continuation lines are joined, as configured
+func multilineOptions() ingest.MultilineOptions {
+	cfg := GetGlobalConfig()
+	return ingest.MultilineOptions{
+		Disabled:      cfg.Analysis.Multiline.Disabled,
+		StartPatterns: cfg.Analysis.Multiline.StartPatterns,
 	}
+}
 
-	return lines, nil
+// templateOptions converts the template mining config to analyzer options.
+// Zero values keep the analyzer defaults.
+func templateOptions(cfg config.TemplatesConfig) analyzer.TemplateOptions {
+	options := analyzer.DefaultTemplateOptions()
+	if cfg.Disabled {
+		options.Limit = 0
+		return options
+	}
+	setInt(&options.Limit, cfg.Limit)
+	setInt(&options.Depth, cfg.Depth)
+	setFloat(&options.Similarity, cfg.Similarity)
+	setInt(&options.MaxClusters, cfg.MaxClusters)
+	setInt(&options.MaxExamples, cfg.MaxExamples)
+	return options
+}
+
+// reportReaderStats reports lines that were skipped or left unread
+func reportReaderStats(source ingest.Source) {
+	if source.Truncated() {
+		fmt.Fprintf(os.Stderr, "Warning: input truncated after %d lines (--max-lines)\n", source.LinesRead())
+	}
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Read %d lines as %s\n", source.LinesRead(), source.FormatName())
+		if source.Skipped() > 0 {
+			fmt.Fprintf(os.Stderr, "Skipped %d unparseable lines\n", source.Skipped())
+		}
+	}
+	reportFiltered(source)
 }
 
 // setupInputReader sets up the input reader based on command args
@@ -174,91 +330,145 @@ func setupInputReader(args []string) (reader io.Reader, source string, cleanup f
 	return file, cleanPath, cleanup, nil
 }
 
-// readAndParseInput reads and parses log entries from the input reader
-func readAndParseInput(reader io.Reader) ([]*common.LogEntry, error) {
-	// Read all lines
-	lines, err := readLines(reader, analyzeMaxLines)
+// setupEntrySource opens every input named by args and returns a single
+// entry stream. Globs are expanded, compressed files and tar archives are
+// unpacked on the fly, and several files are merged by timestamp. --max-lines
+// limits all inputs together.
+func setupEntrySource(args []string) (ingest.Source, func(), error) {
+	paths, err := expandInputArgs(args)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read input: %w", err)
+		return nil, nil, err
 	}
 
-	if len(lines) == 0 {
-		return nil, fmt.Errorf("no log entries found")
+	// No arguments means a single stdin input
+	inputs := [][]string{nil}
+	if len(paths) > 0 {
+		inputs = make([][]string, len(paths))
+		for i, path := range paths {
+			inputs[i] = []string{path}
+		}
 	}
 
-	if isVerbose() {
-		fmt.Fprintf(os.Stderr, "Read %d lines\n", len(lines))
+	var cleanups []func()
+	cleanupAll := func() {
+		for _, cleanup := range cleanups {
+			cleanup()
+		}
 	}
 
-	// Parse logs
-	var entries []*common.LogEntry
-	if analyzeFormat == "auto" {
-		if isVerbose() {
-			fmt.Fprintf(os.Stderr, "Auto-detecting format...\n")
+	budget := ingest.NewLineBudget(analyzeMaxLines)
+	sources := make([]ingest.Source, 0, len(inputs))
+	for _, input := range inputs {
+		reader, name, cleanup, err := setupInputReader(input)
+		if err != nil {
+			cleanupAll()
+			return nil, nil, err
 		}
-		p := logparser.New()
-		logEntries, err := p.ParseString(strings.Join(lines, "\n"))
-		if err == nil {
-			entries = make([]*common.LogEntry, len(logEntries))
-			for i, entry := range logEntries {
-				entries[i] = common.ConvertToCommonLogEntry(&entry, i+1)
-			}
+		if cleanup != nil {
+			cleanups = append(cleanups, cleanup)
 		}
-	} else {
-		entries, err = parseWithSpecificFormat(lines)
-	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse logs: %w", err)
+		source, release, err := newEntrySource(reader, name, budget)
+		if err != nil {
+			cleanupAll()
+			return nil, nil, err
+		}
+		// Release decoders before closing the files they read from
+		cleanups = append([]func(){release}, cleanups...)
+		sources = append(sources, source)
 	}
 
-	if len(entries) == 0 {
-		return nil, fmt.Errorf("no valid log entries found")
+	if len(sources) == 1 {
+		return sources[0], cleanupAll, nil
 	}
+	return ingest.NewMergeReader(sources...), cleanupAll, nil
+}
 
-	if isVerbose() {
-		fmt.Fprintf(os.Stderr, "Parsed %d log entries\n", len(entries))
+// expandInputArgs expands glob arguments into the files they match,
+// keeping plain paths as given and dropping duplicates
+func expandInputArgs(args []string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+
+	for _, arg := range args {
+		matches := []string{arg}
+		if isGlobPattern(arg) {
+			var err error
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob %s: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %s", arg)
+			}
+		}
+
+		for _, path := range matches {
+			cleanPath := filepath.Clean(path)
+			if !seen[cleanPath] {
+				seen[cleanPath] = true
+				paths = append(paths, cleanPath)
+			}
+		}
 	}
 
-	return entries, nil
+	return paths, nil
 }
 
-// parseWithSpecificFormat parses lines with a specific format
-func parseWithSpecificFormat(lines []string) ([]*common.LogEntry, error) {
-	var format logparser.Format
-	switch analyzeFormat {
-	case "json":
-		format = logparser.FormatJSON
-	case "logfmt":
-		format = logparser.FormatLogfmt
-	case "text":
-		format = logparser.FormatText
-	default:
-		return nil, fmt.Errorf("unknown format %s. Available formats: json, logfmt, text", analyzeFormat)
+// readAllEntries reads and parses the log entries from source, keeping at
+// most limit of them unless limit is 0
+func readAllEntries(source ingest.Source, limit int) ([]*common.LogEntry, error) {
+	var entries []*common.LogEntry
+	for {
+		entry, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+		if limit > 0 && len(entries) == limit {
+			fmt.Fprintf(os.Stderr, "Warning: analyzing the first %d entries (analysis.max_entries); use --no-tui, or --max-lines to choose the limit\n", limit)
+			break
+		}
+		entries = append(entries, entry)
 	}
 
-	p := logparser.NewWithFormat(format)
-	logEntries, err := p.ParseString(strings.Join(lines, "\n"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse logs: %w", err)
+	if source.LinesRead() == 0 {
+		return nil, fmt.Errorf("no log entries found")
 	}
 
-	entries := make([]*common.LogEntry, len(logEntries))
-	for i, entry := range logEntries {
-		entries[i] = common.ConvertToCommonLogEntry(&entry, i+1)
+	reportReaderStats(source)
+
+	if len(entries) == 0 {
+		return nil, noEntriesError(source)
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Parsed %d log entries\n", len(entries))
 	}
 
 	return entries, nil
 }
 
+// inMemoryEntryLimit returns how many entries the TUI and AI analysis may
+// hold in memory: analysis.max_entries, unless --max-lines limits the input
+func inMemoryEntryLimit() int {
+	if analyzeMaxLines > 0 {
+		return 0
+	}
+	return GetGlobalConfig().Analysis.MaxEntries
+}
+
 // runAnalysisAndOutput performs analysis and outputs results.
 // This is the main orchestrator that determines whether to use TUI or CLI mode
-// and coordinates the analysis pipeline.
-func runAnalysisAndOutput(ctx context.Context, entries []*common.LogEntry, patterns []*common.Pattern) error {
-	if shouldUseTUIMode() {
+// and coordinates the analysis pipeline. Comparisons with a baseline are
+// only reported by the CLI.
+func runAnalysisAndOutput(ctx context.Context, entries []*common.LogEntry, patterns []*common.Pattern, base *baseline) error {
+	if base == nil && shouldUseTUIMode() {
 		return runTUIAnalysis(entries, patterns)
 	}
-	return runCLIAnalysis(ctx, entries, patterns)
+	return runCLIAnalysis(ctx, entries, patterns, base)
 }
 
 // shouldUseTUIMode determines if the terminal UI should be used based on flags and output settings.
@@ -275,7 +485,30 @@ func runTUIAnalysis(entries []*common.LogEntry, patterns []*common.Pattern) erro
 }
 
 // runCLIAnalysis performs command-line analysis with optional correlation and outputs results.
-func runCLIAnalysis(ctx context.Context, entries []*common.LogEntry, patterns []*common.Pattern) error {
+// Entries are already in memory, so their analysis is bound by --timeout.
+func runCLIAnalysis(ctx context.Context, entries []*common.LogEntry, patterns []*common.Pattern, base *baseline) error {
+	return runCLIPipeline(ctx, patterns, base, func(ctx context.Context, engine *analyzer.AnalyzerEngine, collector monitor.Collector) (*analyzer.Analysis, error) {
+		ctx, cancel := context.WithTimeout(ctx, analyzeTimeout)
+		defer cancel()
+		return performAnalysisWithMonitoring(ctx, engine, entries, collector)
+	})
+}
+
+// runStreamingCLIAnalysis analyzes entries as they are read from source and outputs results.
+// Reading and analyzing go together, so --timeout only starts once the
+// input is exhausted.
+func runStreamingCLIAnalysis(ctx context.Context, source ingest.Source, patterns []*common.Pattern, base *baseline) error {
+	return runCLIPipeline(ctx, patterns, base, func(ctx context.Context, engine *analyzer.AnalyzerEngine, collector monitor.Collector) (*analyzer.Analysis, error) {
+		return performStreamAnalysisWithMonitoring(ctx, engine, source, collector)
+	})
+}
+
+// analysisFunc runs the main analysis step of the CLI pipeline with engine
+type analysisFunc func(ctx context.Context, engine *analyzer.AnalyzerEngine, collector monitor.Collector) (*analyzer.Analysis, error)
+
+// runCLIPipeline sets up monitoring, runs the analysis step, optional
+// correlation, the comparison with base if any, and output.
+func runCLIPipeline(ctx context.Context, patterns []*common.Pattern, base *baseline, analyze analysisFunc) error {
 	// Setup monitoring if enabled
 	var metricsCollector monitor.Collector
 	if analyzeMonitor {
@@ -294,15 +527,22 @@ func runCLIAnalysis(ctx context.Context, entries []*common.LogEntry, patterns []
 		}
 	}
 
-	// Perform main analysis
-	analysis, err := performAnalysisWithMonitoring(ctx, entries, patterns, metricsCollector)
+	// Perform main analysis, profiled for the comparison with the baseline
+	engine := newAnalysisEngine(patterns)
+	if base != nil {
+		engine.SetProfiling(true)
+	}
+	analysis, err := analyze(ctx, engine, metricsCollector)
 	if err != nil {
 		return err
 	}
+	attachBaseline(analysis, base)
 
 	// Perform correlation if enabled OR if AI is enabled (AI always shows correlation summary)
 	var correlationResult *correlation.CorrelationResult
 	if analyzeCorrelate || analyzeAI {
+		ctx, cancel := context.WithTimeout(ctx, analyzeTimeout)
+		defer cancel()
 		var err error
 		correlationResult, err = performCorrelationWithMonitoring(ctx, analysis, metricsCollector)
 		if err != nil {
@@ -329,8 +569,10 @@ func logCorrelationWarning(err error) {
 	}
 }
 
-// performAnalysis runs the analysis engine with patterns
-func performAnalysis(ctx context.Context, entries []*common.LogEntry, patterns []*common.Pattern) (*analyzer.Analysis, error) {
+// newAnalysisEngine creates an analyzer engine loaded with patterns and
+// set up from the analysis and insights config
+func newAnalysisEngine(patterns []*common.Pattern) *analyzer.AnalyzerEngine {
+	cfg := GetGlobalConfig()
 	engine := analyzer.NewEngine()
 	if len(patterns) > 0 {
 		if err := engine.SetPatterns(patterns); err != nil {
@@ -340,6 +582,25 @@ func performAnalysis(ctx context.Context, entries []*common.LogEntry, patterns [
 		}
 	}
 
+	if !cfg.Analysis.EnableInsights || analyzeNoInsights {
+		engine.DisableInsights()
+	}
+	engine.SetTimelineMaxBuckets(cfg.Analysis.TimelineBuckets)
+	engine.SetTemplateOptions(templateOptions(cfg.Analysis.Templates))
+	// Saved JSON analyses with a profile can serve as baselines
+	engine.SetProfiling(analyzeSaveProfile)
+	options, err := insightOptions(cfg)
+	if err == nil {
+		err = engine.SetInsightOptions(options)
+	}
+	if err != nil && isVerbose() {
+		fmt.Fprintf(os.Stderr, "Warning: using default insight settings: %v\n", err)
+	}
+	return engine
+}
+
+// performAnalysis runs the analysis engine over entries
+func performAnalysis(ctx context.Context, engine *analyzer.AnalyzerEngine, entries []*common.LogEntry) (*analyzer.Analysis, error) {
 	if isVerbose() {
 		if analyzeAI {
 			fmt.Fprintf(os.Stderr, "Performing AI-enhanced analysis...\n")
@@ -362,6 +623,30 @@ func performAnalysis(ctx context.Context, entries []*common.LogEntry, patterns [
 	return analysis, nil
 }
 
+// performStreamAnalysis runs the analysis engine over a stream of entries
+func performStreamAnalysis(ctx context.Context, engine *analyzer.AnalyzerEngine, source ingest.Source) (*analyzer.Analysis, error) {
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Performing streaming analysis...\n")
+	}
+
+	analysis, err := engine.AnalyzeStream(ctx, source, analyzer.DefaultStreamOptions())
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	if source.LinesRead() == 0 {
+		return nil, fmt.Errorf("no log entries found")
+	}
+
+	reportReaderStats(source)
+
+	if analysis.TotalEntries == 0 {
+		return nil, noEntriesError(source)
+	}
+
+	return analysis, nil
+}
+
 // performCorrelation runs document correlation on analysis results
 func performCorrelation(ctx context.Context, analysis *analyzer.Analysis) (*correlation.CorrelationResult, error) {
 	if isVerbose() {
@@ -726,7 +1011,7 @@ func stopMonitoring(collector monitor.Collector) error {
 }
 
 // performAnalysisWithMonitoring wraps the analysis with monitoring if collector is available
-func performAnalysisWithMonitoring(ctx context.Context, entries []*common.LogEntry, patterns []*common.Pattern, collector monitor.Collector) (*analyzer.Analysis, error) {
+func performAnalysisWithMonitoring(ctx context.Context, engine *analyzer.AnalyzerEngine, entries []*common.LogEntry, collector monitor.Collector) (*analyzer.Analysis, error) {
 	if collector != nil {
 		// Track the entire analysis operation
 		var analysis *analyzer.Analysis
@@ -746,14 +1031,46 @@ func performAnalysisWithMonitoring(ctx context.Context, entries []*common.LogEnt
 			}
 
 			var err error
-			analysis, err = performAnalysis(ctx, entries, patterns)
+			analysis, err = performAnalysis(ctx, engine, entries)
 			return err
 		})
 		return analysis, err
 	}
 
 	// Fallback to regular analysis if no monitoring
-	return performAnalysis(ctx, entries, patterns)
+	return performAnalysis(ctx, engine, entries)
+}
+
+// performStreamAnalysisWithMonitoring wraps streaming analysis with monitoring if collector is available
+func performStreamAnalysisWithMonitoring(ctx context.Context, engine *analyzer.AnalyzerEngine, source ingest.Source, collector monitor.Collector) (*analyzer.Analysis, error) {
+	if collector == nil {
+		return performStreamAnalysis(ctx, engine, source)
+	}
+
+	var analysis *analyzer.Analysis
+	err := collector.TrackOperationWithError(monitor.OperationAnalyze, func() error {
+		var err error
+		analysis, err = performStreamAnalysis(ctx, engine, source)
+		if err != nil {
+			return err
+		}
+
+		// Input size is only known once the stream has been consumed
+		metric := monitor.Metric{
+			Name:      "analysis.input_lines",
+			Type:      monitor.MetricTypeGauge,
+			Value:     float64(source.LinesRead()),
+			Timestamp: time.Now(),
+			Labels: map[string]string{
+				"operation": "analyze",
+			},
+		}
+		if err := collector.RecordMetric(&metric); err != nil && isVerbose() {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record input metrics: %v\n", err)
+		}
+		return nil
+	})
+	return analysis, err
 }
 
 // performCorrelationWithMonitoring wraps correlation with monitoring if collector is available
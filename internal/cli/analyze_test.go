@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/yildizm/LogSum/internal/common"
 	"github.com/yildizm/LogSum/internal/correlation"
 	"github.com/yildizm/LogSum/internal/docstore"
+	"github.com/yildizm/LogSum/internal/ingest"
 	"github.com/yildizm/go-logparser"
 )
 
@@ -431,7 +433,7 @@ func TestRunCLIAnalysis(t *testing.T) {
 	defer cancel()
 
 	// This should not panic and should complete without error
-	err := runCLIAnalysis(ctx, entries, patterns)
+	err := runCLIAnalysis(ctx, entries, patterns, nil)
 
 	// We expect this to fail because there's no proper output setup in test environment
 	// But it should fail gracefully, not panic
@@ -439,3 +441,100 @@ func TestRunCLIAnalysis(t *testing.T) {
 		t.Logf("Expected error in test environment: %v", err)
 	}
 }
+
+func TestExpandInputArgs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"api.log", "worker.log", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("line\n"), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	paths, err := expandInputArgs([]string{filepath.Join(dir, "*.log"), filepath.Join(dir, "api.log"), filepath.Join(dir, "notes.txt")})
+	if err != nil {
+		t.Fatalf("expandInputArgs() failed: %v", err)
+	}
+
+	expected := []string{filepath.Join(dir, "api.log"), filepath.Join(dir, "worker.log"), filepath.Join(dir, "notes.txt")}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Path %d: expected %s, got %s", i, expected[i], paths[i])
+		}
+	}
+
+	if _, err := expandInputArgs([]string{filepath.Join(dir, "*.gz")}); err == nil {
+		t.Error("Expected error for glob without matches")
+	}
+}
+
+func TestSetupEntrySourceSharesMaxLines(t *testing.T) {
+	oldMaxLines := analyzeMaxLines
+	analyzeMaxLines = 4
+	defer func() { analyzeMaxLines = oldMaxLines }()
+
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"api.log", "worker.log"} {
+		var lines strings.Builder
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(&lines, "2024-01-01 10:00:%02d [INFO] %s request %d\n", i, name, i)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(lines.String()), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		paths = append(paths, path)
+	}
+
+	source, cleanup, err := setupEntrySource(paths)
+	if err != nil {
+		t.Fatalf("setupEntrySource() failed: %v", err)
+	}
+	defer cleanup()
+
+	entries, err := readAllEntries(source, 0)
+	if err != nil {
+		t.Fatalf("readAllEntries() failed: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("Expected 4 entries across both files, got %d", len(entries))
+	}
+	if source.LinesRead() != 4 {
+		t.Errorf("Expected 4 lines read, got %d", source.LinesRead())
+	}
+	if !source.Truncated() {
+		t.Error("Expected the merged input to be truncated")
+	}
+}
+
+func TestReadAllEntriesLimit(t *testing.T) {
+	input := "2024-01-01 10:00:00 [INFO] one\n2024-01-01 10:00:01 [INFO] two\n2024-01-01 10:00:02 [INFO] three\n"
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "capped", limit: 2, want: 2},
+		{name: "exactly at the cap", limit: 3, want: 3},
+		{name: "unlimited", limit: 0, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := ingest.NewReader(strings.NewReader(input), ingest.Options{Format: "text"})
+			if err != nil {
+				t.Fatalf("NewReader() failed: %v", err)
+			}
+			entries, err := readAllEntries(source, tt.limit)
+			if err != nil {
+				t.Fatalf("readAllEntries() failed: %v", err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("readAllEntries() = %d entries, want %d", len(entries), tt.want)
+			}
+		})
+	}
+}
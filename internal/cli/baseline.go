@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/formatter"
+)
+
+var (
+	analyzeBaseline    string
+	analyzeSaveProfile bool
+)
+
+// baseline is the profile of a known-good input, loaded before the
+// analysis it is compared with
+type baseline struct {
+	path    string
+	profile *common.AnalysisProfile
+}
+
+// loadBaseline profiles a baseline input: a JSON analysis saved with
+// --save-profile, or a log analyzed with the same patterns and input flags
+func loadBaseline(ctx context.Context, path string, patterns []*common.Pattern) (*baseline, error) {
+	if err := validateFilePath(path); err != nil {
+		return nil, fmt.Errorf("invalid baseline path: %w", err)
+	}
+
+	profile, err := readSavedProfile(path)
+	if err != nil {
+		return nil, err
+	}
+	if profile != nil {
+		return &baseline{path: path, profile: profile}, nil
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Analyzing baseline %s...\n", path)
+	}
+	source, cleanup, err := setupEntrySource([]string{path})
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	engine := newAnalysisEngine(patterns)
+	engine.DisableInsights()
+	engine.SetProfiling(true)
+	analysis, err := engine.AnalyzeStream(ctx, source, analyzer.DefaultStreamOptions())
+	if err != nil {
+		return nil, fmt.Errorf("baseline analysis failed: %w", err)
+	}
+	if analysis.TotalEntries == 0 {
+		return nil, fmt.Errorf("no log entries found in baseline %s", path)
+	}
+	return &baseline{path: path, profile: analysis.Profile}, nil
+}
+
+// readSavedProfile returns the profile of a saved JSON analysis, or nil if
+// path holds something else, such as a log
+func readSavedProfile(path string) (*common.AnalysisProfile, error) {
+	// #nosec G304 - path is validated by the caller
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	// A JSON log decodes its first line as an object without a summary
+	var saved formatter.EnhancedJSONOutput
+	if err := json.NewDecoder(bufio.NewReader(file)).Decode(&saved); err != nil || saved.Summary == nil {
+		return nil, nil
+	}
+	if saved.Profile != nil {
+		return saved.Profile, nil
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Baseline %s was saved without --save-profile; comparing volume and patterns only\n", path)
+	}
+	return profileFromOutput(&saved), nil
+}
+
+// profileFromOutput rebuilds what it can of a profile from saved output.
+// Error signatures and services are left unknown.
+func profileFromOutput(saved *formatter.EnhancedJSONOutput) *common.AnalysisProfile {
+	profile := &common.AnalysisProfile{
+		TotalEntries: saved.Summary.TotalEntries,
+		ErrorCount:   saved.Summary.ErrorCount,
+		Patterns:     make(map[string]common.PatternProfile, len(saved.Patterns)),
+	}
+	if saved.Summary.TimeRange != nil {
+		profile.StartTime = saved.Summary.TimeRange.Start
+		profile.EndTime = saved.Summary.TimeRange.End
+	}
+	for _, pattern := range saved.Patterns {
+		if pattern.Pattern != nil && pattern.Matches > 0 {
+			profile.Patterns[pattern.Pattern.ID] = common.PatternProfile{Name: pattern.Pattern.Name, Count: pattern.Matches}
+		}
+	}
+	return profile
+}
+
+// attachBaseline adds the differences from base, if any, to analysis
+func attachBaseline(analysis *analyzer.Analysis, base *baseline) {
+	if base == nil || analysis.Profile == nil {
+		return
+	}
+	analysis.Baseline = analyzer.CompareProfiles(base.profile, analysis.Profile, analyzer.DefaultDiffOptions())
+	analysis.Baseline.Baseline = base.path
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <baseline> <file|glob>...",
+		Short: "Compare logs with a known-good baseline",
+		Long: `Analyze logs and report what differs from a baseline period.
+
+The baseline is a log read like any other input, or an analysis saved with
+-o json --save-profile. The report lists patterns that never matched in the
+baseline, patterns whose rate rose or fell at least twofold, error signatures
+the baseline never had, services whose share of errors shifted by 10 points or
+more, and changes in entry and error volume. Rates are per minute when both
+logs have timestamps, and per 1000 entries otherwise.
+
+diff is analyze --baseline with the baseline as the first argument, so the
+same patterns, input flags and output formats apply to both.
+
+Examples:
+  logsum diff yesterday.log today.log
+  logsum diff -o json baseline.json '/var/log/myapp/*.log'
+  logsum analyze -o json --save-profile known-good.log > baseline.json`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: runDiff,
+	}
+
+	addInputFlags(cmd)
+	cmd.Flags().StringVarP(&analyzePatterns, "patterns", "p", "", "pattern file or directory")
+	cmd.Flags().DurationVar(&analyzeTimeout, "timeout", 30*time.Second, "timeout for analysis, not counting the time spent reading input")
+	cmd.Flags().StringVar(&analyzeOutputFile, "output-file", "", "save output to file instead of stdout")
+	cmd.Flags().BoolVar(&analyzeNoInsights, "no-insights", false, "skip insight generation")
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	return analyzeInputs(cmd, args[1:], args[0])
+}
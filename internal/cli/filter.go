@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/filter"
+	"github.com/yildizm/LogSum/internal/ingest"
+)
+
+// newEntryFilter builds the filter set by --since, --until and --where, or
+// returns nil when none of them is given
+func newEntryFilter() (*filter.Filter, error) {
+	f, err := filter.New(analyzeSince, analyzeUntil, analyzeWhere, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !f.Active() {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// filterSource drops the entries of source that f rejects. A nil filter
+// leaves source unchanged.
+func filterSource(source ingest.Source, f *filter.Filter) ingest.Source {
+	if f == nil {
+		return source
+	}
+	return ingest.NewFilterReader(source, f.Match)
+}
+
+// reportFiltered reports how many entries the filter dropped
+func reportFiltered(source ingest.Source) {
+	if filtered, ok := source.(*ingest.FilterReader); ok && isVerbose() {
+		fmt.Fprintf(os.Stderr, "Filtered out %d entries\n", filtered.Filtered())
+	}
+}
+
+// noEntriesError explains why source produced no entries
+func noEntriesError(source ingest.Source) error {
+	if filtered, ok := source.(*ingest.FilterReader); ok && filtered.Filtered() > 0 {
+		return fmt.Errorf("no log entries match the filter (%d filtered out)", filtered.Filtered())
+	}
+	return fmt.Errorf("no valid log entries found")
+}
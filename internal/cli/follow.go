@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/filter"
+	"github.com/yildizm/LogSum/internal/ingest"
+	"github.com/yildizm/LogSum/internal/ui"
+)
+
+// recentEntryLimit is the number of recent entries kept for the TUI logs view
+const recentEntryLimit = 200
+
+// followState is the live analysis shared between the reading goroutine
+// and the refresh loop
+type followState struct {
+	stream *analyzer.StreamAnalyzer
+
+	mu      sync.Mutex
+	recent  []*common.LogEntry
+	changed bool
+}
+
+// add feeds an entry into the stream and remembers it as recent
+func (fs *followState) add(entry *common.LogEntry) {
+	fs.stream.Add(entry)
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.recent = append(fs.recent, entry)
+	if len(fs.recent) > recentEntryLimit {
+		fs.recent = fs.recent[len(fs.recent)-recentEntryLimit:]
+	}
+	fs.changed = true
+}
+
+// snapshot returns the current analysis if anything changed since the last call
+func (fs *followState) snapshot() (ui.LiveUpdate, bool) {
+	fs.mu.Lock()
+	if !fs.changed {
+		fs.mu.Unlock()
+		return ui.LiveUpdate{}, false
+	}
+	fs.changed = false
+	recent := append([]*common.LogEntry(nil), fs.recent...)
+	fs.mu.Unlock()
+
+	return ui.LiveUpdate{
+		Analysis: fs.stream.Analysis(),
+		Entries:  recent,
+	}, true
+}
+
+// validateFollowArgs checks that --follow is used with a single file and
+// without modes that need the complete input
+func validateFollowArgs(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("--follow requires a file argument")
+	}
+	if len(args) > 1 || isGlobPattern(args[0]) {
+		return fmt.Errorf("--follow accepts a single file; use 'logsum watch' for several files")
+	}
+	if analyzeAI || analyzeCorrelate {
+		return fmt.Errorf("--follow cannot be combined with --ai or --correlate")
+	}
+	return nil
+}
+
+// runFollowAnalysis analyzes a file and keeps analyzing lines appended to it
+// until interrupted, refreshing results in the TUI or as periodic reports
+func runFollowAnalysis(args []string, patterns []*common.Pattern, entryFilter *filter.Filter) error {
+	if err := validateFollowArgs(args); err != nil {
+		return err
+	}
+
+	reader, name, cleanup, err := setupInputReader(args)
+	if err != nil {
+		return err
+	}
+	if cleanup != nil {
+		defer cleanup()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	state := &followState{
+		stream: newAnalysisEngine(patterns).NewStream(analyzer.DefaultStreamOptions()),
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- followInput(ctx, reader, name, entryFilter, state)
+	}()
+
+	if shouldUseTUIMode() {
+		return runFollowTUI(ctx, stop, state, patterns, readErr)
+	}
+	return runFollowReports(ctx, state, readErr)
+}
+
+// followInput reads the existing content of the input, then keeps reading
+// appended lines until ctx is cancelled. Entries entryFilter rejects are
+// not analyzed. One source reads both, so a record still being written at
+// the end of the existing content is held back like any other, and
+// --max-lines does not apply.
+func followInput(ctx context.Context, reader io.Reader, name string, entryFilter *filter.Filter, state *followState) error {
+	options, err := entryReaderOptions(name)
+	if err != nil {
+		return err
+	}
+	options.MaxLines = 0
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Reading %s and following new entries...\n", name)
+	}
+
+	follower, err := ingest.NewFollowSource(ctx, reader, ingest.DefaultPollInterval, options)
+	if err != nil {
+		return err
+	}
+	return feedEntries(filterSource(follower, entryFilter), state)
+}
+
+// feedEntries adds every entry from source to the follow state
+func feedEntries(source ingest.Source, state *followState) error {
+	for {
+		entry, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		state.add(entry)
+	}
+}
+
+// runFollowTUI shows live analysis results in the interactive TUI
+func runFollowTUI(ctx context.Context, stop context.CancelFunc, state *followState, patterns []*common.Pattern, readErr <-chan error) error {
+	updates := make(chan ui.LiveUpdate, 1)
+
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(analyzeRefresh)
+		defer ticker.Stop()
+
+		for {
+			if update, ok := state.snapshot(); ok {
+				select {
+				case updates <- update:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	err := ui.InteractiveFollowRun(updates, patterns)
+	stop()
+
+	if readErrValue := <-readErr; readErrValue != nil && err == nil {
+		err = readErrValue
+	}
+	return err
+}
+
+// runFollowReports prints a refreshed report whenever new entries have been
+// analyzed, until interrupted
+func runFollowReports(ctx context.Context, state *followState, readErr <-chan error) error {
+	ticker := time.NewTicker(analyzeRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if isVerbose() {
+				fmt.Fprintf(os.Stderr, "\nReceived interrupt signal, stopping...\n")
+			}
+			return <-readErr
+
+		case err := <-readErr:
+			return err
+
+		case <-ticker.C:
+			update, ok := state.snapshot()
+			if !ok {
+				continue
+			}
+			if getOutputFormat() == "text" && analyzeOutputFile == "" {
+				fmt.Printf("\n--- Follow update %s (%d entries) ---\n",
+					time.Now().Format("15:04:05"), update.Analysis.TotalEntries)
+			}
+			if err := formatAndOutputResults(update.Analysis, nil); err != nil {
+				return err
+			}
+		}
+	}
+}
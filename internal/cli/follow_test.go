@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/ingest"
+)
+
+func TestFollowInputReadsWholeFileAndHoldsLastRecord(t *testing.T) {
+	oldMaxLines := analyzeMaxLines
+	analyzeMaxLines = 10
+	defer func() { analyzeMaxLines = oldMaxLines }()
+
+	// More existing lines than --max-lines, ending with a record that is
+	// still being written
+	var existing strings.Builder
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&existing, "2024-01-01 10:00:%02d [INFO] request %d served\n", i, i)
+	}
+	existing.WriteString("2024-01-01 10:01:00 [ERROR] request failed\n")
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeLogFile(t, path, existing.String(), os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open log file: %v", err)
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	state := &followState{stream: newAnalysisEngine(nil).NewStream(analyzer.DefaultStreamOptions())}
+	done := make(chan error, 1)
+	go func() { done <- followInput(ctx, file, path, nil, state) }()
+
+	// The continuation arrives after the existing lines were analyzed
+	for deadline := time.Now().Add(5 * time.Second); state.stream.Analysis().TotalEntries < 50; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the existing entries, got %d", state.stream.Analysis().TotalEntries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	writeLogFile(t, path, "    at handler (app.js:10)\n", os.O_APPEND|os.O_WRONLY)
+	time.Sleep(3 * ingest.DefaultPollInterval)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("followInput() failed: %v", err)
+	}
+
+	update, _ := state.snapshot()
+	if update.Analysis.TotalEntries != 51 {
+		t.Fatalf("Expected 51 entries, got %d", update.Analysis.TotalEntries)
+	}
+	last := update.Entries[len(update.Entries)-1]
+	if last.Message != "request failed" || !strings.Contains(last.Raw, "app.js:10") {
+		t.Errorf("Expected the stack trace joined onto the last record, got %+v", last)
+	}
+}
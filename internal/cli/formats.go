@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/yildizm/LogSum/internal/config"
+	"github.com/yildizm/LogSum/internal/ingest"
+	"gopkg.in/yaml.v3"
+)
+
+// formatFile is the layout of a --format-file, which matches the formats
+// section of the config file so definitions can be moved between the two
+type formatFile struct {
+	Formats map[string]config.FormatConfig `yaml:"formats"`
+}
+
+// lineFormats compiles the custom formats from the config and, when given,
+// the format file. Definitions in the format file take precedence.
+func lineFormats(formatFilePath string) ([]ingest.LineFormat, error) {
+	definitions := make(map[string]config.FormatConfig)
+	for name, definition := range GetGlobalConfig().Formats {
+		definitions[name] = definition
+	}
+
+	if formatFilePath != "" {
+		fileFormats, err := loadFormatFile(formatFilePath)
+		if err != nil {
+			return nil, err
+		}
+		for name, definition := range fileFormats {
+			definitions[name] = definition
+		}
+	}
+
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	formats := make([]ingest.LineFormat, 0, len(names))
+	for _, name := range names {
+		definition := definitions[name]
+		format, err := ingest.NewCustomFormat(name, ingest.FormatDefinition{
+			Regex:           definition.Regex,
+			Grok:            definition.Grok,
+			TimestampFormat: definition.TimestampFormat,
+			Fields:          definition.Fields,
+		})
+		if err != nil {
+			return nil, err
+		}
+		formats = append(formats, format)
+	}
+	return formats, nil
+}
+
+// loadFormatFile reads custom format definitions from a YAML file
+func loadFormatFile(path string) (map[string]config.FormatConfig, error) {
+	if err := validateFilePath(path); err != nil {
+		return nil, fmt.Errorf("invalid format file: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read format file: %w", err)
+	}
+
+	var file formatFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse format file %s: %w", path, err)
+	}
+	if len(file.Formats) == 0 {
+		return nil, fmt.Errorf("format file %s defines no formats", path)
+	}
+	if err := config.ValidateFormats(file.Formats); err != nil {
+		return nil, fmt.Errorf("invalid format file %s: %w", path, err)
+	}
+	return file.Formats, nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLineFormatsFromFormatFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "formats.yaml")
+	content := `formats:
+  legacy:
+    grok: '%{TIMESTAMP_ISO8601:timestamp} %{LOGLEVEL:level} %{GREEDYDATA:message}'
+  haproxy:
+    regex: '^(?P<client>\S+) (?P<message>.*)$'
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write format file: %v", err)
+	}
+
+	formats, err := lineFormats(path)
+	if err != nil {
+		t.Fatalf("lineFormats() failed: %v", err)
+	}
+	if len(formats) != 2 || formats[0].Name() != "haproxy" || formats[1].Name() != "legacy" {
+		t.Fatalf("Expected haproxy and legacy formats, got %d", len(formats))
+	}
+
+	entry, err := formats[1].ParseLine("2024-01-01 10:00:00 ERROR disk full")
+	if err != nil {
+		t.Fatalf("ParseLine() failed: %v", err)
+	}
+	if entry.Message != "disk full" {
+		t.Errorf("Expected message 'disk full', got %q", entry.Message)
+	}
+}
+
+func TestLoadFormatFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no formats", "version: \"1.0\"\n"},
+		{"reserved name", "formats:\n  json:\n    regex: '.*'\n"},
+		{"missing expression", "formats:\n  legacy:\n    timestamp_format: '2006'\n"},
+		{"invalid yaml", "formats: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "formats.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write format file: %v", err)
+			}
+			if _, err := loadFormatFile(path); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	if _, err := loadFormatFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/config"
+)
+
+// insightOptions builds the insight detector options from the config,
+// overridden by the analyze flags
+func insightOptions(cfg *config.Config) (analyzer.InsightOptions, error) {
+	options := convertInsights(cfg.Insights)
+	if analyzeAnomalyMethod != "" {
+		options.Statistical.Method = analyzer.AnomalyMethod(analyzeAnomalyMethod)
+	}
+	if analyzeAnomalySensitivity != 0 {
+		options.Statistical.Sensitivity = analyzeAnomalySensitivity
+	}
+	return options, options.Validate()
+}
+
+// convertInsights converts config insight tuning to analyzer options. Zero
+// values keep the analyzer defaults.
+func convertInsights(cfg config.InsightsConfig) analyzer.InsightOptions {
+	options := analyzer.DefaultInsightOptions()
+	setFloat(&options.MinConfidence, cfg.MinConfidence)
+	setInt(&options.EvidenceLimit, cfg.EvidenceLimit)
+
+	spikes := &options.ErrorSpikes
+	spikes.Enabled = !cfg.ErrorSpikes.Disabled
+	setFloat(&spikes.Threshold, cfg.ErrorSpikes.Threshold)
+	if cfg.ErrorSpikes.Window > 0 {
+		spikes.Window = cfg.ErrorSpikes.Window
+	}
+	setInt(&spikes.MinEntries, cfg.ErrorSpikes.MinEntries)
+
+	options.Performance.Enabled = !cfg.Performance.Disabled
+	if len(cfg.Performance.SlowKeywords) > 0 {
+		options.Performance.SlowKeywords = cfg.Performance.SlowKeywords
+	}
+
+	anomalies := &options.Anomalies
+	anomalies.Enabled = !cfg.Anomalies.Disabled
+	setFloat(&anomalies.ServiceErrorRate, cfg.Anomalies.ServiceErrorRate)
+	setInt(&anomalies.ServiceMinEntries, cfg.Anomalies.ServiceMinEntries)
+
+	statistical := &options.Statistical
+	statistical.Enabled = !cfg.Statistical.Disabled
+	if cfg.Statistical.Method != "" {
+		statistical.Method = analyzer.AnomalyMethod(cfg.Statistical.Method)
+	}
+	setFloat(&statistical.Sensitivity, cfg.Statistical.Sensitivity)
+	setInt(&statistical.Window, cfg.Statistical.Window)
+	setInt(&statistical.MinBaseline, cfg.Statistical.MinBaseline)
+	setInt(&statistical.MinCount, cfg.Statistical.MinCount)
+	setFloat(&statistical.Alpha, cfg.Statistical.Alpha)
+	if cfg.Statistical.BucketSize > 0 {
+		statistical.BucketSize = cfg.Statistical.BucketSize
+	}
+
+	options.RootCauses.Enabled = !cfg.RootCauses.Disabled
+	setFloat(&options.RootCauses.CorrelationThreshold, cfg.RootCauses.CorrelationThreshold)
+
+	if len(cfg.Tags) > 0 {
+		options.Tags = make(map[string]analyzer.TagOptions, len(cfg.Tags))
+		for tag, tuning := range cfg.Tags {
+			options.Tags[tag] = analyzer.TagOptions{
+				Disabled:             tuning.Disabled,
+				MinConfidence:        tuning.MinConfidence,
+				Sensitivity:          tuning.Sensitivity,
+				CorrelationThreshold: tuning.CorrelationThreshold,
+			}
+		}
+	}
+	return options
+}
+
+// setInt sets dst to a non-zero value
+func setInt(dst *int, value int) {
+	if value != 0 {
+		*dst = value
+	}
+}
+
+// setFloat sets dst to a non-zero value
+func setFloat(dst *float64, value float64) {
+	if value != 0 {
+		*dst = value
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/config"
+)
+
+func TestInsightOptions(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Insights.RootCauses.Disabled = true
+	cfg.Insights.Statistical.BucketSize = 10 * time.Second
+	cfg.Insights.Tags = map[string]config.InsightTagConfig{"critical": {Sensitivity: 2}}
+
+	options, err := insightOptions(cfg)
+	if err != nil {
+		t.Fatalf("insightOptions() failed: %v", err)
+	}
+	if options.RootCauses.Enabled || !options.ErrorSpikes.Enabled {
+		t.Errorf("only root causes should be disabled: %+v", options)
+	}
+	if options.Statistical.BucketSize != 10*time.Second || options.Statistical.Method != analyzer.AnomalyZScore {
+		t.Errorf("statistical = %+v, want zscore over 10s buckets", options.Statistical)
+	}
+	if options.Tags["critical"].Sensitivity != 2 {
+		t.Errorf("tags = %+v, want critical at sensitivity 2", options.Tags)
+	}
+
+	// Zero config values keep the analyzer defaults
+	options, err = insightOptions(&config.Config{})
+	if err != nil {
+		t.Fatalf("insightOptions() with an empty config failed: %v", err)
+	}
+	if options.ErrorSpikes != analyzer.DefaultInsightOptions().ErrorSpikes {
+		t.Errorf("error spikes = %+v, want the defaults", options.ErrorSpikes)
+	}
+
+	analyzeAnomalyMethod, analyzeAnomalySensitivity = "ewma", 4
+	defer func() { analyzeAnomalyMethod, analyzeAnomalySensitivity = "", 0 }()
+	options, err = insightOptions(cfg)
+	if err != nil {
+		t.Fatalf("insightOptions() with flags failed: %v", err)
+	}
+	if options.Statistical.Method != analyzer.AnomalyEWMA || options.Statistical.Sensitivity != 4 {
+		t.Errorf("statistical = %+v, want the flag values", options.Statistical)
+	}
+
+	analyzeAnomalyMethod = "median"
+	if _, err := insightOptions(cfg); err == nil {
+		t.Error("insightOptions() with an unknown method succeeded, want error")
+	}
+}
+
+func TestTemplateOptions(t *testing.T) {
+	options := templateOptions(config.TemplatesConfig{Limit: 5, Similarity: 0.6})
+	defaults := analyzer.DefaultTemplateOptions()
+	if options.Limit != 5 || options.Similarity != 0.6 {
+		t.Errorf("options = %+v, want limit 5 and similarity 0.6", options)
+	}
+	if options.Depth != defaults.Depth || options.MaxExamples != defaults.MaxExamples {
+		t.Errorf("options = %+v, want the default depth and examples", options)
+	}
+
+	if options := templateOptions(config.TemplatesConfig{Disabled: true, Limit: 5}); options.Limit != 0 {
+		t.Errorf("limit = %d with mining disabled, want 0", options.Limit)
+	}
+}
@@ -11,35 +11,72 @@ import (
 )
 
 // PatternLoader handles loading and processing of log analysis patterns from various sources.
-type PatternLoader struct{}
+type PatternLoader struct {
+	suppressions []*common.SuppressionRule // rules read from pattern files
+}
 
 // NewPatternLoader creates a new pattern loader instance.
 func NewPatternLoader() *PatternLoader {
 	return &PatternLoader{}
 }
 
-// LoadAnalysisPatterns loads patterns based on configuration precedence:
+// LoadAnalysisPatterns loads patterns for the analyze command, honouring
+// its --patterns flag.
+func (pl *PatternLoader) LoadAnalysisPatterns() []*common.Pattern {
+	return pl.LoadPatterns(analyzePatterns)
+}
+
+// LoadPatterns loads patterns based on configuration precedence:
 // 1. Command line flag patterns (highest priority)
 // 2. Config file directory patterns
 // 3. Config file custom patterns
 // 4. Default embedded patterns (lowest priority)
-func (pl *PatternLoader) LoadAnalysisPatterns() []*common.Pattern {
+//
+// Suppression rules from the loaded pattern files and the config file are
+// attached to the patterns they silence.
+func (pl *PatternLoader) LoadPatterns(flagPath string) []*common.Pattern {
 	cfg := GetGlobalConfig()
 
+	var patterns []*common.Pattern
 	// Check if patterns flag was explicitly set
-	if analyzePatterns != "" {
-		return pl.loadPatternsFromFlag()
+	if flagPath != "" {
+		patterns = pl.loadPatternsFromFlag(flagPath)
+	} else {
+		patterns = pl.loadPatternsFromConfig(cfg)
 	}
 
-	return pl.loadPatternsFromConfig(cfg)
+	rules := append(pl.suppressions, convertSuppressions(cfg.Patterns.Suppress)...)
+	common.AttachSuppressions(patterns, rules)
+	if isVerbose() && len(rules) > 0 {
+		fmt.Fprintf(os.Stderr, "Loaded %d suppression rules\n", len(rules))
+	}
+	return patterns
 }
 
-// loadPatternsFromFlag loads patterns from the command line flag.
-func (pl *PatternLoader) loadPatternsFromFlag() []*common.Pattern {
-	loadedPatterns, err := pl.loadPatternsFromPath(analyzePatterns)
+// convertSuppressions converts config suppression rules to common.SuppressionRule format.
+func convertSuppressions(configs []config.SuppressConfig) []*common.SuppressionRule {
+	rules := make([]*common.SuppressionRule, len(configs))
+	for i, rule := range configs {
+		rules[i] = &common.SuppressionRule{
+			ID:          rule.ID,
+			Description: rule.Description,
+			Regex:       rule.Regex,
+			Condition:   rule.Condition,
+			Sources:     rule.Sources,
+			Since:       rule.Since,
+			Until:       rule.Until,
+			Patterns:    rule.Patterns,
+		}
+	}
+	return rules
+}
+
+// loadPatternsFromFlag loads patterns from a command line flag path.
+func (pl *PatternLoader) loadPatternsFromFlag(path string) []*common.Pattern {
+	loadedPatterns, err := pl.loadPatternsFromPath(path)
 	if err != nil {
 		if isVerbose() {
-			fmt.Fprintf(os.Stderr, "Warning: failed to load patterns from %s: %v\n", analyzePatterns, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to load patterns from %s: %v\n", path, err)
 		}
 		return nil
 	}
@@ -135,7 +172,7 @@ func (pl *PatternLoader) loadPatternsFromDirectory(directory string) ([]*common.
 			return err
 		}
 
-		if !info.IsDir() && (strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")) {
+		if isPatternFile(path, info) {
 			filePatterns, err := pl.loadPatternsFromFile(path)
 			if err != nil {
 				// Log specific error instead of silently continuing
@@ -152,9 +189,24 @@ func (pl *PatternLoader) loadPatternsFromDirectory(directory string) ([]*common.
 	return patterns, err
 }
 
-// loadPatternsFromFile loads patterns from a single YAML file.
+// isPatternFile reports whether a directory entry is a pattern YAML file,
+// skipping the sidecar files that hold pattern tests.
+func isPatternFile(path string, info os.FileInfo) bool {
+	if info.IsDir() || common.IsPatternTestFile(path) {
+		return false
+	}
+	return strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+}
+
+// loadPatternsFromFile loads patterns from a single YAML file, keeping
+// any suppression rules it holds.
 func (pl *PatternLoader) loadPatternsFromFile(filename string) ([]*common.Pattern, error) {
-	return common.LoadPatternsFromFile(filename)
+	patterns, rules, err := common.LoadPatternFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	pl.suppressions = append(pl.suppressions, rules...)
+	return patterns, nil
 }
 
 // convertCustomPatterns converts config custom patterns to common.Pattern format.
@@ -317,6 +317,69 @@ func TestLoadPatternsFromDirectory(t *testing.T) {
 	_ = patterns // Use the variable to avoid unused warning
 }
 
+func TestLoadPatternsFromDirectorySkipsTestFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"app.yaml":      "- id: app_error\n  name: App Error\n  type: error\n  keywords: [\"boom\"]\n",
+		"app_test.yaml": "- pattern: app_error\n  match: [\"boom\"]\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	patterns, err := NewPatternLoader().loadPatternsFromDirectory(tempDir)
+	if err != nil {
+		t.Fatalf("loadPatternsFromDirectory() failed: %v", err)
+	}
+	if len(patterns) != 1 || patterns[0].ID != "app_error" {
+		t.Errorf("loadPatternsFromDirectory() = %d patterns, want only app_error", len(patterns))
+	}
+}
+
+func TestLoadPatternsAttachesSuppressions(t *testing.T) {
+	oldGlobalConfig := globalConfig
+	defer func() {
+		globalConfig = oldGlobalConfig
+	}()
+	globalConfig = &config.Config{
+		Patterns: config.PatternConfig{
+			Suppress: []config.SuppressConfig{{ID: "canary", Sources: []string{"canary.log"}}},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	content := `patterns:
+  - id: app_error
+    name: App Error
+    type: error
+    keywords: ["boom"]
+  - id: db_error
+    name: DB Error
+    type: error
+    keywords: ["refused"]
+suppress:
+  - id: health
+    regex: "health"
+    patterns: [db_error]
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to create pattern file: %v", err)
+	}
+
+	patterns := NewPatternLoader().LoadPatterns(path)
+	if len(patterns) != 2 {
+		t.Fatalf("LoadPatterns() = %d patterns, want 2", len(patterns))
+	}
+	want := map[string]int{"app_error": 1, "db_error": 2}
+	for _, pattern := range patterns {
+		if len(pattern.Suppressions) != want[pattern.ID] {
+			t.Errorf("%s has %d suppression rules, want %d", pattern.ID, len(pattern.Suppressions), want[pattern.ID])
+		}
+	}
+}
+
 // Test verbose logging behavior
 func TestVerboseLogging(t *testing.T) {
 	// Save original verbose state
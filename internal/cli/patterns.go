@@ -3,10 +3,15 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yildizm/LogSum/internal/analyzer"
 	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/filter"
 )
 
 func newPatternsCommand() *cobra.Command {
@@ -21,6 +26,9 @@ performance issues, and security events.`,
 
 	cmd.AddCommand(newPatternsListCommand())
 	cmd.AddCommand(newPatternsValidateCommand())
+	cmd.AddCommand(newPatternsTestCommand())
+	cmd.AddCommand(newPatternsSuggestCommand())
+	cmd.AddCommand(newPatternsCoverageCommand())
 
 	return cmd
 }
@@ -60,6 +68,38 @@ Checks YAML syntax and verifies required fields are present.`,
 	return cmd
 }
 
+func newPatternsTestCommand() *cobra.Command {
+	var requireTests bool
+
+	cmd := &cobra.Command{
+		Use:   "test [file|directory...]",
+		Short: "Run pattern tests",
+		Long: `Run the tests of one or more pattern files.
+
+A pattern's tests are sample log lines it must match and must not match,
+given inline under "tests:" or in a sidecar file next to the pattern file
+(app_test.yaml for app.yaml):
+
+  - pattern: database_connection_error
+    match:
+      - "ERROR db: connection refused by 10.0.0.5:5432"
+    no_match:
+      - "INFO db: connection pool resized"
+
+Each line is matched as a raw log line by a matcher holding only that
+pattern. Exits with an error if any test fails, so it can run in CI.`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPatternsTest(args, requireTests)
+		},
+	}
+
+	cmd.Flags().BoolVar(&requireTests, "require-tests", false, "fail when a pattern has no tests")
+
+	return cmd
+}
+
 func runPatternsList(directory string) error {
 	if isVerbose() {
 		fmt.Fprintf(os.Stderr, "Scanning patterns directory: %s\n", directory)
@@ -125,6 +165,133 @@ func runPatternsValidate(files []string) error {
 	return nil
 }
 
+func runPatternsTest(paths []string, requireTests bool) error {
+	files, err := patternTestTargets(paths)
+	if err != nil {
+		return err
+	}
+
+	var patterns, tests, failed, untested int
+	for _, file := range files {
+		if isVerbose() {
+			fmt.Fprintf(os.Stderr, "Testing: %s\n", file)
+		}
+
+		filePatterns, err := loadPatternsWithTests(file)
+		if err != nil {
+			fmt.Printf("%s %s: %v\n", GetEmoji("error"), file, err)
+			failed++
+			continue
+		}
+
+		for _, pattern := range filePatterns {
+			patterns++
+			report := analyzer.TestPattern(pattern)
+			tests += len(report.Results)
+			failed += report.Failed()
+			if len(report.Results) == 0 {
+				untested++
+			}
+			printPatternTestReport(file, report, requireTests)
+		}
+	}
+
+	fmt.Printf("\n%d patterns, %d tests, %d failed, %d without tests\n", patterns, tests, failed, untested)
+
+	if failed > 0 {
+		return fmt.Errorf("%d pattern tests failed", failed)
+	}
+	if requireTests && untested > 0 {
+		return fmt.Errorf("%d patterns have no tests", untested)
+	}
+	return nil
+}
+
+// patternTestTargets expands directories into the pattern files they hold
+func patternTestTargets(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("path does not exist: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if isPatternFile(file, info) {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+		}
+	}
+	return files, nil
+}
+
+// loadPatternsWithTests loads a pattern file along with its sidecar tests
+func loadPatternsWithTests(file string) ([]*common.Pattern, error) {
+	patterns, err := loadPatternsFromFileCmd(file)
+	if err != nil {
+		return nil, err
+	}
+
+	testFile := common.PatternTestFile(file)
+	if _, err := os.Stat(testFile); err != nil {
+		return patterns, nil
+	}
+	cases, err := common.LoadPatternTestFile(testFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", testFile, err)
+	}
+	if err := common.AttachPatternTests(patterns, cases); err != nil {
+		return nil, fmt.Errorf("%s: %w", testFile, err)
+	}
+	return patterns, nil
+}
+
+// printPatternTestReport prints one pattern's result, listing failed lines
+func printPatternTestReport(file string, report *analyzer.PatternTestReport, requireTests bool) {
+	id := report.Pattern.ID
+	switch {
+	case report.Err != nil:
+		fmt.Printf("%s %s: %s: %v\n", GetEmoji("error"), file, id, report.Err)
+		return
+	case len(report.Results) == 0:
+		if requireTests {
+			fmt.Printf("%s %s: %s: no tests\n", GetEmoji("error"), file, id)
+		} else if isVerbose() {
+			fmt.Printf("%s %s: %s: no tests\n", GetEmoji("warning"), file, id)
+		}
+		return
+	}
+
+	failed := report.Failed()
+	if failed == 0 {
+		fmt.Printf("%s %s: %s: %d passed\n", GetEmoji("success"), file, id, len(report.Results))
+		return
+	}
+
+	fmt.Printf("%s %s: %s: %d of %d failed\n", GetEmoji("error"), file, id, failed, len(report.Results))
+	for _, result := range report.Results {
+		if result.Passed {
+			continue
+		}
+		expectation := "should match"
+		if !result.WantMatch {
+			expectation = "should not match"
+		}
+		fmt.Printf("  %s: %q\n", expectation, result.Line)
+	}
+}
+
 func loadPatternsFromDirectoryCmd(directory string) ([]*common.Pattern, error) {
 	return common.LoadDefaultPatterns()
 }
@@ -134,7 +301,7 @@ func loadPatternsFromFileCmd(filename string) ([]*common.Pattern, error) {
 }
 
 func validatePatternFile(filename string) (bool, error) {
-	patterns, err := loadPatternsFromFileCmd(filename)
+	patterns, rules, err := common.LoadPatternFile(filename)
 	if err != nil {
 		return false, err
 	}
@@ -146,10 +313,45 @@ func validatePatternFile(filename string) (bool, error) {
 			valid = false
 		}
 	}
+	for i, rule := range rules {
+		if err := validateSuppressionRule(rule); err != nil {
+			fmt.Printf("  Suppression rule %d: %v\n", i, err)
+			valid = false
+		}
+	}
 
 	return valid, nil
 }
 
+// validateSuppressionRule checks that a rule's regex, condition, sources
+// and time window parse
+func validateSuppressionRule(rule *common.SuppressionRule) error {
+	if rule.Regex != "" {
+		if _, err := regexp.Compile(rule.Regex); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+	if rule.Condition != "" {
+		if _, err := filter.Parse(rule.Condition); err != nil {
+			return fmt.Errorf("invalid condition: %w", err)
+		}
+	}
+	for _, source := range rule.Sources {
+		if _, err := filepath.Match(source, ""); err != nil {
+			return fmt.Errorf("invalid source %q: %w", source, err)
+		}
+	}
+	for _, value := range []string{rule.Since, rule.Until} {
+		if value == "" {
+			continue
+		}
+		if _, err := filter.ParseTime(value, time.Now()); err != nil {
+			return fmt.Errorf("invalid time window: %w", err)
+		}
+	}
+	return nil
+}
+
 func validatePattern(pattern *common.Pattern, index int) error {
 	if pattern.ID == "" {
 		return fmt.Errorf("missing required field: id")
@@ -169,15 +371,42 @@ func validatePattern(pattern *common.Pattern, index int) error {
 		common.PatternTypeAnomaly:     true,
 		common.PatternTypePerformance: true,
 		common.PatternTypeSecurity:    true,
+		common.PatternTypeSequence:    true,
 	}
 
 	if !validTypes[pattern.Type] {
-		return fmt.Errorf("invalid pattern type: %s. Valid types: error, anomaly, performance, security", pattern.Type)
+		return fmt.Errorf("invalid pattern type: %s. Valid types: error, anomaly, performance, security, sequence", pattern.Type)
 	}
 
-	// Must have either regex or keywords
-	if pattern.Regex == "" && len(pattern.Keywords) == 0 {
-		return fmt.Errorf("pattern must have either regex or keywords")
+	// Sequence patterns match their steps instead of single lines
+	if pattern.Type == common.PatternTypeSequence {
+		if pattern.Sequence == nil {
+			return fmt.Errorf("sequence pattern must have a sequence")
+		}
+		return pattern.Sequence.Validate()
+	}
+
+	// Must have something to match
+	if pattern.Regex == "" && len(pattern.Keywords) == 0 && pattern.Condition == "" {
+		return fmt.Errorf("pattern must have a regex, keywords or a condition")
+	}
+
+	if pattern.Condition != "" {
+		if _, err := filter.ParseCase(pattern.Condition, pattern.CaseSensitive); err != nil {
+			return fmt.Errorf("invalid condition: %w", err)
+		}
+	}
+
+	for _, exclude := range pattern.Exclude {
+		if _, err := regexp.Compile(exclude); err != nil {
+			return fmt.Errorf("invalid exclude regex: %w", err)
+		}
+	}
+
+	if pattern.Threshold != nil {
+		if err := pattern.Threshold.Validate(); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/formatter"
+)
+
+var coverageTop int
+
+func newPatternsCoverageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "coverage [file|glob]...",
+		Short: "Report which problem lines no pattern explains",
+		Long: `Measure how well the loaded patterns cover a log or a corpus of logs.
+
+Reports the share of WARN, ERROR and FATAL entries matched by at least one
+pattern, the most frequent unmatched messages grouped by a normalized
+signature (IDs, addresses, paths and quoted values masked), and the patterns
+that never fired across all the input.
+
+Input is read exactly as by analyze, so several files are merged into one
+corpus and formats, compressed files, archives and filters all apply.
+
+Examples:
+  logsum patterns coverage app.log
+  logsum patterns coverage -p ./patterns/ '/var/log/myapp/*.log'
+  logsum patterns coverage --top 50 -o json app.log worker.log`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runPatternsCoverage,
+	}
+
+	addInputFlags(cmd)
+	cmd.Flags().StringVarP(&analyzePatterns, "patterns", "p", "", "pattern file or directory to measure")
+	cmd.Flags().IntVar(&coverageTop, "top", 20, "unmatched signatures to list (0 = all)")
+	cmd.Flags().StringVar(&analyzeOutputFile, "output-file", "", "save output to file instead of stdout")
+
+	return cmd
+}
+
+func runPatternsCoverage(cmd *cobra.Command, args []string) error {
+	entryFilter, err := newEntryFilter()
+	if err != nil {
+		return err
+	}
+
+	engine := analyzer.NewEngine()
+	if err := engine.SetPatterns(NewPatternLoader().LoadAnalysisPatterns()); err != nil {
+		return fmt.Errorf("failed to load patterns: %w", err)
+	}
+	coverage := engine.NewCoverage(coverageTop)
+
+	source, cleanup, err := setupEntrySource(args)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	source = filterSource(source, entryFilter)
+
+	for {
+		entry, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		coverage.Add(entry)
+	}
+	reportReaderStats(source)
+
+	output, err := formatter.FormatCoverage(getOutputFormat(), coverage.Report())
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	return handleOutputDestination(output)
+}
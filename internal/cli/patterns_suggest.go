@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/common"
+	yaml "gopkg.in/yaml.v3"
+)
+
+var (
+	suggestMinCount int
+	suggestLimit    int
+	suggestAI       bool
+)
+
+func newPatternsSuggestCommand() *cobra.Command {
+	defaults := analyzer.DefaultSuggestOptions()
+
+	cmd := &cobra.Command{
+		Use:   "suggest [file|glob]...",
+		Short: "Suggest patterns for unmatched error lines",
+		Long: `Suggest new patterns from ERROR and FATAL entries that no loaded pattern matches.
+
+The unmatched entries are clustered into message templates, and each template
+seen often enough becomes a pattern in the same YAML format as the built-in
+patterns, ready to review and add to a patterns directory. Variable parts of a
+message become regex wildcards; messages without any become keywords.
+
+With --ai, the configured AI provider proposes IDs, names, descriptions and
+tags for the suggested patterns. Their matching rules are left unchanged.
+
+Input is read exactly as by analyze, so formats, compressed files, archives
+and --since/--until/--where filters all apply.
+
+Examples:
+  logsum patterns suggest app.log > patterns/new.yaml
+  logsum patterns suggest --min-count 5 --limit 10 '/var/log/myapp/*.log'
+  logsum patterns suggest --since 2h --ai app.log`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runPatternsSuggest,
+	}
+
+	addInputFlags(cmd)
+	cmd.Flags().StringVarP(&analyzePatterns, "patterns", "p", "", "pattern file or directory to check lines against")
+	cmd.Flags().IntVar(&suggestMinCount, "min-count", defaults.MinCount, "minimum entries a template needs to be suggested")
+	cmd.Flags().IntVar(&suggestLimit, "limit", defaults.Limit, "maximum number of suggestions (0 = unlimited)")
+	cmd.Flags().BoolVar(&suggestAI, "ai", false, "have the AI provider name and describe the suggestions")
+
+	return cmd
+}
+
+func runPatternsSuggest(cmd *cobra.Command, args []string) error {
+	entryFilter, err := newEntryFilter()
+	if err != nil {
+		return err
+	}
+
+	engine := analyzer.NewEngine()
+	if err := engine.SetPatterns(NewPatternLoader().LoadAnalysisPatterns()); err != nil {
+		return fmt.Errorf("failed to load patterns: %w", err)
+	}
+	suggester := engine.NewPatternSuggester(analyzer.SuggestOptions{
+		MinCount: suggestMinCount,
+		Limit:    suggestLimit,
+	})
+
+	source, cleanup, err := setupEntrySource(args)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	source = filterSource(source, entryFilter)
+
+	for {
+		entry, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		suggester.Add(entry)
+	}
+	reportReaderStats(source)
+
+	suggestions := suggester.Suggestions()
+	if len(suggestions) == 0 {
+		fmt.Fprintln(os.Stderr, "No unmatched error lines to suggest patterns for")
+		return nil
+	}
+
+	if suggestAI {
+		if err := nameSuggestionsWithAI(suggester, suggestions); err != nil {
+			return err
+		}
+	}
+
+	output, err := formatSuggestions(suggestions, args)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(output))
+	return nil
+}
+
+// nameSuggestionsWithAI has the configured AI provider name the suggestions
+func nameSuggestionsWithAI(suggester *analyzer.PatternSuggester, suggestions []analyzer.PatternSuggestion) error {
+	cfg := GetGlobalConfig()
+	provider, err := createAIProvider(&cfg.AI)
+	if err != nil {
+		return fmt.Errorf("failed to create AI provider: %w", err)
+	}
+	defer func() { _ = provider.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.Timeout)
+	defer cancel()
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Naming %d suggestions with %s...\n", len(suggestions), provider.Name())
+	}
+	if err := suggester.NameWithAI(ctx, provider, suggestions); err != nil {
+		return fmt.Errorf("AI naming failed: %w", err)
+	}
+	return nil
+}
+
+// formatSuggestions renders suggestions as a pattern file, with a comment
+// giving each pattern's template and frequency
+func formatSuggestions(suggestions []analyzer.PatternSuggestion, inputs []string) ([]byte, error) {
+	source := "stdin"
+	if len(inputs) > 0 {
+		source = strings.Join(inputs, ", ")
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "# Patterns suggested by logsum from unmatched error lines in %s\n", source)
+	b.WriteString("# Review names, severities and matching rules before use\n")
+
+	for _, suggestion := range suggestions {
+		data, err := yaml.Marshal([]*common.Pattern{suggestion.Pattern})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode pattern %s: %w", suggestion.Pattern.ID, err)
+		}
+		template := suggestion.Template
+		fmt.Fprintf(&b, "\n# %d entries like: %s\n", template.Count, template.Template)
+		b.Write(data)
+	}
+	return []byte(b.String()), nil
+}
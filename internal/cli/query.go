@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yildizm/LogSum/internal/formatter"
+	"github.com/yildizm/LogSum/internal/query"
+)
+
+func newQueryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query QUERY [file|glob]...",
+		Short: "Filter, count and group log entries",
+		Long: `Answer ad-hoc questions about logs with a small query language.
+
+Input is read and parsed exactly as by analyze, so every format, compressed
+files, archives and several merged files are supported. A query is a pipeline
+of stages separated by | with spaces around it. A | followed by anything but a
+stage keyword stays in the expression, as in 'message~timeout|refused | count':
+
+  where EXPR                    keep entries matching a filter expression
+  count [by F[, F...]] [per D]  count entries per group and time bucket
+  top N F[, F...]               the N most frequent values of the fields
+  fields F[, F...]              columns to list when not aggregating
+  limit N                       at most N result rows
+
+Fields are level, message, service, trace_id, source, timestamp, line, raw or
+any metadata field. Filter expressions are the same as for --where. A query
+without count or top lists the matching entries.
+
+Results are printed as a table, or as JSON, Markdown or CSV with --output.
+
+Examples:
+  logsum query 'level>=ERROR | count by service per 1m' app.log
+  logsum query 'where trace_id=abc123 | top 10 message' app.log
+  logsum query 'count by level' '/var/log/myapp/*.log'
+  logsum query 'where status>=500 | top 5 path' --format nginx access.log
+  logsum query 'service=payments | fields timestamp, message | limit 20' app.log
+  logsum query --since 1h -o csv 'count by service, level' app.log`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runQuery,
+	}
+
+	addInputFlags(cmd)
+	cmd.Flags().StringVar(&analyzeOutputFile, "output-file", "", "save output to file instead of stdout")
+
+	return cmd
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	q, err := query.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	entryFilter, err := newEntryFilter()
+	if err != nil {
+		return err
+	}
+
+	source, cleanup, err := setupEntrySource(args[1:])
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	source = filterSource(source, entryFilter)
+
+	executor := query.NewExecutor(q)
+	for !executor.Done() {
+		entry, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		executor.Add(entry)
+	}
+	reportReaderStats(source)
+
+	result := executor.Result()
+	output, err := formatter.FormatTable(getOutputFormat(), result.Columns, result.Rows)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Query returned %d rows\n", len(result.Rows))
+	}
+	return handleOutputDestination(output)
+}
@@ -80,10 +80,12 @@ from files or stdin with real-time monitoring capabilities.`,
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
 	rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "disable emoji output (useful for Windows terminals)")
-	rootCmd.PersistentFlags().StringVarP(&outputFmt, "output", "o", "text", "output format (text, json, markdown)")
+	rootCmd.PersistentFlags().StringVarP(&outputFmt, "output", "o", "text", "output format (text, json, markdown, csv)")
 
 	// Add subcommands
 	rootCmd.AddCommand(newAnalyzeCommand())
+	rootCmd.AddCommand(newQueryCommand())
+	rootCmd.AddCommand(newDiffCommand())
 	rootCmd.AddCommand(newPatternsCommand())
 	rootCmd.AddCommand(newWatchCommand())
 	rootCmd.AddCommand(newConfigCommand())
@@ -1,130 +1,306 @@
 package cli
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
 	"syscall"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
+	"github.com/yildizm/LogSum/internal/analyzer"
 	"github.com/yildizm/LogSum/internal/common"
-	"github.com/yildizm/go-logparser"
 )
 
+// watchFlushInterval is how long new entries are buffered so that entries
+// from different files can be printed in timestamp order
+const watchFlushInterval = 250 * time.Millisecond
+
 var (
-	watchPatterns string
+	watchPatterns     string
+	watchDrainRotated bool
 )
 
-// parserState encapsulates the parser with thread-safe access
-type parserState struct {
-	mu     sync.RWMutex
-	parser logparser.Parser
+// watchSession is the live state of a watch run: the followed files and the
+// rolling pattern analysis shared between them
+type watchSession struct {
+	specs    []watchSpec
+	files    map[string]*watchedFile
+	multi    bool               // prefix output with the source file
+	pending  []*common.LogEntry // parsed entries not yet printed
+	stream   *analyzer.StreamAnalyzer
+	patterns map[string]*common.Pattern
+	counts   map[string]int
+	reported map[string]bool // insights already printed
 }
 
-// getParser safely returns the current parser
-func (ps *parserState) getParser() logparser.Parser {
-	ps.mu.RLock()
-	defer ps.mu.RUnlock()
-	return ps.parser
-}
+// newWatchSession creates a session that follows the files matching specs
+// and matches new entries against patterns
+func newWatchSession(specs []watchSpec, patterns []*common.Pattern) *watchSession {
+	patternsByID := make(map[string]*common.Pattern, len(patterns))
+	for _, pattern := range patterns {
+		patternsByID[pattern.ID] = pattern
+	}
 
-// setParser safely updates the parser
-func (ps *parserState) setParser(parser logparser.Parser) {
-	ps.mu.Lock()
-	defer ps.mu.Unlock()
-	ps.parser = parser
+	multi := len(specs) > 1
+	for _, spec := range specs {
+		multi = multi || spec.kind != watchSpecFile
+	}
+
+	return &watchSession{
+		specs:    specs,
+		files:    make(map[string]*watchedFile),
+		multi:    multi,
+		stream:   newAnalysisEngine(patterns).NewStream(analyzer.DefaultStreamOptions()),
+		patterns: patternsByID,
+		counts:   make(map[string]int),
+		reported: make(map[string]bool),
+	}
 }
 
 func newWatchCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "watch [file]",
+		Use:   "watch <file|dir|glob>...",
 		Short: "Watch log files for real-time analysis",
 		Long: `Monitor log files for changes and analyze new entries in real-time.
 
 Uses file system notifications to detect changes and processes new log lines
-as they are written to the file. Press Ctrl+C to stop watching.
+as they are written to the file. Every new entry is matched against the loaded
+patterns; matches are printed with their line number and a running count per
+pattern, and new insights are reported as they appear. Press Ctrl+C to stop
+watching and print the pattern totals.
+
+Several files, directories and globs can be watched at once. Files that appear
+later in a watched directory or matching a glob are picked up and read from
+the start. Entries from different files are printed in timestamp order,
+prefixed with the file they came from.
+
+Log rotation is handled: when the file is renamed, removed or truncated, the
+remaining lines are read and watching resumes on the new file from its start.
+With --drain-rotated, lines written just before a copy-and-truncate rotation
+are read from the rotated copy (app.log.1) so none are lost.
 
 Examples:
   logsum watch app.log
+  logsum watch api.log worker.log
+  logsum watch '/var/log/myapp/*.log'
+  logsum watch /var/log/myapp/
+  logsum watch --drain-rotated /var/log/app.log
   logsum watch --patterns ./patterns/ access.log`,
-		Args: cobra.ExactArgs(1),
+		Args: cobra.MinimumNArgs(1),
 		RunE: runWatch,
 	}
 
 	cmd.Flags().StringVarP(&watchPatterns, "patterns", "p", "", "pattern file or directory")
+	cmd.Flags().BoolVar(&watchDrainRotated, "drain-rotated", false, "read missed lines from the rotated file (e.g. app.log.1) after truncation")
 
 	return cmd
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
-	filename := args[0]
+	specs, err := resolveWatchSpecs(args)
+	if err != nil {
+		return err
+	}
 
-	// Setup file watcher
-	watcher, file, cleanup, err := setupFileWatcher(filename)
+	// Load patterns the same way analyze does
+	patterns := NewPatternLoader().LoadPatterns(watchPatterns)
+
+	// Setup file watcher before opening files so no change is missed
+	watcher, cleanup, err := setupFileWatcher(specs)
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
+	session := newWatchSession(specs, patterns)
+	defer session.close()
+	if err := session.start(); err != nil {
+		return err
+	}
+
 	// Run watch loop
-	return runWatchLoop(watcher, file)
+	err = runWatchLoop(watcher, session)
+	for _, wf := range session.files {
+		wf.flushHeld()
+	}
+	session.flush()
+	session.printSummary()
+	return err
 }
 
-func processNewLines(file *os.File, detectedParser logparser.Parser) (logparser.Parser, error) {
-	scanner := bufio.NewScanner(file)
-
-	var newLines []string
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line != "" {
-			newLines = append(newLines, line)
+// start opens every file currently matching the specs, positioned at its end
+func (ws *watchSession) start() error {
+	for _, spec := range ws.specs {
+		paths, err := spec.files()
+		if err != nil {
+			return err
 		}
+		for _, path := range paths {
+			if err := ws.addFile(path, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Watching %d files\n", len(ws.files))
+		fmt.Fprintf(os.Stderr, "Press Ctrl+C to stop...\n\n")
+	}
+	return nil
+}
+
+// addFile starts following path. Files present when watch starts are read
+// from their end; files that appear later are read from the start.
+func (ws *watchSession) addFile(path string, skipExisting bool) error {
+	if _, exists := ws.files[path]; exists {
+		return nil
 	}
 
-	if err := scanner.Err(); err != nil {
-		return detectedParser, fmt.Errorf("scanner error: %w", err)
+	file, err := openWatchFile(path)
+	if err != nil {
+		return err
 	}
 
-	if len(newLines) == 0 {
-		return detectedParser, nil
+	wf := newWatchedFile(path, file, ws.queue)
+	ws.files[path] = wf
+
+	if skipExisting {
+		return wf.skipExisting()
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Started watching new file %s\n", path)
 	}
+	return wf.processNewLines()
+}
 
-	// Auto-detect parser on first lines if not already detected
-	if detectedParser == nil {
-		detectedParser = logparser.New()
-		if isVerbose() {
-			fmt.Fprintf(os.Stderr, "Created auto-detecting parser\n")
+// matches reports whether path belongs to any watch spec
+func (ws *watchSession) matches(path string) bool {
+	for _, spec := range ws.specs {
+		if spec.matches(path) {
+			return true
 		}
 	}
+	return false
+}
 
-	// Process new lines in batch
-	if len(newLines) > 0 {
-		entries, err := detectedParser.ParseString(strings.Join(newLines, "\n"))
-		if err != nil {
-			if isVerbose() {
-				fmt.Fprintf(os.Stderr, "Failed to parse lines: %v\n", err)
-			}
-			return detectedParser, nil
+// queue buffers parsed entries until the next flush
+func (ws *watchSession) queue(entries []*common.LogEntry) {
+	ws.pending = append(ws.pending, entries...)
+}
+
+// flushIdle queues the records held back in files that have been idle
+// for the flush interval
+func (ws *watchSession) flushIdle(now time.Time) {
+	for _, wf := range ws.files {
+		wf.flushIdle(now)
+	}
+}
+
+// flush analyzes and prints the buffered entries in timestamp order
+func (ws *watchSession) flush() {
+	if len(ws.pending) == 0 {
+		return
+	}
+
+	sort.SliceStable(ws.pending, func(i, j int) bool {
+		return ws.pending[i].Timestamp.Before(ws.pending[j].Timestamp)
+	})
+	for _, entry := range ws.pending {
+		ws.processEntry(entry)
+	}
+	ws.pending = ws.pending[:0]
+
+	ws.printNewInsights()
+}
+
+// processEntry matches a single entry and prints it when a pattern fired
+// or its level is WARN or above
+func (ws *watchSession) processEntry(entry *common.LogEntry) {
+	matchedIDs := ws.stream.Add(entry)
+	if len(matchedIDs) == 0 && entry.LogLevel < common.LevelWarn {
+		return
+	}
+
+	prefix := fmt.Sprintf("[%s]", entry.Timestamp.Format("15:04:05"))
+	if ws.multi {
+		prefix += fmt.Sprintf(" [%s]", filepath.Base(entry.Source))
+	}
+
+	if len(matchedIDs) == 0 {
+		fmt.Printf("%s %s line %d: %s\n", prefix, entry.LogLevel.String(), entry.LineNumber, entry.Message)
+		return
+	}
+
+	fired := make([]string, len(matchedIDs))
+	for i, id := range matchedIDs {
+		ws.counts[id]++
+		fired[i] = fmt.Sprintf("%s #%d", id, ws.counts[id])
+	}
+	fmt.Printf("%s %s line %d [%s]: %s\n", prefix, entry.LogLevel.String(), entry.LineNumber,
+		strings.Join(fired, ", "), entry.Message)
+}
+
+// printNewInsights prints insights that were not reported before
+func (ws *watchSession) printNewInsights() {
+	for _, insight := range ws.stream.Analysis().Insights {
+		key := insightKey(insight)
+		if ws.reported[key] {
+			continue
 		}
+		ws.reported[key] = true
+		fmt.Printf("[insight] %s: %s\n", insight.Title, insight.Description)
+	}
+}
 
-		// Simple real-time output - just show important entries
-		for i, entry := range entries {
-			commonEntry := common.ConvertToCommonLogEntry(&entry, i)
-			if commonEntry.LogLevel >= common.LevelWarn {
-				timestamp := entry.Timestamp.Format("15:04:05")
-				fmt.Printf("[%s] %s: %s\n", timestamp, commonEntry.LogLevel.String(), entry.Message)
-			}
+// insightKey identifies an insight across refreshes by its title and the
+// first line of its evidence, so growing counts do not repeat it
+func insightKey(insight analyzer.Insight) string {
+	if len(insight.Evidence) == 0 {
+		return insight.Title
+	}
+	first := insight.Evidence[0]
+	return fmt.Sprintf("%s:%s:%d", insight.Title, first.Source, first.LineNumber)
+}
+
+// printSummary prints the rolling per-pattern counters when watch stops
+func (ws *watchSession) printSummary() {
+	if len(ws.counts) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(ws.counts))
+	for id := range ws.counts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ws.counts[ids[i]] != ws.counts[ids[j]] {
+			return ws.counts[ids[i]] > ws.counts[ids[j]]
 		}
+		return ids[i] < ids[j]
+	})
+
+	fmt.Printf("\nPattern matches:\n")
+	for _, id := range ids {
+		name := id
+		if pattern := ws.patterns[id]; pattern != nil && pattern.Name != "" {
+			name = pattern.Name
+		}
+		fmt.Printf("  %-40s %d\n", name, ws.counts[id])
 	}
+}
 
-	return detectedParser, nil
+// close closes every followed file
+func (ws *watchSession) close() {
+	for _, wf := range ws.files {
+		wf.close()
+	}
 }
 
 // cleanupWatcher safely closes watcher with error logging
@@ -141,77 +317,50 @@ func cleanupFile(file *os.File) {
 	}
 }
 
-// createWatcher creates and configures a new file system watcher
-func createWatcher(filename string) (*fsnotify.Watcher, error) {
+// createWatcher creates a file system watcher on the given directories.
+// Directories are watched rather than files so that files being renamed,
+// removed or created are seen.
+func createWatcher(dirs []string) (*fsnotify.Watcher, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create watcher: %w", err)
 	}
 
-	if err := watcher.Add(filename); err != nil {
-		cleanupWatcher(watcher)
-		return nil, fmt.Errorf("failed to watch file: %w", err)
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			cleanupWatcher(watcher)
+			return nil, fmt.Errorf("failed to watch directory %s: %w", dir, err)
+		}
 	}
 
 	return watcher, nil
 }
 
-// openWatchFile opens and prepares file for watching
-func openWatchFile(filename string) (*os.File, error) {
-	// #nosec G304 - path is validated by caller
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-
-	if _, err := file.Seek(0, io.SeekEnd); err != nil {
-		cleanupFile(file)
-		return nil, fmt.Errorf("failed to seek to end of file: %w", err)
-	}
-
-	return file, nil
-}
-
-// setupFileWatcher creates and configures file watcher
-func setupFileWatcher(filename string) (*fsnotify.Watcher, *os.File, func(), error) {
-	// Check if file exists
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return nil, nil, nil, fmt.Errorf("file does not exist: %s", filename)
-	}
-
-	if isVerbose() {
-		fmt.Fprintf(os.Stderr, "Watching file: %s\n", filename)
-		fmt.Fprintf(os.Stderr, "Press Ctrl+C to stop...\n\n")
-	}
-
-	// Validate file path for security
-	if err := validateWatchFilePath(filename); err != nil {
-		return nil, nil, nil, fmt.Errorf("invalid file path: %w", err)
-	}
-
-	// Create watcher
-	watcher, err := createWatcher(filename)
-	if err != nil {
-		return nil, nil, nil, err
+// setupFileWatcher creates a watcher covering the directories of all specs
+func setupFileWatcher(specs []watchSpec) (*fsnotify.Watcher, func(), error) {
+	seen := make(map[string]bool, len(specs))
+	var dirs []string
+	for _, spec := range specs {
+		if !seen[spec.dir] {
+			seen[spec.dir] = true
+			dirs = append(dirs, spec.dir)
+		}
 	}
 
-	// Open file
-	file, err := openWatchFile(filename)
+	watcher, err := createWatcher(dirs)
 	if err != nil {
-		cleanupWatcher(watcher)
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
 	cleanup := func() {
 		cleanupWatcher(watcher)
-		cleanupFile(file)
 	}
 
-	return watcher, file, cleanup, nil
+	return watcher, cleanup, nil
 }
 
 // runWatchLoop runs the main watch loop with signal handling
-func runWatchLoop(watcher *fsnotify.Watcher, file *os.File) error {
+func runWatchLoop(watcher *fsnotify.Watcher, session *watchSession) error {
 	// Set up signal handling for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -219,8 +368,8 @@ func runWatchLoop(watcher *fsnotify.Watcher, file *os.File) error {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
-	// Create thread-safe parser state for auto-detection
-	parserSt := &parserState{}
+	ticker := time.NewTicker(watchFlushInterval)
+	defer ticker.Stop()
 
 	// Watch loop
 	for {
@@ -234,18 +383,16 @@ func runWatchLoop(watcher *fsnotify.Watcher, file *os.File) error {
 			}
 			return nil
 
+		case now := <-ticker.C:
+			session.flushIdle(now)
+			session.flush()
+
 		case event, ok := <-watcher.Events:
 			if !ok {
 				return fmt.Errorf("watcher events channel closed")
 			}
-			currentParser := parserSt.getParser()
-			updatedParser, err := handleWatchEvent(event, file, currentParser)
-			if err != nil {
-				if isVerbose() {
-					fmt.Fprintf(os.Stderr, "Error handling event: %v\n", err)
-				}
-			} else {
-				parserSt.setParser(updatedParser)
+			if err := handleWatchEvent(event, session); err != nil && isVerbose() {
+				fmt.Fprintf(os.Stderr, "Error handling event: %v\n", err)
 			}
 
 		case err, ok := <-watcher.Errors:
@@ -259,42 +406,34 @@ func runWatchLoop(watcher *fsnotify.Watcher, file *os.File) error {
 	}
 }
 
-// handleWatchEvent processes file system events
-func handleWatchEvent(event fsnotify.Event, file *os.File, detectedParser logparser.Parser) (logparser.Parser, error) {
-	// Only process write events
-	if event.Op&fsnotify.Write == fsnotify.Write {
-		updatedParser, err := processNewLines(file, detectedParser)
-		if err != nil {
-			return detectedParser, fmt.Errorf("error processing new lines: %w", err)
-		}
-		return updatedParser, nil
-	}
-	return detectedParser, nil
-}
-
-// validateWatchFilePath validates that a file path is safe to watch
-func validateWatchFilePath(path string) error {
-	// Check for empty path
-	if strings.TrimSpace(path) == "" {
-		return fmt.Errorf("empty file path")
-	}
+// handleWatchEvent processes file system events for watched files
+func handleWatchEvent(event fsnotify.Event, session *watchSession) error {
+	path := filepath.Clean(event.Name)
+	wf, tracked := session.files[path]
 
-	// Clean the path to resolve . and .. elements
-	cleanPath := filepath.Clean(path)
-
-	// Check for path traversal attempts
-	if strings.Contains(cleanPath, "..") {
-		return fmt.Errorf("path traversal not allowed")
+	// Whole directories are watched, so ignore events for unrelated files
+	if !tracked {
+		if event.Has(fsnotify.Create) && session.matches(path) {
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				return session.addFile(path, false)
+			}
+		}
+		return nil
 	}
 
-	// For watch operations, ensure the file exists and is a regular file
-	info, err := os.Stat(cleanPath)
-	if err != nil {
-		return fmt.Errorf("cannot access file: %w", err)
-	}
-	if info.IsDir() {
-		return fmt.Errorf("cannot watch directory, must be a file")
+	switch {
+	case event.Has(fsnotify.Create):
+		if err := wf.reopen(); err != nil {
+			return fmt.Errorf("error reopening file: %w", err)
+		}
+	case event.Has(fsnotify.Rename), event.Has(fsnotify.Remove):
+		if err := wf.detach(); err != nil {
+			return fmt.Errorf("error draining rotated file: %w", err)
+		}
+	case event.Has(fsnotify.Write):
+		if err := wf.processNewLines(); err != nil {
+			return fmt.Errorf("error processing new lines: %w", err)
+		}
 	}
-
 	return nil
 }
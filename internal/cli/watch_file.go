@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/ingest"
+	"github.com/yildizm/go-logparser"
+)
+
+// truncationCheckBytes is how many of the last consumed bytes are kept to
+// notice a file that was truncated and then grew past the consumed offset
+const truncationCheckBytes = 64
+
+// watchedFile follows a single log file, tracking how far it has been read
+// and the format detected on its first batch
+type watchedFile struct {
+	path    string
+	file    *os.File // nil while the path is rotated away
+	offset  int64    // bytes of the current file consumed so far
+	tail    []byte   // last bytes consumed, ending at offset
+	format  string
+	lineNum int    // number of complete lines consumed
+	partial string // trailing data of a line still being written
+
+	// feeder parses the lines of the current file, holding back a record
+	// that the next write may continue, such as a stack trace
+	feeder   *ingest.Feeder
+	skipped  int       // unparseable lines of the current file already reported
+	lastData time.Time // when lines were last read
+
+	// emit receives the entries parsed from the file
+	emit func(entries []*common.LogEntry)
+}
+
+// newWatchedFile creates a follower for path that hands parsed entries to emit
+func newWatchedFile(path string, file *os.File, emit func(entries []*common.LogEntry)) *watchedFile {
+	return &watchedFile{
+		path:   path,
+		file:   file,
+		format: logparser.FormatAuto.String(),
+		emit:   emit,
+	}
+}
+
+// openWatchFile opens the file for watching
+func openWatchFile(filename string) (*os.File, error) {
+	// #nosec G304 - path is validated by caller
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return file, nil
+}
+
+// skipExisting moves past the current content of the file, counting its
+// lines so that new entries are reported with their real line numbers
+func (wf *watchedFile) skipExisting() error {
+	reader := bufio.NewReader(wf.file)
+	for {
+		line, err := reader.ReadString('\n')
+		if err == nil {
+			wf.lineNum++
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			wf.partial = line
+			break
+		}
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	offset, err := wf.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to get file offset: %w", err)
+	}
+	wf.offset = offset
+
+	tail := make([]byte, min(offset, truncationCheckBytes))
+	if _, err := wf.file.ReadAt(tail, offset-int64(len(tail))); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	wf.tail = tail
+	return nil
+}
+
+// processNewLines parses the lines appended since the last call. A file
+// that shrank below the consumed offset, or whose bytes before the offset
+// are no longer the ones consumed, is treated as truncated and read again
+// from the start.
+func (wf *watchedFile) processNewLines() error {
+	if wf.file == nil {
+		return nil
+	}
+
+	info, err := wf.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.Size() < wf.offset || !wf.tailUnchanged() {
+		if err := wf.handleTruncation(); err != nil {
+			return err
+		}
+	}
+
+	data, err := io.ReadAll(wf.file)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	wf.offset += int64(len(data))
+	wf.rememberTail(data)
+
+	return wf.processData(data, false)
+}
+
+// tailUnchanged reports whether the file still holds the last consumed
+// bytes just before the offset. A file truncated and rewritten past the
+// offset between two reads has other content there.
+func (wf *watchedFile) tailUnchanged() bool {
+	if len(wf.tail) == 0 {
+		return true
+	}
+
+	current := make([]byte, len(wf.tail))
+	if _, err := wf.file.ReadAt(current, wf.offset-int64(len(current))); err != nil {
+		return false
+	}
+	return bytes.Equal(current, wf.tail)
+}
+
+// rememberTail keeps the last bytes of data appended to those consumed
+func (wf *watchedFile) rememberTail(data []byte) {
+	tail := append(wf.tail, data...)
+	if len(tail) > truncationCheckBytes {
+		tail = tail[len(tail)-truncationCheckBytes:]
+	}
+	wf.tail = append([]byte(nil), tail...)
+}
+
+// processData parses the complete lines in data together with any partial
+// line left over from before. When final is set the source has ended, so
+// a trailing line without a newline and the record held back for the next
+// write are processed as well.
+func (wf *watchedFile) processData(data []byte, final bool) error {
+	chunk := wf.completeLines(data, final)
+	if chunk == "" && (!final || wf.feeder == nil) {
+		return nil
+	}
+
+	if wf.feeder == nil {
+		feeder, err := ingest.NewFeeder(ingest.Options{
+			Format:        wf.format,
+			MaxLineLength: GetGlobalConfig().Analysis.MaxLineLength,
+			LineOffset:    wf.lineNum,
+			Source:        wf.path,
+			Multiline:     multilineOptions(),
+		})
+		if err != nil {
+			return err
+		}
+		wf.feeder = feeder
+	}
+
+	entries, err := wf.feeder.Feed(chunk)
+	if err != nil {
+		return fmt.Errorf("failed to parse lines: %w", err)
+	}
+	wf.lastData = time.Now()
+	if final {
+		entries = append(entries, wf.feeder.Flush()...)
+	}
+	wf.emitEntries(entries)
+	return nil
+}
+
+// flushIdle processes the record held back for the next write once no
+// lines have been read for the flush interval, so the last entry written
+// is not delayed until another one follows
+func (wf *watchedFile) flushIdle(now time.Time) {
+	if now.Sub(wf.lastData) >= watchFlushInterval {
+		wf.flushHeld()
+	}
+}
+
+// flushHeld processes the record held back for the next write, if any
+func (wf *watchedFile) flushHeld() {
+	if wf.feeder != nil && wf.feeder.Holding() {
+		wf.emitEntries(wf.feeder.Flush())
+	}
+}
+
+// emitEntries hands parsed entries on and records the feeder's position
+func (wf *watchedFile) emitEntries(entries []*common.LogEntry) {
+	// Keep the detected format so later files are parsed consistently
+	wf.format = wf.feeder.FormatName()
+	wf.lineNum = wf.feeder.LineNumber()
+
+	if skipped := wf.feeder.Skipped() - wf.skipped; isVerbose() && skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d unparseable lines in %s\n", skipped, wf.path)
+	}
+	wf.skipped = wf.feeder.Skipped()
+
+	if len(entries) > 0 {
+		wf.emit(entries)
+	}
+}
+
+// completeLines returns the complete lines of the pending partial line
+// followed by data, holding back a trailing line that has no newline yet
+// unless final is set
+func (wf *watchedFile) completeLines(data []byte, final bool) string {
+	text := wf.partial + string(data)
+	if final {
+		wf.partial = ""
+		return text
+	}
+
+	cut := strings.LastIndexByte(text, '\n')
+	if cut < 0 {
+		wf.partial = text
+		return ""
+	}
+
+	wf.partial = text[cut+1:]
+	return text[:cut+1]
+}
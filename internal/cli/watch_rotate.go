@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/yildizm/LogSum/internal/ingest"
+)
+
+// rotatedSuffixes are the names logrotate gives the most recent rotated
+// copy, plain or compressed
+var rotatedSuffixes = []string{".1", ".1.gz", ".1.zst", ".1.bz2"}
+
+// rotatedNamePattern matches numbered rotated copies, optionally compressed
+var rotatedNamePattern = regexp.MustCompile(`\.\d+(\.(gz|bz2|xz|zst))?$`)
+
+// detach reads what is left in the current file after it was renamed or
+// removed, then closes it until the path is re-created
+func (wf *watchedFile) detach() error {
+	if wf.file == nil {
+		return nil
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "File %s was rotated, waiting for it to be re-created...\n", wf.path)
+	}
+
+	// The open handle still refers to the rotated file, so nothing written
+	// before the rotation is lost
+	err := wf.processNewLines()
+	if flushErr := wf.processData(nil, true); err == nil {
+		err = flushErr
+	}
+
+	cleanupFile(wf.file)
+	wf.file = nil
+	return err
+}
+
+// reopen starts following a newly created file at path from its beginning
+func (wf *watchedFile) reopen() error {
+	if wf.file != nil {
+		if err := wf.detach(); err != nil && isVerbose() {
+			fmt.Fprintf(os.Stderr, "Warning: failed to drain previous file: %v\n", err)
+		}
+	}
+
+	file, err := openWatchFile(wf.path)
+	if err != nil {
+		return err
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "File %s was re-created, resuming from the start\n", wf.path)
+	}
+
+	wf.file = file
+	wf.resetPosition()
+	return wf.processNewLines()
+}
+
+// handleTruncation recovers lines lost to a copy-and-truncate rotation and
+// rewinds to the start of the truncated file
+func (wf *watchedFile) handleTruncation() error {
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "File %s was truncated, resuming from the start\n", wf.path)
+	}
+
+	if watchDrainRotated {
+		if err := wf.drainRotated(); err != nil && isVerbose() {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read rotated file: %v\n", err)
+		}
+	}
+	if err := wf.processData(nil, true); err != nil {
+		return err
+	}
+
+	if _, err := wf.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to start of file: %w", err)
+	}
+	wf.resetPosition()
+	return nil
+}
+
+// drainRotated reads the lines of the rotated copy beyond the consumed
+// offset, i.e. those written after the last read but before the truncation
+func (wf *watchedFile) drainRotated() error {
+	rotatedPath, ok := findRotatedFile(wf.path)
+	if !ok {
+		return nil
+	}
+
+	rotated, err := openWatchFile(rotatedPath)
+	if err != nil {
+		return err
+	}
+	defer cleanupFile(rotated)
+
+	// The offset refers to the uncompressed content, so a compressed copy
+	// is decompressed and the consumed prefix skipped
+	content, _, err := ingest.Decompress(rotated)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if _, err := io.CopyN(io.Discard, content, wf.offset); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("failed to skip read data in rotated file: %w", err)
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return fmt.Errorf("failed to read rotated file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Read %d missed bytes from %s\n", len(data), rotatedPath)
+	}
+	return wf.processData(data, false)
+}
+
+// resetPosition starts line and offset tracking over for a new file
+func (wf *watchedFile) resetPosition() {
+	wf.offset = 0
+	wf.tail = nil
+	wf.lineNum = 0
+	wf.partial = ""
+	wf.feeder = nil
+	wf.skipped = 0
+}
+
+// close closes the currently followed file, if any
+func (wf *watchedFile) close() {
+	if wf.file != nil {
+		cleanupFile(wf.file)
+		wf.file = nil
+	}
+}
+
+// findRotatedFile returns the most recent rotated copy of path, if present
+func findRotatedFile(path string) (string, bool) {
+	for _, suffix := range rotatedSuffixes {
+		candidate := path + suffix
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// isRotatedName reports whether path looks like a rotated copy such as
+// app.log.1 or app.log.2.gz, which directory and glob watches skip so that
+// a rotation is not read twice
+func isRotatedName(path string) bool {
+	return rotatedNamePattern.MatchString(filepath.Base(path))
+}
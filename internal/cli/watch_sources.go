@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// watchSpecKind is what a watch argument refers to
+type watchSpecKind int
+
+const (
+	watchSpecFile watchSpecKind = iota
+	watchSpecDir
+	watchSpecGlob
+)
+
+// watchSpec is one watch argument: a file, a directory or a glob
+type watchSpec struct {
+	kind    watchSpecKind
+	pattern string // cleaned file path, directory or glob
+	dir     string // directory watched for changes
+}
+
+// resolveWatchSpecs validates the watch arguments and classifies each one
+func resolveWatchSpecs(args []string) ([]watchSpec, error) {
+	specs := make([]watchSpec, 0, len(args))
+	for _, arg := range args {
+		spec, err := resolveWatchSpec(arg)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// resolveWatchSpec classifies a single watch argument
+func resolveWatchSpec(arg string) (watchSpec, error) {
+	if err := validateWatchPath(arg); err != nil {
+		return watchSpec{}, fmt.Errorf("invalid path %s: %w", arg, err)
+	}
+	cleanPath := filepath.Clean(arg)
+
+	if isGlobPattern(cleanPath) {
+		if _, err := filepath.Match(cleanPath, ""); err != nil {
+			return watchSpec{}, fmt.Errorf("invalid glob %s: %w", arg, err)
+		}
+		dir := filepath.Dir(cleanPath)
+		if isGlobPattern(dir) {
+			return watchSpec{}, fmt.Errorf("invalid glob %s: only the file name may contain wildcards", arg)
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return watchSpec{}, fmt.Errorf("directory does not exist: %s", dir)
+		}
+		return watchSpec{kind: watchSpecGlob, pattern: cleanPath, dir: dir}, nil
+	}
+
+	info, err := os.Stat(cleanPath)
+	if err != nil {
+		return watchSpec{}, fmt.Errorf("file does not exist: %s", arg)
+	}
+	if info.IsDir() {
+		return watchSpec{kind: watchSpecDir, pattern: cleanPath, dir: cleanPath}, nil
+	}
+	return watchSpec{kind: watchSpecFile, pattern: cleanPath, dir: filepath.Dir(cleanPath)}, nil
+}
+
+// matches reports whether a file at path belongs to this spec
+func (s watchSpec) matches(path string) bool {
+	switch s.kind {
+	case watchSpecFile:
+		return path == s.pattern
+	case watchSpecDir:
+		return filepath.Dir(path) == s.pattern && !isRotatedName(path)
+	case watchSpecGlob:
+		matched, err := filepath.Match(s.pattern, path)
+		return err == nil && matched && !isRotatedName(path)
+	default:
+		return false
+	}
+}
+
+// files lists the regular files currently matching the spec
+func (s watchSpec) files() ([]string, error) {
+	if s.kind == watchSpecFile {
+		return []string{s.pattern}, nil
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", s.dir, err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		path := filepath.Join(s.dir, entry.Name())
+		if entry.Type().IsRegular() && s.matches(path) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// isGlobPattern reports whether path contains glob wildcards
+func isGlobPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// validateWatchPath validates that a file, directory or glob is safe to watch
+func validateWatchPath(path string) error {
+	// Check for empty path
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("empty file path")
+	}
+
+	// Clean the path to resolve . and .. elements
+	cleanPath := filepath.Clean(path)
+
+	// Check for path traversal attempts
+	if strings.Contains(cleanPath, "..") {
+		return fmt.Errorf("path traversal not allowed")
+	}
+
+	return nil
+}
@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+var watchTestPatterns = []*common.Pattern{
+	{ID: "conn_refused", Name: "Connection Refused", Type: common.PatternTypeError, Keywords: []string{"connection refused"}},
+}
+
+// startWatchSession writes existing content to a new log file and starts
+// a session positioned at its end
+func startWatchSession(t *testing.T, existing string) (*watchSession, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeLogFile(t, path, existing, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+
+	return startWatchSessionFor(t, path), path
+}
+
+// startWatchSessionFor starts a session watching the given arguments
+func startWatchSessionFor(t *testing.T, args ...string) *watchSession {
+	t.Helper()
+	specs, err := resolveWatchSpecs(args)
+	if err != nil {
+		t.Fatalf("resolveWatchSpecs() failed: %v", err)
+	}
+
+	session := newWatchSession(specs, watchTestPatterns)
+	t.Cleanup(session.close)
+	if err := session.start(); err != nil {
+		t.Fatalf("start() failed: %v", err)
+	}
+	return session
+}
+
+func writeLogFile(t *testing.T, path, text string, flag int) {
+	t.Helper()
+	f, err := os.OpenFile(path, flag, 0o600)
+	if err != nil {
+		t.Fatalf("Failed to open log file: %v", err)
+	}
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatalf("Failed to write log file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Failed to close log file: %v", err)
+	}
+}
+
+// sendWatchEvent handles an event, then flushes as the ticker would once
+// the file has been idle for the flush interval
+func sendWatchEvent(t *testing.T, session *watchSession, name string, op fsnotify.Op) {
+	t.Helper()
+	if err := handleWatchEvent(fsnotify.Event{Name: name, Op: op}, session); err != nil {
+		t.Fatalf("handleWatchEvent(%s) failed: %v", op, err)
+	}
+	session.flushIdle(time.Now().Add(watchFlushInterval))
+	session.flush()
+}
+
+func TestWatchSessionProcessNewLines(t *testing.T) {
+	session, path := startWatchSession(t, "2024-01-01 10:00:00 [INFO] started\n\n2024-01-01 10:00:01 [INFO] ready\n")
+	wf := session.files[path]
+	if wf.lineNum != 3 {
+		t.Fatalf("Expected 3 existing lines, got %d", wf.lineNum)
+	}
+
+	// The second line is only half written
+	writeLogFile(t, path, "2024-01-01 10:00:02 [ERROR] connection refused\n2024-01-01 10:00:03 [ERROR] conn", os.O_APPEND|os.O_WRONLY)
+	sendWatchEvent(t, session, path, fsnotify.Write)
+	if session.counts["conn_refused"] != 1 {
+		t.Errorf("Expected 1 match after first batch, got %d", session.counts["conn_refused"])
+	}
+	if wf.lineNum != 4 {
+		t.Errorf("Expected line 4 after first batch, got %d", wf.lineNum)
+	}
+
+	writeLogFile(t, path, "ection refused\n", os.O_APPEND|os.O_WRONLY)
+	sendWatchEvent(t, session, path, fsnotify.Write)
+	if session.counts["conn_refused"] != 2 {
+		t.Errorf("Expected 2 matches after second batch, got %d", session.counts["conn_refused"])
+	}
+	if wf.lineNum != 5 {
+		t.Errorf("Expected line 5 after second batch, got %d", wf.lineNum)
+	}
+
+	analysis := session.stream.Analysis()
+	if len(analysis.Patterns) != 1 || analysis.Patterns[0].Matches[1].LineNumber != 5 {
+		t.Errorf("Expected second match on line 5, got %+v", analysis.Patterns)
+	}
+}
+
+func TestWatchSessionJoinsStackTraceAcrossWrites(t *testing.T) {
+	session, path := startWatchSession(t, "2024-01-01 10:00:00 [INFO] started\n")
+
+	writeLogFile(t, path, "2024-01-01 10:00:01 [ERROR] connection refused\n", os.O_APPEND|os.O_WRONLY)
+	if err := handleWatchEvent(fsnotify.Event{Name: path, Op: fsnotify.Write}, session); err != nil {
+		t.Fatalf("handleWatchEvent() failed: %v", err)
+	}
+	// Not idle yet: the next write may continue the entry
+	session.flushIdle(time.Now())
+	session.flush()
+	if session.counts["conn_refused"] != 0 {
+		t.Fatalf("Expected the entry to be held back, got %d matches", session.counts["conn_refused"])
+	}
+
+	writeLogFile(t, path, "    at db.connect (db.js:42)\n", os.O_APPEND|os.O_WRONLY)
+	sendWatchEvent(t, session, path, fsnotify.Write)
+
+	analysis := session.stream.Analysis()
+	if session.counts["conn_refused"] != 1 || analysis.TotalEntries != 1 {
+		t.Fatalf("Expected 1 entry with 1 match, got %d entries and %d matches", analysis.TotalEntries, session.counts["conn_refused"])
+	}
+	if raw := analysis.Patterns[0].Matches[0].Raw; !strings.Contains(raw, "db.js:42") {
+		t.Errorf("Expected the stack frame to join the entry, got %q", raw)
+	}
+}
+
+func TestWatchSessionRenameAndCreate(t *testing.T) {
+	session, path := startWatchSession(t, "2024-01-01 10:00:00 [INFO] started\n")
+
+	// Lines written just before the rename are read from the old handle
+	writeLogFile(t, path, "2024-01-01 10:00:01 [ERROR] connection refused\n", os.O_APPEND|os.O_WRONLY)
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Failed to rotate log file: %v", err)
+	}
+	sendWatchEvent(t, session, path, fsnotify.Rename)
+	if session.files[path].file != nil {
+		t.Fatal("Expected file to be closed after rename")
+	}
+
+	writeLogFile(t, path, "2024-01-01 10:00:02 [ERROR] connection refused\n", os.O_CREATE|os.O_WRONLY)
+	sendWatchEvent(t, session, path, fsnotify.Create)
+
+	if session.counts["conn_refused"] != 2 {
+		t.Errorf("Expected 2 matches across rotation, got %d", session.counts["conn_refused"])
+	}
+	if session.files[path].lineNum != 1 {
+		t.Errorf("Expected line numbers to restart in new file, got %d", session.files[path].lineNum)
+	}
+}
+
+func TestWatchSessionTruncationDrainsRotatedCopy(t *testing.T) {
+	oldDrain := watchDrainRotated
+	watchDrainRotated = true
+	defer func() { watchDrainRotated = oldDrain }()
+
+	existing := "2024-01-01 10:00:00 [INFO] started\n"
+	session, path := startWatchSession(t, existing)
+
+	// copytruncate: the copy holds a line watch has not read yet
+	missed := "2024-01-01 10:00:01 [ERROR] connection refused\n"
+	writeLogFile(t, path+".1", existing+missed, os.O_CREATE|os.O_WRONLY)
+	writeLogFile(t, path, "", os.O_TRUNC|os.O_WRONLY)
+	sendWatchEvent(t, session, path, fsnotify.Write)
+
+	writeLogFile(t, path, "2024-01-01 10:00:02 [ERROR] connection refused\n", os.O_APPEND|os.O_WRONLY)
+	sendWatchEvent(t, session, path, fsnotify.Write)
+
+	if session.counts["conn_refused"] != 2 {
+		t.Errorf("Expected 2 matches including the drained line, got %d", session.counts["conn_refused"])
+	}
+	if session.files[path].offset != int64(len("2024-01-01 10:00:02 [ERROR] connection refused\n")) {
+		t.Errorf("Expected offset to restart after truncation, got %d", session.files[path].offset)
+	}
+}
+
+func TestWatchSessionTruncationGrownPastOffset(t *testing.T) {
+	session, path := startWatchSession(t, "2024-01-01 10:00:00 [INFO] started\n")
+
+	// Truncated and rewritten past the consumed offset before the next read
+	rewritten := "2024-01-01 11:00:00 [ERROR] connection refused\n" +
+		"2024-01-01 11:00:01 [ERROR] connection refused\n"
+	writeLogFile(t, path, rewritten, os.O_TRUNC|os.O_WRONLY)
+	sendWatchEvent(t, session, path, fsnotify.Write)
+
+	if session.counts["conn_refused"] != 2 {
+		t.Errorf("Expected both rewritten lines to match, got %d", session.counts["conn_refused"])
+	}
+	if session.files[path].offset != int64(len(rewritten)) {
+		t.Errorf("Expected offset to restart after truncation, got %d", session.files[path].offset)
+	}
+}
+
+func TestWatchSessionTruncationDrainsCompressedCopy(t *testing.T) {
+	oldDrain := watchDrainRotated
+	watchDrainRotated = true
+	defer func() { watchDrainRotated = oldDrain }()
+
+	existing := "2024-01-01 10:00:00 [INFO] started\n"
+	session, path := startWatchSession(t, existing)
+
+	// copytruncate with compress: the copy is gzipped straight away
+	rotated, err := os.Create(path + ".1.gz")
+	if err != nil {
+		t.Fatalf("Failed to create rotated file: %v", err)
+	}
+	writer := gzip.NewWriter(rotated)
+	if _, err := writer.Write([]byte(existing + "2024-01-01 10:00:01 [ERROR] connection refused\n")); err != nil {
+		t.Fatalf("Failed to write rotated file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close gzip writer: %v", err)
+	}
+	if err := rotated.Close(); err != nil {
+		t.Fatalf("Failed to close rotated file: %v", err)
+	}
+
+	writeLogFile(t, path, "", os.O_TRUNC|os.O_WRONLY)
+	sendWatchEvent(t, session, path, fsnotify.Write)
+
+	if session.counts["conn_refused"] != 1 {
+		t.Errorf("Expected the missed line to be drained from the compressed copy, got %d matches", session.counts["conn_refused"])
+	}
+}
+
+func TestHandleWatchEventIgnoresOtherFiles(t *testing.T) {
+	session, path := startWatchSession(t, "")
+
+	writeLogFile(t, path, "2024-01-01 10:00:01 [ERROR] connection refused\n", os.O_APPEND|os.O_WRONLY)
+	sendWatchEvent(t, session, filepath.Join(filepath.Dir(path), "other.log"), fsnotify.Write)
+
+	if session.counts["conn_refused"] != 0 {
+		t.Errorf("Expected events for other files to be ignored, got %d matches", session.counts["conn_refused"])
+	}
+}
+
+func TestWatchSessionDirectoryPicksUpNewFiles(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.log")
+	writeLogFile(t, api, "2024-01-01 10:00:00 [INFO] started\n", os.O_CREATE|os.O_WRONLY)
+	writeLogFile(t, filepath.Join(dir, "api.log.1"), "2024-01-01 09:00:00 [ERROR] connection refused\n", os.O_CREATE|os.O_WRONLY)
+
+	session := startWatchSessionFor(t, dir)
+	if len(session.files) != 1 {
+		t.Fatalf("Expected only api.log to be watched, got %d files", len(session.files))
+	}
+
+	worker := filepath.Join(dir, "worker.log")
+	writeLogFile(t, worker, "2024-01-01 10:00:05 [ERROR] connection refused\n", os.O_CREATE|os.O_WRONLY)
+	sendWatchEvent(t, session, worker, fsnotify.Create)
+
+	if _, ok := session.files[worker]; !ok {
+		t.Fatal("Expected new file in directory to be watched")
+	}
+	if session.counts["conn_refused"] != 1 {
+		t.Errorf("Expected new file to be read from the start, got %d matches", session.counts["conn_refused"])
+	}
+
+	matches := session.stream.Analysis().Patterns[0].Matches
+	if matches[0].Source != worker {
+		t.Errorf("Expected entry source %s, got %s", worker, matches[0].Source)
+	}
+}
+
+func TestWatchSessionInterleavesByTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.log")
+	worker := filepath.Join(dir, "worker.log")
+	writeLogFile(t, api, "", os.O_CREATE|os.O_WRONLY)
+	writeLogFile(t, worker, "", os.O_CREATE|os.O_WRONLY)
+
+	session := startWatchSessionFor(t, filepath.Join(dir, "*.log"))
+	if !session.multi {
+		t.Error("Expected glob watch to prefix output with the source")
+	}
+
+	writeLogFile(t, api, "2024-01-01 10:00:03 [ERROR] connection refused\n", os.O_APPEND|os.O_WRONLY)
+	writeLogFile(t, worker, "2024-01-01 10:00:01 [ERROR] connection refused\n", os.O_APPEND|os.O_WRONLY)
+	for _, path := range []string{api, worker} {
+		if err := handleWatchEvent(fsnotify.Event{Name: path, Op: fsnotify.Write}, session); err != nil {
+			t.Fatalf("handleWatchEvent() failed: %v", err)
+		}
+	}
+	session.flushIdle(time.Now().Add(watchFlushInterval))
+	session.flush()
+
+	matches := session.stream.Analysis().Patterns[0].Matches
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+	if matches[0].Source != worker || matches[1].Source != api {
+		t.Errorf("Expected worker entry before api entry, got %s then %s", matches[0].Source, matches[1].Source)
+	}
+}
+
+func TestResolveWatchSpecs(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.log")
+	writeLogFile(t, file, "", os.O_CREATE|os.O_WRONLY)
+
+	tests := []struct {
+		name    string
+		arg     string
+		kind    watchSpecKind
+		wantErr bool
+	}{
+		{"file", file, watchSpecFile, false},
+		{"directory", dir, watchSpecDir, false},
+		{"glob", filepath.Join(dir, "*.log"), watchSpecGlob, false},
+		{"missing file", filepath.Join(dir, "missing.log"), watchSpecFile, true},
+		{"glob in directory", filepath.Join(dir, "*", "app.log"), watchSpecGlob, true},
+		{"path traversal", "../app.log", watchSpecFile, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := resolveWatchSpecs([]string{tt.arg})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %s", tt.arg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveWatchSpecs() failed: %v", err)
+			}
+			if specs[0].kind != tt.kind {
+				t.Errorf("Expected kind %d, got %d", tt.kind, specs[0].kind)
+			}
+		})
+	}
+}
@@ -14,27 +14,93 @@ type Analysis struct {
 	Patterns     []PatternMatch         `json:"patterns"`
 	Insights     []Insight              `json:"insights"`
 	Timeline     *Timeline              `json:"timeline,omitempty"`
+	Sources      []SourceSummary        `json:"sources,omitempty"`     // Per-source breakdown when several inputs are analyzed
+	Templates    []LogTemplate          `json:"templates,omitempty"`   // Message templates mined from entries no pattern matched
+	Suppressed   []SuppressedMatches    `json:"suppressed,omitempty"`  // Pattern matches silenced by suppression rules
+	Profile      *AnalysisProfile       `json:"profile,omitempty"`     // Counts to compare later analyses against
+	Baseline     *BaselineDiff          `json:"baseline,omitempty"`    // Differences from a baseline analysis
 	Context      map[string]interface{} `json:"context,omitempty"`     // For storing additional analysis context (e.g., AI results)
 	RawEntries   []*LogEntry            `json:"raw_entries,omitempty"` // Store raw entries for correlation
 }
 
 // PatternMatch represents a matched pattern in logs
 type PatternMatch struct {
-	Pattern   *Pattern    `json:"pattern"`
-	Matches   []*LogEntry `json:"matches"`
+	Pattern   *Pattern                  `json:"pattern"`
+	Matches   []*LogEntry               `json:"matches"`
+	Count     int                       `json:"count"`
+	FirstSeen time.Time                 `json:"first_seen"`
+	LastSeen  time.Time                 `json:"last_seen"`
+	Windows   []BurstWindow             `json:"windows,omitempty"`   // Bursts that crossed a threshold pattern's threshold
+	Sequences []SequenceOccurrence      `json:"sequences,omitempty"` // Occurrences of a sequence pattern
+	Captures  map[string]map[string]int `json:"captures,omitempty"`  // Value counts per named regex capture
+}
+
+// SuppressedMatches counts the matches of one pattern that a suppression
+// rule, or the pattern's exclude regexes, silenced
+type SuppressedMatches struct {
+	Rule    string `json:"rule"` // rule ID, or "exclude"
+	Pattern string `json:"pattern"`
+	Count   int    `json:"count"`
+}
+
+// SequenceOccurrence is one occurrence of a sequence pattern, with the
+// entries that matched its steps in order
+type SequenceOccurrence struct {
+	Key     string      `json:"key,omitempty"` // join_by value, if the pattern joins
+	Entries []*LogEntry `json:"entries"`
+	Missing bool        `json:"missing,omitempty"` // the last step did not follow within max_gap
+}
+
+// BurstWindow is a span in which a threshold pattern matched at least its
+// threshold count within its window
+type BurstWindow struct {
+	Group string    `json:"group,omitempty"` // group_by value, if the pattern groups
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Count int       `json:"count"`
+}
+
+// LogTemplate is a message shape shared by many entries, with the tokens
+// that vary between them masked as <*>
+type LogTemplate struct {
+	ID        string      `json:"id"`
+	Template  string      `json:"template"`
 	Count     int         `json:"count"`
+	Level     LogLevel    `json:"level"` // highest level seen
 	FirstSeen time.Time   `json:"first_seen"`
 	LastSeen  time.Time   `json:"last_seen"`
+	Examples  []*LogEntry `json:"examples,omitempty"`
+}
+
+// SourceSummary breaks down analysis results for one input source
+type SourceSummary struct {
+	Source        string         `json:"source"`
+	TotalEntries  int            `json:"total_entries"`
+	ErrorCount    int            `json:"error_count"`
+	WarnCount     int            `json:"warn_count"`
+	PatternCounts map[string]int `json:"pattern_counts,omitempty"` // matches by pattern ID
 }
 
 // Insight represents an analysis insight
 type Insight struct {
-	Type        InsightType `json:"type"`
-	Severity    LogLevel    `json:"severity"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Evidence    []*LogEntry `json:"evidence"`
-	Confidence  float64     `json:"confidence"`
+	Type        InsightType    `json:"type"`
+	Severity    LogLevel       `json:"severity"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Evidence    []*LogEntry    `json:"evidence"`
+	Confidence  float64        `json:"confidence"`
+	Anomaly     *AnomalyDetail `json:"anomaly,omitempty"` // set by the statistical anomaly detector
+}
+
+// AnomalyDetail describes a window where a series left its baseline
+type AnomalyDetail struct {
+	Series    string    `json:"series"` // "entries", "errors" or "pattern:<id>"
+	Method    string    `json:"method"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Value     float64   `json:"value"`     // count in the most deviant bucket
+	Baseline  float64   `json:"baseline"`  // expected count for that bucket
+	Deviation float64   `json:"deviation"` // signed distance from the baseline, in spreads
 }
 
 // InsightType categorizes insights
@@ -0,0 +1,72 @@
+package common
+
+import "time"
+
+// AnalysisProfile condenses an analysis into the counts a later analysis
+// is compared against. It is small enough to save with the analysis.
+type AnalysisProfile struct {
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+	TotalEntries int       `json:"total_entries"`
+	ErrorCount   int       `json:"error_count"`
+
+	Patterns map[string]PatternProfile `json:"patterns"` // by pattern ID
+	// ErrorSignatures and Services are nil when unknown, as for analyses
+	// saved without a profile
+	ErrorSignatures map[string]SignatureProfile `json:"error_signatures"` // by error signature
+	Services        map[string]ServiceProfile   `json:"services"`         // by service name
+	// SignaturesCapped is set when errors had more signatures than were
+	// counted, so a signature missing from ErrorSignatures may still have
+	// occurred
+	SignaturesCapped bool `json:"signatures_capped,omitempty"`
+}
+
+// PatternProfile counts the matches of one pattern
+type PatternProfile struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// SignatureProfile counts the errors sharing an error signature
+type SignatureProfile struct {
+	Count   int    `json:"count"`
+	Example string `json:"example"` // message of the first such error
+}
+
+// ServiceProfile counts the entries and errors of one service
+type ServiceProfile struct {
+	Entries int `json:"entries"`
+	Errors  int `json:"errors"`
+}
+
+// BaselineDiff lists how an analysis differs from a baseline analysis
+type BaselineDiff struct {
+	Baseline        string           `json:"baseline"` // where the baseline came from
+	BaselineEntries int              `json:"baseline_entries"`
+	BaselineErrors  int              `json:"baseline_errors"`
+	RateUnit        string           `json:"rate_unit"` // what rates are counted per
+	Changes         []BaselineChange `json:"changes"`
+}
+
+// BaselineChangeKind categorizes baseline changes
+type BaselineChangeKind string
+
+const (
+	BaselineChangeVolume        BaselineChangeKind = "volume"
+	BaselineChangeNewPattern    BaselineChangeKind = "new_pattern"
+	BaselineChangePatternRate   BaselineChangeKind = "pattern_rate"
+	BaselineChangeNewError      BaselineChangeKind = "new_error"
+	BaselineChangeServiceErrors BaselineChangeKind = "service_errors"
+)
+
+// BaselineChange is one difference from the baseline. Baseline and Current
+// are rates for volume and pattern changes, error ratios for services, and
+// counts for new errors.
+type BaselineChange struct {
+	Kind        BaselineChangeKind `json:"kind"`
+	Key         string             `json:"key"`     // series, pattern ID, error signature or service
+	Subject     string             `json:"subject"` // what changed, for display
+	Baseline    float64            `json:"baseline"`
+	Current     float64            `json:"current"`
+	Description string             `json:"description"`
+}
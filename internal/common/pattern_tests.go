@@ -0,0 +1,85 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// patternTestSuffix marks sidecar test files, so app.yaml is tested by
+// app_test.yaml
+const patternTestSuffix = "_test"
+
+// PatternTests are sample log lines a pattern must and must not match
+type PatternTests struct {
+	Match   []string `yaml:"match,omitempty"`
+	NoMatch []string `yaml:"no_match,omitempty"`
+}
+
+// PatternTestCase holds tests for one pattern in a sidecar test file
+type PatternTestCase struct {
+	Pattern      string `yaml:"pattern"`
+	PatternTests `yaml:",inline"`
+}
+
+// PatternTestFile returns the sidecar test file for a pattern file
+func PatternTestFile(filename string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + patternTestSuffix + ext
+}
+
+// IsPatternTestFile reports whether a file is a sidecar test file rather
+// than a pattern file
+func IsPatternTestFile(filename string) bool {
+	ext := filepath.Ext(filename)
+	return strings.HasSuffix(strings.TrimSuffix(filename, ext), patternTestSuffix)
+}
+
+// LoadPatternTestFile loads the test cases from a sidecar test file
+func LoadPatternTestFile(filename string) ([]*PatternTestCase, error) {
+	if err := validatePatternFilePath(filename); err != nil {
+		return nil, fmt.Errorf("invalid file path: %w", err)
+	}
+
+	// #nosec G304 - path is validated above
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var cases []*PatternTestCase
+	if err := yaml.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	for i, c := range cases {
+		if c == nil || c.Pattern == "" {
+			return nil, fmt.Errorf("test case %d: missing required field: pattern", i)
+		}
+	}
+	return cases, nil
+}
+
+// AttachPatternTests adds sidecar test cases to the patterns they name,
+// after any inline tests. It fails if a case names an unknown pattern.
+func AttachPatternTests(patterns []*Pattern, cases []*PatternTestCase) error {
+	byID := make(map[string]*Pattern, len(patterns))
+	for _, pattern := range patterns {
+		byID[pattern.ID] = pattern
+	}
+
+	for _, c := range cases {
+		pattern, ok := byID[c.Pattern]
+		if !ok {
+			return fmt.Errorf("tests for unknown pattern: %s", c.Pattern)
+		}
+		if pattern.Tests == nil {
+			pattern.Tests = &PatternTests{}
+		}
+		pattern.Tests.Match = append(pattern.Tests.Match, c.Match...)
+		pattern.Tests.NoMatch = append(pattern.Tests.NoMatch, c.NoMatch...)
+	}
+	return nil
+}
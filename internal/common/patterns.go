@@ -13,32 +13,60 @@ import (
 //go:embed embedded_patterns.yaml
 var defaultPatternsYAML []byte
 
+// patternFile is a pattern file that also holds suppression rules
+type patternFile struct {
+	Patterns []*Pattern         `yaml:"patterns"`
+	Suppress []*SuppressionRule `yaml:"suppress"`
+}
+
 // LoadPatternsFromFile loads patterns from a single YAML file
 func LoadPatternsFromFile(filename string) ([]*Pattern, error) {
+	patterns, _, err := LoadPatternFile(filename)
+	return patterns, err
+}
+
+// LoadPatternFile loads the patterns and suppression rules of a YAML file.
+// The file holds a single pattern, a list of patterns, or a mapping with
+// patterns and suppress lists.
+func LoadPatternFile(filename string) ([]*Pattern, []*SuppressionRule, error) {
 	// Validate and sanitize file path for security
 	if err := validatePatternFilePath(filename); err != nil {
-		return nil, fmt.Errorf("invalid file path: %w", err)
+		return nil, nil, fmt.Errorf("invalid file path: %w", err)
 	}
 
 	// #nosec G304 - path is validated above
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	// Try to parse as single pattern first
 	var pattern Pattern
 	if err := yaml.Unmarshal(data, &pattern); err == nil && pattern.ID != "" {
-		return []*Pattern{&pattern}, nil
+		return []*Pattern{&pattern}, nil, nil
+	}
+
+	// Then as patterns with suppression rules
+	var file patternFile
+	if err := yaml.Unmarshal(data, &file); err == nil && (len(file.Patterns) > 0 || len(file.Suppress) > 0) {
+		for i, rule := range file.Suppress {
+			if rule == nil {
+				return nil, nil, fmt.Errorf("suppression rule %d is empty", i)
+			}
+			if err := rule.Validate(); err != nil {
+				return nil, nil, fmt.Errorf("suppression rule %d: %w", i, err)
+			}
+		}
+		return file.Patterns, file.Suppress, nil
 	}
 
 	// Try to parse as array of patterns
 	var patterns []*Pattern
 	if err := yaml.Unmarshal(data, &patterns); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
-	return patterns, nil
+	return patterns, nil, nil
 }
 
 // validatePatternFilePath validates that a pattern file path is safe to read
@@ -0,0 +1,53 @@
+package common
+
+import "fmt"
+
+// SuppressionRule silences known-benign entries, such as health checks
+// during a deploy. Pattern matches of an entry that satisfies every
+// criterion the rule sets are dropped and counted under the rule's ID.
+type SuppressionRule struct {
+	ID          string   `yaml:"id" json:"id"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Regex       string   `yaml:"regex,omitempty" json:"regex,omitempty"`         // matched against the message and raw line
+	Condition   string   `yaml:"condition,omitempty" json:"condition,omitempty"` // filter expression over entry fields
+	Sources     []string `yaml:"sources,omitempty" json:"sources,omitempty"`     // glob patterns of input sources
+	Since       string   `yaml:"since,omitempty" json:"since,omitempty"`         // start of a time window, inclusive as for --since
+	Until       string   `yaml:"until,omitempty" json:"until,omitempty"`         // end of a time window, inclusive as for --until
+	Patterns    []string `yaml:"patterns,omitempty" json:"patterns,omitempty"`   // IDs of the patterns silenced, all when empty
+}
+
+// Validate checks that a rule has an ID and at least one criterion, so it
+// cannot silence everything by accident
+func (r *SuppressionRule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("missing required field: id")
+	}
+	if r.Regex == "" && r.Condition == "" && len(r.Sources) == 0 && r.Since == "" && r.Until == "" {
+		return fmt.Errorf("suppression rule %s must have a regex, condition, sources or time window", r.ID)
+	}
+	return nil
+}
+
+// Silences reports whether the rule applies to a pattern
+func (r *SuppressionRule) Silences(patternID string) bool {
+	if len(r.Patterns) == 0 {
+		return true
+	}
+	for _, id := range r.Patterns {
+		if id == patternID {
+			return true
+		}
+	}
+	return false
+}
+
+// AttachSuppressions adds each rule to the patterns it silences
+func AttachSuppressions(patterns []*Pattern, rules []*SuppressionRule) {
+	for _, pattern := range patterns {
+		for _, rule := range rules {
+			if rule.Silences(pattern.ID) {
+				pattern.Suppressions = append(pattern.Suppressions, rule)
+			}
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package common
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/yildizm/go-logparser"
 )
@@ -31,14 +33,81 @@ type LogEntry struct {
 
 // Pattern represents a log pattern for detection
 type Pattern struct {
-	ID          string      `yaml:"id" json:"id"`
-	Name        string      `yaml:"name" json:"name"`
-	Description string      `yaml:"description" json:"description"`
-	Type        PatternType `yaml:"type" json:"type"`
-	Regex       string      `yaml:"regex,omitempty" json:"regex,omitempty"`
-	Keywords    []string    `yaml:"keywords,omitempty" json:"keywords,omitempty"`
-	Severity    LogLevel    `yaml:"severity" json:"severity"`
-	Tags        []string    `yaml:"tags,omitempty" json:"tags,omitempty"`
+	ID          string        `yaml:"id" json:"id"`
+	Name        string        `yaml:"name" json:"name"`
+	Description string        `yaml:"description" json:"description"`
+	Type        PatternType   `yaml:"type" json:"type"`
+	Regex       string        `yaml:"regex,omitempty" json:"regex,omitempty"`
+	Keywords    []string      `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	Condition   string        `yaml:"condition,omitempty" json:"condition,omitempty"` // filter expression over entry fields
+	Exclude     []string      `yaml:"exclude,omitempty" json:"exclude,omitempty"`     // regexes of lines not to match
+	Severity    LogLevel      `yaml:"severity" json:"severity"`
+	Tags        []string      `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Threshold   *Threshold    `yaml:"threshold,omitempty" json:"threshold,omitempty"`
+	Sequence    *Sequence     `yaml:"sequence,omitempty" json:"sequence,omitempty"`
+	Tests       *PatternTests `yaml:"tests,omitempty" json:"-"`
+
+	// CaseSensitive matches Regex and Keywords with case, which is
+	// ignored by default
+	CaseSensitive bool `yaml:"case_sensitive,omitempty" json:"case_sensitive,omitempty"`
+
+	// Suppressions are the rules silencing this pattern's matches
+	Suppressions []*SuppressionRule `yaml:"-" json:"-"`
+}
+
+// Threshold turns a pattern into a frequency rule: it is only reported
+// once Count matching entries fall within a sliding Window. With GroupBy,
+// entries are counted separately per value of that field or regex capture.
+type Threshold struct {
+	Count   int           `yaml:"count" json:"count"`
+	Window  time.Duration `yaml:"window" json:"window"`
+	GroupBy string        `yaml:"group_by,omitempty" json:"group_by,omitempty"`
+}
+
+// Validate checks that a threshold has a positive count and window
+func (t *Threshold) Validate() error {
+	if t.Count < 1 {
+		return fmt.Errorf("threshold count must be at least 1")
+	}
+	if t.Window <= 0 {
+		return fmt.Errorf("threshold window must be positive")
+	}
+	return nil
+}
+
+// Sequence describes the ordered steps of a sequence pattern. Each step
+// must follow the previous one within MaxGap. With JoinBy, only entries
+// sharing the value of that field or regex capture form a sequence. With
+// Absent, the pattern matches when the last step does not follow in time.
+type Sequence struct {
+	Steps  []SequenceStep `yaml:"steps" json:"steps"`
+	MaxGap time.Duration  `yaml:"max_gap" json:"max_gap"`
+	JoinBy string         `yaml:"join_by,omitempty" json:"join_by,omitempty"`
+	Absent bool           `yaml:"absent,omitempty" json:"absent,omitempty"`
+}
+
+// SequenceStep matches one step of a sequence, like a line pattern
+type SequenceStep struct {
+	Regex     string   `yaml:"regex,omitempty" json:"regex,omitempty"`
+	Keywords  []string `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	Condition string   `yaml:"condition,omitempty" json:"condition,omitempty"`
+}
+
+// Validate checks that a sequence has at least two matchable steps and a
+// positive gap
+func (s *Sequence) Validate() error {
+	if len(s.Steps) < 2 {
+		return fmt.Errorf("sequence must have at least 2 steps")
+	}
+	for i, step := range s.Steps {
+		if step.Regex == "" && len(step.Keywords) == 0 && step.Condition == "" {
+			return fmt.Errorf("sequence step %d must have a regex, keywords or a condition", i+1)
+		}
+	}
+	if s.MaxGap <= 0 {
+		return fmt.Errorf("sequence max_gap must be positive")
+	}
+	return nil
 }
 
 // PatternType defines types of patterns
@@ -49,6 +118,7 @@ const (
 	PatternTypeAnomaly     PatternType = "anomaly"
 	PatternTypePerformance PatternType = "performance"
 	PatternTypeSecurity    PatternType = "security"
+	PatternTypeSequence    PatternType = "sequence"
 )
 
 // String methods for LogLevel
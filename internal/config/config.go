@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -13,6 +15,10 @@ type Config struct {
 	Storage  StorageConfig  `yaml:"storage" json:"storage"`
 	Output   OutputConfig   `yaml:"output" json:"output"`
 	Analysis AnalysisConfig `yaml:"analysis" json:"analysis"`
+	Insights InsightsConfig `yaml:"insights" json:"insights"`
+
+	// Formats are custom log formats selectable by name with --format
+	Formats map[string]FormatConfig `yaml:"formats,omitempty" json:"formats,omitempty"`
 }
 
 // PatternConfig configures pattern loading and processing
@@ -21,6 +27,22 @@ type PatternConfig struct {
 	AutoReload     bool                   `yaml:"auto_reload" json:"auto_reload"`
 	CustomPatterns map[string]interface{} `yaml:"custom_patterns" json:"custom_patterns"`
 	EnableDefaults bool                   `yaml:"enable_defaults" json:"enable_defaults"`
+
+	// Suppress silences known-benign matches of any loaded pattern
+	Suppress []SuppressConfig `yaml:"suppress,omitempty" json:"suppress,omitempty"`
+}
+
+// SuppressConfig declares a suppression rule. Pattern matches of entries
+// meeting every criterion set are dropped and counted under the rule's ID.
+type SuppressConfig struct {
+	ID          string   `yaml:"id" json:"id"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Regex       string   `yaml:"regex,omitempty" json:"regex,omitempty"`         // message or raw line
+	Condition   string   `yaml:"condition,omitempty" json:"condition,omitempty"` // filter expression, as for --where
+	Sources     []string `yaml:"sources,omitempty" json:"sources,omitempty"`     // glob patterns of input sources
+	Since       string   `yaml:"since,omitempty" json:"since,omitempty"`         // time window, inclusive as for --since
+	Until       string   `yaml:"until,omitempty" json:"until,omitempty"`         // and --until
+	Patterns    []string `yaml:"patterns,omitempty" json:"patterns,omitempty"`   // pattern IDs silenced, all when empty
 }
 
 // AIConfig configures AI provider settings
@@ -61,6 +83,12 @@ type AnalysisConfig struct {
 	MaxLineLength   int           `yaml:"max_line_length" json:"max_line_length"`
 	StrictMode      bool          `yaml:"strict_mode" json:"strict_mode"`
 
+	// Multi-line entry assembly, e.g. for stack traces
+	Multiline MultilineConfig `yaml:"multiline" json:"multiline"`
+
+	// Template mining of lines no pattern matches
+	Templates TemplatesConfig `yaml:"templates" json:"templates"`
+
 	// Context timeout configurations
 	VectorTimeout      time.Duration `yaml:"vector_timeout" json:"vector_timeout"`           // Vector operations timeout
 	CorrelationTimeout time.Duration `yaml:"correlation_timeout" json:"correlation_timeout"` // Correlation analysis timeout
@@ -68,6 +96,103 @@ type AnalysisConfig struct {
 	CancelCheckPeriod  int           `yaml:"cancel_check_period" json:"cancel_check_period"` // Iterations between cancellation checks
 }
 
+// InsightsConfig tunes the insight detectors. Every detector runs unless
+// disabled, and zero values keep the defaults.
+type InsightsConfig struct {
+	MinConfidence float64 `yaml:"min_confidence" json:"min_confidence"` // insights below this confidence are dropped
+	EvidenceLimit int     `yaml:"evidence_limit" json:"evidence_limit"` // evidence entries kept per insight
+
+	ErrorSpikes ErrorSpikeConfig   `yaml:"error_spikes" json:"error_spikes"`
+	Performance PerformanceConfig  `yaml:"performance" json:"performance"`
+	Anomalies   AnomalyRulesConfig `yaml:"anomalies" json:"anomalies"`
+	Statistical StatisticalConfig  `yaml:"statistical" json:"statistical"`
+	RootCauses  RootCauseConfig    `yaml:"root_causes" json:"root_causes"`
+
+	// Tags tunes the detectors for patterns carrying a tag
+	Tags map[string]InsightTagConfig `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// ErrorSpikeConfig configures error spike detection
+type ErrorSpikeConfig struct {
+	Disabled   bool          `yaml:"disabled" json:"disabled"`
+	Threshold  float64       `yaml:"threshold" json:"threshold"`     // error rate over the average of the two windows before
+	Window     time.Duration `yaml:"window" json:"window"`           // width of the compared windows
+	MinEntries int           `yaml:"min_entries" json:"min_entries"` // entries needed before spikes are looked for
+}
+
+// PerformanceConfig configures performance issue detection
+type PerformanceConfig struct {
+	Disabled     bool     `yaml:"disabled" json:"disabled"`
+	SlowKeywords []string `yaml:"slow_keywords" json:"slow_keywords"` // message keywords that mark slow responses
+}
+
+// AnomalyRulesConfig configures anomaly patterns and service error rates
+type AnomalyRulesConfig struct {
+	Disabled          bool    `yaml:"disabled" json:"disabled"`
+	ServiceErrorRate  float64 `yaml:"service_error_rate" json:"service_error_rate"`   // share of a service's entries that are errors
+	ServiceMinEntries int     `yaml:"service_min_entries" json:"service_min_entries"` // entries a service needs before its rate counts
+}
+
+// StatisticalConfig configures statistical anomaly detection over entry,
+// error and pattern counts
+type StatisticalConfig struct {
+	Disabled    bool          `yaml:"disabled" json:"disabled"`
+	Method      string        `yaml:"method" json:"method"`             // zscore|ewma|mad
+	Sensitivity float64       `yaml:"sensitivity" json:"sensitivity"`   // deviations above the baseline that are anomalous
+	Window      int           `yaml:"window" json:"window"`             // buckets in the rolling baseline
+	MinBaseline int           `yaml:"min_baseline" json:"min_baseline"` // buckets seen before a bucket is scored
+	MinCount    int           `yaml:"min_count" json:"min_count"`       // events an anomalous bucket must hold
+	Alpha       float64       `yaml:"alpha" json:"alpha"`               // ewma smoothing factor
+	BucketSize  time.Duration `yaml:"bucket_size" json:"bucket_size"`   // width of the counted buckets
+}
+
+// RootCauseConfig configures root cause correlation
+type RootCauseConfig struct {
+	Disabled             bool    `yaml:"disabled" json:"disabled"`
+	CorrelationThreshold float64 `yaml:"correlation_threshold" json:"correlation_threshold"` // time overlap of two error patterns
+}
+
+// InsightTagConfig tunes the detectors for patterns with a tag
+type InsightTagConfig struct {
+	Disabled             bool    `yaml:"disabled,omitempty" json:"disabled,omitempty"`                           // no insights about these patterns
+	MinConfidence        float64 `yaml:"min_confidence,omitempty" json:"min_confidence,omitempty"`               // pattern insights below this are dropped
+	Sensitivity          float64 `yaml:"sensitivity,omitempty" json:"sensitivity,omitempty"`                     // statistical anomaly sensitivity
+	CorrelationThreshold float64 `yaml:"correlation_threshold,omitempty" json:"correlation_threshold,omitempty"` // root cause correlation
+}
+
+// MultilineConfig configures how continuation lines are joined into one entry
+type MultilineConfig struct {
+	Disabled      bool     `yaml:"disabled" json:"disabled"`             // one entry per line
+	StartPatterns []string `yaml:"start_patterns" json:"start_patterns"` // regexes matching the first line of an entry
+}
+
+// TemplatesConfig tunes template mining. Zero values keep the defaults.
+type TemplatesConfig struct {
+	Disabled    bool    `yaml:"disabled" json:"disabled"`
+	Limit       int     `yaml:"limit" json:"limit"`               // templates reported
+	Depth       int     `yaml:"depth" json:"depth"`               // prefix tree depth
+	Similarity  float64 `yaml:"similarity" json:"similarity"`     // share of equal tokens needed to join a template
+	MaxClusters int     `yaml:"max_clusters" json:"max_clusters"` // templates tracked
+	MaxExamples int     `yaml:"max_examples" json:"max_examples"` // example entries kept per template
+}
+
+// FormatConfig declares a custom log format as a regex with named groups or
+// a grok expression
+type FormatConfig struct {
+	Regex           string            `yaml:"regex,omitempty" json:"regex,omitempty"`                       // regex with named groups
+	Grok            string            `yaml:"grok,omitempty" json:"grok,omitempty"`                         // grok expression, e.g. %{LOGLEVEL:level}
+	TimestampFormat string            `yaml:"timestamp_format,omitempty" json:"timestamp_format,omitempty"` // Go time layout of the timestamp capture
+	Fields          map[string]string `yaml:"fields,omitempty" json:"fields,omitempty"`                     // capture name -> entry field
+}
+
+// reservedFormatNames are the built-in formats custom formats cannot replace
+var reservedFormatNames = map[string]bool{
+	"auto":   true,
+	"json":   true,
+	"logfmt": true,
+	"text":   true,
+}
+
 // DefaultConfig returns a configuration with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
@@ -101,7 +226,7 @@ func DefaultConfig() *Config {
 			CompactMode:     false,
 		},
 		Analysis: AnalysisConfig{
-			MaxEntries:      100000,
+			MaxEntries:      100000, // Entries held in memory for the TUI and AI analysis
 			TimelineBuckets: 60,
 			EnableInsights:  true,
 			Timeout:         60 * time.Second,
@@ -132,6 +257,68 @@ func (c *Config) Validate() error {
 	if err := c.validateTimeoutConfig(); err != nil {
 		return err
 	}
+	if err := ValidateFormats(c.Formats); err != nil {
+		return err
+	}
+	if err := c.validateSuppressConfig(); err != nil {
+		return err
+	}
+	if err := c.validateInsightsConfig(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateInsightsConfig checks insight thresholds for values the
+// detectors cannot work with
+func (c *Config) validateInsightsConfig() error {
+	insights := c.Insights
+	if insights.MinConfidence < 0 || insights.MinConfidence > 1 {
+		return fmt.Errorf("insights min_confidence must be between 0 and 1")
+	}
+	if insights.EvidenceLimit < 0 {
+		return fmt.Errorf("insights evidence_limit must be non-negative")
+	}
+	if insights.ErrorSpikes.Threshold != 0 && insights.ErrorSpikes.Threshold <= 1 {
+		return fmt.Errorf("insights error_spikes threshold must be greater than 1")
+	}
+	if insights.ErrorSpikes.Window < 0 || insights.Statistical.BucketSize < 0 {
+		return fmt.Errorf("insights windows must be non-negative")
+	}
+	if insights.Anomalies.ServiceErrorRate < 0 || insights.Anomalies.ServiceErrorRate > 1 {
+		return fmt.Errorf("insights service_error_rate must be between 0 and 1")
+	}
+	if insights.RootCauses.CorrelationThreshold < 0 || insights.RootCauses.CorrelationThreshold > 1 {
+		return fmt.Errorf("insights correlation_threshold must be between 0 and 1")
+	}
+	switch insights.Statistical.Method {
+	case "", "zscore", "ewma", "mad":
+	default:
+		return fmt.Errorf("invalid anomaly method: %s (must be one of: zscore, ewma, mad)", insights.Statistical.Method)
+	}
+	if insights.Statistical.Sensitivity < 0 || insights.Statistical.Alpha < 0 || insights.Statistical.Alpha > 1 {
+		return fmt.Errorf("insights statistical sensitivity and alpha must be non-negative, alpha at most 1")
+	}
+	for tag, tuning := range insights.Tags {
+		if tuning.MinConfidence < 0 || tuning.MinConfidence > 1 || tuning.Sensitivity < 0 ||
+			tuning.CorrelationThreshold < 0 || tuning.CorrelationThreshold > 1 {
+			return fmt.Errorf("insights tuning of tag %s is out of range", tag)
+		}
+	}
+	return nil
+}
+
+// validateSuppressConfig checks that suppression rules have an ID and at
+// least one criterion
+func (c *Config) validateSuppressConfig() error {
+	for i, rule := range c.Patterns.Suppress {
+		if rule.ID == "" {
+			return fmt.Errorf("suppression rule %d is missing an id", i)
+		}
+		if rule.Regex == "" && rule.Condition == "" && len(rule.Sources) == 0 && rule.Since == "" && rule.Until == "" {
+			return fmt.Errorf("suppression rule %s must have a regex, condition, sources or time window", rule.ID)
+		}
+	}
 	return nil
 }
 
@@ -193,6 +380,32 @@ func (c *Config) validateAnalysisConfig() error {
 	if c.Analysis.MaxLineLength < 1 {
 		return fmt.Errorf("max_line_length must be greater than 0")
 	}
+	for _, pattern := range c.Analysis.Multiline.StartPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid multiline start pattern %q: %w", pattern, err)
+		}
+	}
+	templates := c.Analysis.Templates
+	if templates.Limit < 0 || templates.Depth < 0 || templates.MaxClusters < 0 || templates.MaxExamples < 0 {
+		return fmt.Errorf("templates limit, depth, max_clusters and max_examples must be non-negative")
+	}
+	if templates.Similarity < 0 || templates.Similarity > 1 {
+		return fmt.Errorf("templates similarity must be between 0 and 1")
+	}
+	return nil
+}
+
+// ValidateFormats checks that custom formats have valid names and exactly
+// one expression
+func ValidateFormats(formats map[string]FormatConfig) error {
+	for name, format := range formats {
+		if reservedFormatNames[strings.ToLower(name)] {
+			return fmt.Errorf("custom format name %s is reserved for a built-in format", name)
+		}
+		if (format.Regex == "") == (format.Grok == "") {
+			return fmt.Errorf("custom format %s must set exactly one of regex or grok", name)
+		}
+	}
 	return nil
 }
 
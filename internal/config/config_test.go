@@ -104,6 +104,80 @@ func TestConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max_retries must be non-negative",
 		},
+		{
+			name: "invalid multiline start pattern",
+			config: &Config{
+				Analysis: AnalysisConfig{
+					MaxEntries:        100,
+					TimelineBuckets:   10,
+					BufferSize:        1024,
+					MaxLineLength:     1024,
+					Multiline:         MultilineConfig{StartPatterns: []string{"("}},
+					CancelCheckPeriod: 100,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "reserved custom format name",
+			config: &Config{
+				Analysis: AnalysisConfig{
+					MaxEntries:        100,
+					TimelineBuckets:   10,
+					BufferSize:        1024,
+					MaxLineLength:     1024,
+					CancelCheckPeriod: 100,
+				},
+				Formats: map[string]FormatConfig{"json": {Regex: ".*"}},
+			},
+			wantErr: true,
+			errMsg:  "custom format name json is reserved for a built-in format",
+		},
+		{
+			name: "suppression rule without criteria",
+			config: &Config{
+				Patterns: PatternConfig{Suppress: []SuppressConfig{{ID: "everything"}}},
+				Analysis: AnalysisConfig{
+					MaxEntries:        100,
+					TimelineBuckets:   10,
+					BufferSize:        1024,
+					MaxLineLength:     1024,
+					CancelCheckPeriod: 100,
+				},
+			},
+			wantErr: true,
+			errMsg:  "suppression rule everything must have a regex, condition, sources or time window",
+		},
+		{
+			name: "invalid anomaly method",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Insights.Statistical.Method = "median"
+				return cfg
+			}(),
+			wantErr: true,
+			errMsg:  "invalid anomaly method: median (must be one of: zscore, ewma, mad)",
+		},
+		{
+			name: "template similarity out of range",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Analysis.Templates.Similarity = 1.5
+				return cfg
+			}(),
+			wantErr: true,
+			errMsg:  "templates similarity must be between 0 and 1",
+		},
+		{
+			name: "insight tag out of range",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Insights.Tags = map[string]InsightTagConfig{"noisy": {MinConfidence: 2}}
+				return cfg
+			}(),
+			wantErr: true,
+			errMsg:  "insights tuning of tag noisy is out of range",
+		},
 	}
 
 	for _, tt := range tests {
@@ -92,8 +92,18 @@ func (l *Loader) loadFromFile(config *Config, path string) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Create a temporary config to unmarshal into
-	var fileConfig Config
+	// Create a temporary config to unmarshal into. The insight detectors'
+	// switches start at their current values, so the ones the file leaves
+	// out are kept.
+	fileConfig := Config{
+		Insights: InsightsConfig{
+			ErrorSpikes: ErrorSpikeConfig{Disabled: config.Insights.ErrorSpikes.Disabled},
+			Performance: PerformanceConfig{Disabled: config.Insights.Performance.Disabled},
+			Anomalies:   AnomalyRulesConfig{Disabled: config.Insights.Anomalies.Disabled},
+			Statistical: StatisticalConfig{Disabled: config.Insights.Statistical.Disabled},
+			RootCauses:  RootCauseConfig{Disabled: config.Insights.RootCauses.Disabled},
+		},
+	}
 	if err := yaml.Unmarshal(data, &fileConfig); err != nil {
 		return fmt.Errorf("failed to parse YAML: %w", err)
 	}
@@ -130,13 +140,15 @@ func (l *Loader) applyEnvOverrides(config *Config) error {
 		"LOGSUM_OUTPUT_COMPACT_MODE":     func(v string) error { return parseBool(v, &config.Output.CompactMode) },
 
 		// Analysis Config
-		"LOGSUM_ANALYSIS_MAX_ENTRIES":      func(v string) error { return parseInt(v, &config.Analysis.MaxEntries) },
-		"LOGSUM_ANALYSIS_TIMELINE_BUCKETS": func(v string) error { return parseInt(v, &config.Analysis.TimelineBuckets) },
-		"LOGSUM_ANALYSIS_ENABLE_INSIGHTS":  func(v string) error { return parseBool(v, &config.Analysis.EnableInsights) },
-		"LOGSUM_ANALYSIS_TIMEOUT":          func(v string) error { return parseDuration(v, &config.Analysis.Timeout) },
-		"LOGSUM_ANALYSIS_BUFFER_SIZE":      func(v string) error { return parseInt(v, &config.Analysis.BufferSize) },
-		"LOGSUM_ANALYSIS_MAX_LINE_LENGTH":  func(v string) error { return parseInt(v, &config.Analysis.MaxLineLength) },
-		"LOGSUM_ANALYSIS_STRICT_MODE":      func(v string) error { return parseBool(v, &config.Analysis.StrictMode) },
+		"LOGSUM_ANALYSIS_MAX_ENTRIES":        func(v string) error { return parseInt(v, &config.Analysis.MaxEntries) },
+		"LOGSUM_ANALYSIS_TIMELINE_BUCKETS":   func(v string) error { return parseInt(v, &config.Analysis.TimelineBuckets) },
+		"LOGSUM_ANALYSIS_ENABLE_INSIGHTS":    func(v string) error { return parseBool(v, &config.Analysis.EnableInsights) },
+		"LOGSUM_ANALYSIS_TIMEOUT":            func(v string) error { return parseDuration(v, &config.Analysis.Timeout) },
+		"LOGSUM_ANALYSIS_BUFFER_SIZE":        func(v string) error { return parseInt(v, &config.Analysis.BufferSize) },
+		"LOGSUM_ANALYSIS_MAX_LINE_LENGTH":    func(v string) error { return parseInt(v, &config.Analysis.MaxLineLength) },
+		"LOGSUM_ANALYSIS_STRICT_MODE":        func(v string) error { return parseBool(v, &config.Analysis.StrictMode) },
+		"LOGSUM_ANALYSIS_MULTILINE_DISABLED": func(v string) error { return parseBool(v, &config.Analysis.Multiline.Disabled) },
+		"LOGSUM_ANALYSIS_TEMPLATES_DISABLED": func(v string) error { return parseBool(v, &config.Analysis.Templates.Disabled) },
 
 		// Pattern Config
 		"LOGSUM_PATTERNS_AUTO_RELOAD":     func(v string) error { return parseBool(v, &config.Patterns.AutoReload) },
@@ -247,6 +259,21 @@ func mergeConfigs(dst, src *Config) {
 	mergeStorageConfig(&dst.Storage, &src.Storage)
 	mergeOutputConfig(&dst.Output, &src.Output)
 	mergeAnalysisConfig(&dst.Analysis, &src.Analysis)
+	mergeInsightsConfig(&dst.Insights, &src.Insights)
+	mergeFormats(dst, src.Formats)
+}
+
+// mergeFormats adds custom formats, replacing any with the same name
+func mergeFormats(dst *Config, formats map[string]FormatConfig) {
+	if len(formats) == 0 {
+		return
+	}
+	if dst.Formats == nil {
+		dst.Formats = make(map[string]FormatConfig)
+	}
+	for name, format := range formats {
+		dst.Formats[name] = format
+	}
 }
 
 // mergePatternConfig merges pattern configuration
@@ -265,6 +292,8 @@ func mergePatternConfig(dst, src *PatternConfig) {
 			dst.CustomPatterns[k] = v
 		}
 	}
+	// Suppression rules of every config file apply
+	dst.Suppress = append(dst.Suppress, src.Suppress...)
 	if !src.EnableDefaults && dst.EnableDefaults {
 		// Only override if explicitly set to false in source
 		dst.EnableDefaults = src.EnableDefaults
@@ -344,8 +373,61 @@ func mergeAnalysisConfig(dst, src *AnalysisConfig) {
 	if src.MaxLineLength != 0 {
 		dst.MaxLineLength = src.MaxLineLength
 	}
+	if len(src.Multiline.StartPatterns) > 0 {
+		dst.Multiline.StartPatterns = src.Multiline.StartPatterns
+	}
 	mergeIfSet(&dst.EnableInsights, src.EnableInsights)
 	mergeIfSet(&dst.StrictMode, src.StrictMode)
+	mergeIfSet(&dst.Multiline.Disabled, src.Multiline.Disabled)
+
+	mergeIfSet(&dst.Templates.Disabled, src.Templates.Disabled)
+	mergeInt(&dst.Templates.Limit, src.Templates.Limit)
+	mergeInt(&dst.Templates.Depth, src.Templates.Depth)
+	mergeFloat(&dst.Templates.Similarity, src.Templates.Similarity)
+	mergeInt(&dst.Templates.MaxClusters, src.Templates.MaxClusters)
+	mergeInt(&dst.Templates.MaxExamples, src.Templates.MaxExamples)
+}
+
+// mergeInsightsConfig merges insight tuning. A detector stays disabled or
+// enabled unless a later config file says otherwise.
+func mergeInsightsConfig(dst, src *InsightsConfig) {
+	mergeFloat(&dst.MinConfidence, src.MinConfidence)
+	mergeInt(&dst.EvidenceLimit, src.EvidenceLimit)
+
+	mergeIfSet(&dst.ErrorSpikes.Disabled, src.ErrorSpikes.Disabled)
+	mergeFloat(&dst.ErrorSpikes.Threshold, src.ErrorSpikes.Threshold)
+	mergeDuration(&dst.ErrorSpikes.Window, src.ErrorSpikes.Window)
+	mergeInt(&dst.ErrorSpikes.MinEntries, src.ErrorSpikes.MinEntries)
+
+	mergeIfSet(&dst.Performance.Disabled, src.Performance.Disabled)
+	if len(src.Performance.SlowKeywords) > 0 {
+		dst.Performance.SlowKeywords = src.Performance.SlowKeywords
+	}
+
+	mergeIfSet(&dst.Anomalies.Disabled, src.Anomalies.Disabled)
+	mergeFloat(&dst.Anomalies.ServiceErrorRate, src.Anomalies.ServiceErrorRate)
+	mergeInt(&dst.Anomalies.ServiceMinEntries, src.Anomalies.ServiceMinEntries)
+
+	mergeIfSet(&dst.Statistical.Disabled, src.Statistical.Disabled)
+	if src.Statistical.Method != "" {
+		dst.Statistical.Method = src.Statistical.Method
+	}
+	mergeFloat(&dst.Statistical.Sensitivity, src.Statistical.Sensitivity)
+	mergeInt(&dst.Statistical.Window, src.Statistical.Window)
+	mergeInt(&dst.Statistical.MinBaseline, src.Statistical.MinBaseline)
+	mergeInt(&dst.Statistical.MinCount, src.Statistical.MinCount)
+	mergeFloat(&dst.Statistical.Alpha, src.Statistical.Alpha)
+	mergeDuration(&dst.Statistical.BucketSize, src.Statistical.BucketSize)
+
+	mergeIfSet(&dst.RootCauses.Disabled, src.RootCauses.Disabled)
+	mergeFloat(&dst.RootCauses.CorrelationThreshold, src.RootCauses.CorrelationThreshold)
+
+	if len(src.Tags) > 0 && dst.Tags == nil {
+		dst.Tags = make(map[string]InsightTagConfig)
+	}
+	for tag, tuning := range src.Tags {
+		dst.Tags[tag] = tuning
+	}
 }
 
 // mergeIfSet only merges boolean values if they appear to be explicitly set
@@ -355,6 +437,27 @@ func mergeIfSet(dst *bool, src bool) {
 	*dst = src
 }
 
+// mergeInt merges a non-zero integer
+func mergeInt(dst *int, src int) {
+	if src != 0 {
+		*dst = src
+	}
+}
+
+// mergeFloat merges a non-zero float
+func mergeFloat(dst *float64, src float64) {
+	if src != 0 {
+		*dst = src
+	}
+}
+
+// mergeDuration merges a non-zero duration
+func mergeDuration(dst *time.Duration, src time.Duration) {
+	if src != 0 {
+		*dst = src
+	}
+}
+
 // Type conversion helpers
 
 func parseInt(s string, dst *int) error {
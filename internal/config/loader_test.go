@@ -85,6 +85,74 @@ analysis:
 	}
 }
 
+func TestLoadConfigInsights(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test-config.yaml")
+	configContent := `insights:
+  error_spikes:
+    disabled: true
+  statistical:
+    method: mad
+    bucket_size: 30s
+  tags:
+    noisy:
+      disabled: true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	cfg, err := NewLoader().LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config from file: %v", err)
+	}
+
+	if !cfg.Insights.ErrorSpikes.Disabled || cfg.Insights.Performance.Disabled {
+		t.Errorf("Expected only error spikes disabled, got %+v", cfg.Insights)
+	}
+	statistical := cfg.Insights.Statistical
+	if statistical.Method != "mad" || statistical.BucketSize != 30*time.Second || statistical.Sensitivity != 0 {
+		t.Errorf("Expected mad over 30s buckets with the sensitivity left unset, got %+v", statistical)
+	}
+	if !cfg.Insights.Tags["noisy"].Disabled {
+		t.Errorf("Expected tag noisy disabled, got %+v", cfg.Insights.Tags)
+	}
+}
+
+func TestLoadConfigLayersInsightToggles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"system.yaml":  "insights:\n  error_spikes:\n    disabled: true\n  root_causes:\n    disabled: true\n",
+		"user.yaml":    "insights:\n  error_spikes:\n    disabled: false\n",
+		"project.yaml": "insights:\n  min_confidence: 0.5\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write test config file: %v", err)
+		}
+	}
+
+	// Paths are listed from highest to lowest priority
+	loader := &Loader{configPaths: []string{
+		filepath.Join(dir, "project.yaml"),
+		filepath.Join(dir, "user.yaml"),
+		filepath.Join(dir, "system.yaml"),
+	}}
+	cfg, err := loader.LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if cfg.Insights.ErrorSpikes.Disabled {
+		t.Error("Expected error spikes re-enabled by the user config")
+	}
+	if !cfg.Insights.RootCauses.Disabled {
+		t.Error("Expected root causes to stay disabled when later files leave them out")
+	}
+	if cfg.Insights.MinConfidence != 0.5 {
+		t.Errorf("Expected min confidence 0.5, got %v", cfg.Insights.MinConfidence)
+	}
+}
+
 func TestLoadConfigInvalidYAML(t *testing.T) {
 	// Create a temporary config file with invalid YAML
 	tempDir := t.TempDir()
@@ -85,10 +85,11 @@ output:
 
 # Analysis configuration
 analysis:
-  # Maximum number of log entries to process
+  # Maximum number of log entries held in memory for the interactive TUI
+  # and --ai; other output streams the whole input
   max_entries: 100000
   
-  # Number of buckets for timeline analysis
+  # Maximum number of timeline buckets; long logs get wider buckets
   timeline_buckets: 60
   
   # Enable insights generation
@@ -105,6 +106,81 @@ analysis:
   
   # Enable strict parsing mode
   strict_mode: false
+  
+  # Multi-line entries: stack trace frames and indented continuation
+  # lines are joined to the entry they follow
+  multiline:
+    # Treat every line as a separate entry
+    disabled: false
+    
+    # Regexes matching the first line of an entry; every other line is
+    # joined to the previous entry (replaces the built-in rules)
+    start_patterns: []
+    #   - '^\d{4}-\d{2}-\d{2}'
+
+# Insight detectors (each runs unless disabled; omitted values keep the defaults)
+insights:
+  # Drop insights below this confidence (0-1)
+  min_confidence: 0
+  
+  # Evidence entries kept per insight
+  evidence_limit: 5
+  
+  # Error rate of a window compared with the two windows before it
+  error_spikes:
+    disabled: false
+    threshold: 2.0
+    window: 5m
+    min_entries: 10
+  
+  # Performance patterns and messages with slow response keywords
+  performance:
+    disabled: false
+    slow_keywords: ["slow", "timeout", "taking too long", "high latency", "response time"]
+  
+  # Anomaly patterns and services with a high error rate
+  anomalies:
+    disabled: false
+    service_error_rate: 0.5
+    service_min_entries: 10
+  
+  # Entry, error and pattern counts far above their rolling baseline
+  statistical:
+    disabled: false
+    method: zscore       # zscore, ewma or mad
+    sensitivity: 3       # deviations above the baseline
+    window: 30           # buckets in the baseline
+    min_baseline: 5
+    min_count: 5
+    alpha: 0.3           # ewma only
+    bucket_size: 1m
+  
+  # Error patterns that occur together
+  root_causes:
+    disabled: false
+    correlation_threshold: 0.6
+  
+  # Tuning for patterns with a tag; the first tuned tag of a pattern wins
+  # tags:
+  #   noisy:
+  #     disabled: true
+  #   critical:
+  #     sensitivity: 2
+  #     min_confidence: 0.5
+
+# Custom log formats, selected with --format <name> (optional)
+# formats:
+#   legacy:
+#     # Grok expression or, alternatively, a regex with named groups
+#     grok: '%{TIMESTAMP_ISO8601:timestamp} %{LOGLEVEL:level} \[%{WORD:service}\] %{GREEDYDATA:message}'
+#   haproxy:
+#     regex: '^(?P<ts>\S+ \S+) (?P<client>[\d.]+):\d+ (?P<msg>.*)$'
+#     timestamp_format: "2006-01-02 15:04:05"
+#     # Map capture names onto timestamp, level, message, service or trace_id;
+#     # other captures are stored as metadata
+#     fields:
+#       ts: timestamp
+#       msg: message
 `
 }
 
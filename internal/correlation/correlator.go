@@ -674,6 +674,13 @@ func (c *correlator) groupSimilarErrors(entries []*common.LogEntry) map[string][
 	return groups
 }
 
+// ErrorSignature returns the signature errors are grouped by: the error
+// type and the message with variable data removed, qualified by the
+// entry's source, service and level
+func ErrorSignature(entry *common.LogEntry) string {
+	return (&correlator{}).generateErrorSignature(entry)
+}
+
 // generateErrorSignature creates a unique signature for error grouping based on multiple factors
 func (c *correlator) generateErrorSignature(entry *common.LogEntry) string {
 	var signatureParts []string
@@ -683,7 +690,7 @@ func (c *correlator) generateErrorSignature(entry *common.LogEntry) string {
 	signatureParts = append(signatureParts, errorType)
 
 	// 2. Normalize the error message by removing variable data
-	normalizedMessage := c.normalizeErrorMessage(entry.Message)
+	normalizedMessage := NormalizeMessage(entry.Message)
 	signatureParts = append(signatureParts, normalizedMessage)
 
 	// 3. Add context-sensitive information
@@ -708,69 +715,77 @@ func (c *correlator) generateErrorSignature(entry *common.LogEntry) string {
 	return strings.Join(signatureParts, "|")
 }
 
-// normalizeErrorMessage removes variable data to enable better grouping
-func (c *correlator) normalizeErrorMessage(message string) string {
-	normalized := message
+// messageNormalization replaces variable data matching regex with a
+// placeholder
+type messageNormalization struct {
+	regex       *regexp.Regexp
+	placeholder string
+}
 
-	// Define patterns to normalize (replace with placeholders)
-	normalizationPatterns := []struct {
-		pattern     string
-		placeholder string
-	}{
-		// IDs and numeric values
-		{`\b\d{4,}\b`, "<ID>"}, // Long numbers (IDs, timestamps)
-		{`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`, "<UUID>"}, // UUIDs
-		{`\b[0-9a-fA-F]{32}\b`, "<HASH>"}, // MD5 hashes
-		{`\b[0-9a-fA-F]{40}\b`, "<HASH>"}, // SHA1 hashes
-		{`\b[0-9a-fA-F]{64}\b`, "<HASH>"}, // SHA256 hashes
-
-		// File paths and URLs
-		{`/[\w\-/\.]+/[\w\-\.]+`, "<PATH>"}, // File paths
-		{`https?://[^\s]+`, "<URL>"},        // URLs
-		{`file://[^\s]+`, "<FILE_URL>"},     // File URLs
-
-		// Network addresses
-		{`\b(?:\d{1,3}\.){3}\d{1,3}:\d+\b`, "<IP:PORT>"}, // IP:port
-		{`\b(?:\d{1,3}\.){3}\d{1,3}\b`, "<IP>"},          // IP addresses
-		{`localhost:\d+`, "<LOCALHOST>"},                 // localhost with port
-
-		// Timestamps and durations
-		{`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}`, "<TIMESTAMP>"}, // ISO timestamps
-		{`\d+ms\b`, "<DURATION>"},                                 // Milliseconds
-		{`\d+s\b`, "<DURATION>"},                                  // Seconds
-		{`\d+\.\d+s\b`, "<DURATION>"},                             // Float seconds
-
-		// Database specific
-		{`'[^']*'`, "<STRING>"}, // Single quoted strings (SQL values)
-		{`"[^"]*"`, "<STRING>"}, // Double quoted strings
-		{`=\s*\w+`, "=<VALUE>"}, // Assignment values
-
-		// Memory addresses and references
-		{`0x[0-9a-fA-F]+`, "<MEMADDR>"}, // Memory addresses
-		{`@[0-9a-fA-F]+`, "<OBJREF>"},   // Object references
-
-		// User/session specific
-		{`user_id[=:]\w+`, "user_id=<ID>"}, // User IDs
-		{`session[=:]\w+`, "session=<ID>"}, // Session IDs
-		{`token[=:]\w+`, "token=<TOKEN>"},  // Tokens
-
-		// Business domain specific (terms example)
-		{`promo_id[=:]['"]?[A-Z0-9]+['"]?`, "promo_id=<PROMO_ID>"},      // Promo IDs like SUMMER2024
-		{`campaign[=:]['"]?[A-Za-z0-9_-]+['"]?`, "campaign=<CAMPAIGN>"}, // Campaign names
-
-		// Generic parameter patterns
-		{`\w+=[^\s,)]+`, "<PARAM>"}, // Generic key=value pairs
-	}
+// messageNormalizations are applied to error messages in order
+var messageNormalizations = []messageNormalization{
+	// IDs and numeric values
+	{regexp.MustCompile(`\b\d{4,}\b`), "<ID>"}, // Long numbers (IDs, timestamps)
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "<UUID>"}, // UUIDs
+	{regexp.MustCompile(`\b[0-9a-fA-F]{32}\b`), "<HASH>"},                                                             // MD5 hashes
+	{regexp.MustCompile(`\b[0-9a-fA-F]{40}\b`), "<HASH>"},                                                             // SHA1 hashes
+	{regexp.MustCompile(`\b[0-9a-fA-F]{64}\b`), "<HASH>"},                                                             // SHA256 hashes
+
+	// File paths and URLs
+	{regexp.MustCompile(`/[\w\-/\.]+/[\w\-\.]+`), "<PATH>"}, // File paths
+	{regexp.MustCompile(`https?://[^\s]+`), "<URL>"},        // URLs
+	{regexp.MustCompile(`file://[^\s]+`), "<FILE_URL>"},     // File URLs
+
+	// Network addresses
+	{regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}:\d+\b`), "<IP:PORT>"}, // IP:port
+	{regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), "<IP>"},          // IP addresses
+	{regexp.MustCompile(`localhost:\d+`), "<LOCALHOST>"},                 // localhost with port
+
+	// Timestamps and durations
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}`), "<TIMESTAMP>"}, // ISO timestamps
+	{regexp.MustCompile(`\d+ms\b`), "<DURATION>"},                                 // Milliseconds
+	{regexp.MustCompile(`\d+s\b`), "<DURATION>"},                                  // Seconds
+	{regexp.MustCompile(`\d+\.\d+s\b`), "<DURATION>"},                             // Float seconds
+
+	// Database specific
+	{regexp.MustCompile(`'[^']*'`), "<STRING>"}, // Single quoted strings (SQL values)
+	{regexp.MustCompile(`"[^"]*"`), "<STRING>"}, // Double quoted strings
+	{regexp.MustCompile(`=\s*\w+`), "=<VALUE>"}, // Assignment values
+
+	// Memory addresses and references
+	{regexp.MustCompile(`0x[0-9a-fA-F]+`), "<MEMADDR>"}, // Memory addresses
+	{regexp.MustCompile(`@[0-9a-fA-F]+`), "<OBJREF>"},   // Object references
+
+	// User/session specific
+	{regexp.MustCompile(`user_id[=:]\w+`), "user_id=<ID>"}, // User IDs
+	{regexp.MustCompile(`session[=:]\w+`), "session=<ID>"}, // Session IDs
+	{regexp.MustCompile(`token[=:]\w+`), "token=<TOKEN>"},  // Tokens
+
+	// Business domain specific (terms example)
+	{regexp.MustCompile(`promo_id[=:]['"]?[A-Z0-9]+['"]?`), "promo_id=<PROMO_ID>"},      // Promo IDs like SUMMER2024
+	{regexp.MustCompile(`campaign[=:]['"]?[A-Za-z0-9_-]+['"]?`), "campaign=<CAMPAIGN>"}, // Campaign names
+
+	// Generic parameter patterns
+	{regexp.MustCompile(`\w+=[^\s,)]+`), "<PARAM>"}, // Generic key=value pairs
+}
+
+// whitespaceRegex matches runs of whitespace
+var whitespaceRegex = regexp.MustCompile(`\s+`)
+
+// NormalizeMessage removes variable data such as IDs, addresses, paths and
+// quoted values from an error message, so messages that differ only in
+// those group together
+func NormalizeMessage(message string) string {
+	normalized := message
 
 	// Apply normalizations
-	for _, np := range normalizationPatterns {
-		re := regexp.MustCompile(np.pattern)
-		normalized = re.ReplaceAllString(normalized, np.placeholder)
+	for _, n := range messageNormalizations {
+		normalized = n.regex.ReplaceAllString(normalized, n.placeholder)
 	}
 
 	// Additional cleanup
 	// Remove excessive whitespace
-	normalized = regexp.MustCompile(`\s+`).ReplaceAllString(normalized, " ")
+	normalized = whitespaceRegex.ReplaceAllString(normalized, " ")
 
 	// Trim and limit length to prevent extremely long signatures
 	normalized = strings.TrimSpace(normalized)
@@ -790,6 +805,11 @@ func (c *correlator) extractErrorType(entry *common.LogEntry) string {
 		return errorType
 	}
 
+	// A joined stack trace names the exception below its first line
+	if errorType := c.extractExceptionPatterns(entry.Raw); errorType != "" {
+		return errorType
+	}
+
 	if errorType := c.extractDomainPatterns(message); errorType != "" {
 		return errorType
 	}
@@ -98,6 +98,20 @@ func TestCorrelator(t *testing.T) {
 	}
 }
 
+func TestNormalizeMessage(t *testing.T) {
+	// Signatures are stored in saved profiles, so they must stay stable
+	tests := map[string]string{
+		"query took 1.5s after 250ms":        "query took 1.<DURATION> after <DURATION>",
+		"order 123456 failed for 10.0.0.1":   "order <ID> failed for <IP>",
+		"user_id=42 denied at /api/v1/users": "<PARAM> denied at <PATH>",
+	}
+	for message, want := range tests {
+		if got := NormalizeMessage(message); got != want {
+			t.Errorf("NormalizeMessage(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
+
 func TestKeywordExtractor(t *testing.T) {
 	extractor := NewKeywordExtractor()
 
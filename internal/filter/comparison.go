@@ -0,0 +1,234 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// fieldKind selects how a field is compared
+type fieldKind int
+
+const (
+	fieldText fieldKind = iota
+	fieldLevel
+	fieldTime
+)
+
+// fieldAliases maps accepted field names onto canonical ones
+var fieldAliases = map[string]string{
+	"level":       "level",
+	"severity":    "level",
+	"timestamp":   "timestamp",
+	"time":        "timestamp",
+	"message":     "message",
+	"msg":         "message",
+	"service":     "service",
+	"trace_id":    "trace_id",
+	"source":      "source",
+	"line":        "line",
+	"line_number": "line",
+	"raw":         "raw",
+}
+
+// levelNames are the level values a filter accepts
+var levelNames = map[string]common.LogLevel{
+	"DEBUG":   common.LevelDebug,
+	"INFO":    common.LevelInfo,
+	"WARN":    common.LevelWarn,
+	"WARNING": common.LevelWarn,
+	"ERROR":   common.LevelError,
+	"FATAL":   common.LevelFatal,
+}
+
+// comparison compares one entry field with a value
+type comparison struct {
+	field string // canonical field, or a metadata key
+	kind  fieldKind
+	op    string
+	value string
+
+	level   common.LogLevel
+	time    time.Time
+	number  float64
+	numeric bool
+	exact   bool
+	regex   *regexp.Regexp
+}
+
+// newComparison validates and prepares a comparison with the parser's
+// case handling
+func (p *parser) newComparison(field, op, value string) (node, error) {
+	c := &comparison{field: canonicalField(field), op: op, value: value, exact: p.exact}
+
+	if op == "=~" {
+		op = "~"
+		c.op = op
+	}
+	switch op {
+	case "=", "!=", "<", "<=", ">", ">=", "~", "!~":
+	default:
+		return nil, fmt.Errorf("unknown operator %s", op)
+	}
+
+	switch c.field {
+	case "level":
+		c.kind = fieldLevel
+		level, ok := levelNames[strings.ToUpper(value)]
+		if !ok && op != "~" && op != "!~" {
+			return nil, fmt.Errorf("unknown level %s: use DEBUG, INFO, WARN, ERROR or FATAL", value)
+		}
+		c.level = level
+	case "timestamp":
+		c.kind = fieldTime
+		if op != "~" && op != "!~" {
+			t, err := ParseTime(value, p.now)
+			if err != nil {
+				return nil, err
+			}
+			c.time = t
+		}
+	}
+
+	if op == "~" || op == "!~" {
+		expr := value
+		if p.foldRegex {
+			expr = "(?i)" + value
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", value, err)
+		}
+		c.regex = re
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		c.number = number
+		c.numeric = true
+	}
+	return c, nil
+}
+
+// match compares the entry's field value. A field the entry does not have
+// only satisfies != and !~.
+func (c *comparison) match(entry *common.LogEntry) bool {
+	actual, ok := fieldValue(entry, c.field)
+	if !ok {
+		return c.op == "!=" || c.op == "!~"
+	}
+
+	switch c.op {
+	case "~":
+		return c.regex.MatchString(actual)
+	case "!~":
+		return !c.regex.MatchString(actual)
+	}
+
+	order := c.compare(entry, actual)
+	switch c.op {
+	case "=":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	default: // ">="
+		return order >= 0
+	}
+}
+
+// compare orders the entry's value against the comparison value
+func (c *comparison) compare(entry *common.LogEntry, actual string) int {
+	switch c.kind {
+	case fieldLevel:
+		return int(entry.LogLevel) - int(c.level)
+	case fieldTime:
+		return entry.Timestamp.Compare(c.time)
+	}
+
+	if c.numeric {
+		if number, err := strconv.ParseFloat(actual, 64); err == nil {
+			switch {
+			case number < c.number:
+				return -1
+			case number > c.number:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	if c.op == "=" || c.op == "!=" {
+		if actual == c.value || !c.exact && strings.EqualFold(actual, c.value) {
+			return 0
+		}
+		return 1
+	}
+	return strings.Compare(actual, c.value)
+}
+
+// canonicalField resolves field aliases and strips the optional metadata.
+// prefix of metadata keys
+func canonicalField(field string) string {
+	if canonical, ok := fieldAliases[strings.ToLower(field)]; ok {
+		return canonical
+	}
+	return strings.TrimPrefix(field, "metadata.")
+}
+
+// FieldValue returns an entry's value for a field name as accepted in
+// filter expressions, and whether the entry has the field
+func FieldValue(entry *common.LogEntry, field string) (string, bool) {
+	return fieldValue(entry, canonicalField(field))
+}
+
+// fieldValue returns the entry's value for a canonical field as text.
+// Fields the entry leaves empty fall back to metadata and parsed fields of
+// the same name, so service=api also matches JSON logs with a service key.
+func fieldValue(entry *common.LogEntry, field string) (string, bool) {
+	switch field {
+	case "level":
+		return entry.LogLevel.String(), true
+	case "timestamp":
+		if entry.Timestamp.IsZero() {
+			return "", false
+		}
+		return entry.Timestamp.Format(time.RFC3339Nano), true
+	case "message":
+		return entry.Message, true
+	case "line":
+		return strconv.Itoa(entry.LineNumber), true
+	case "raw":
+		if entry.Raw != "" {
+			return entry.Raw, true
+		}
+		return entry.Message, true
+	case "service":
+		if entry.Service != "" {
+			return entry.Service, true
+		}
+	case "trace_id":
+		if entry.TraceID != "" {
+			return entry.TraceID, true
+		}
+	case "source":
+		if entry.Source != "" {
+			return entry.Source, true
+		}
+	}
+
+	if value, ok := entry.Metadata[field]; ok {
+		return value, true
+	}
+	if value, ok := entry.Fields[field]; ok && value != nil {
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
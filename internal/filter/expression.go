@@ -0,0 +1,392 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// Expression is a parsed --where filter such as
+// `service=payments AND level>=WARN`. Comparisons can be combined with
+// AND, OR and NOT (or &&, || and !) and grouped with parentheses. A field
+// can also be tested against a list, as in `service in [api, gateway]`.
+type Expression struct {
+	text string
+	root node
+}
+
+// node is an element of the expression tree
+type node interface {
+	match(entry *common.LogEntry) bool
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ inner node }
+
+func (n andNode) match(entry *common.LogEntry) bool {
+	return n.left.match(entry) && n.right.match(entry)
+}
+
+func (n orNode) match(entry *common.LogEntry) bool {
+	return n.left.match(entry) || n.right.match(entry)
+}
+
+func (n notNode) match(entry *common.LogEntry) bool {
+	return !n.inner.match(entry)
+}
+
+// Parse parses a filter expression. Relative times in timestamp
+// comparisons are resolved against the current time. Equality ignores
+// case and regexes match as written.
+func Parse(text string) (*Expression, error) {
+	return parse(text, &parser{now: time.Now()})
+}
+
+// ParseCase parses a filter expression whose equality and regex
+// comparisons all either respect or ignore case, as pattern conditions do
+func ParseCase(text string, caseSensitive bool) (*Expression, error) {
+	return parse(text, &parser{now: time.Now(), exact: caseSensitive, foldRegex: !caseSensitive})
+}
+
+// parse tokenizes and parses text with a prepared parser
+func parse(text string, p *parser) (*Expression, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+
+	p.tokens = tokens
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q in filter expression", p.peek().text)
+	}
+	return &Expression{text: text, root: root}, nil
+}
+
+// Match reports whether an entry satisfies the expression
+func (e *Expression) Match(entry *common.LogEntry) bool {
+	return e.root.match(entry)
+}
+
+// String returns the expression as written
+func (e *Expression) String() string {
+	return e.text
+}
+
+// tokenKind classifies expression tokens
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOperator
+	tokenOpen
+	tokenClose
+	tokenListOpen
+	tokenListClose
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// operators are the comparison and logical symbols, longest first
+var operators = []string{"=~", "!=", ">=", "<=", "!~", "&&", "||", "=", ">", "<", "~", "!"}
+
+// tokenize splits an expression into words, quoted strings, operators,
+// parentheses and bracketed lists. Commas only separate words inside a
+// list, so unquoted regexes such as \d{1,3} keep working, and brackets
+// right after a regex operator are character classes, as in 5[0-9][0-9].
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	inList := false
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '[' && !inList && followsRegexOperator(tokens):
+			end := regexWordEnd(text, i)
+			tokens = append(tokens, token{kind: tokenWord, text: text[i:end]})
+			i = end
+		case c == '[' && !inList:
+			tokens = append(tokens, token{kind: tokenListOpen, text: "["})
+			inList = true
+			i++
+		case c == ']' && inList:
+			tokens = append(tokens, token{kind: tokenListClose, text: "]"})
+			inList = false
+			i++
+		case c == ',' && inList:
+			tokens = append(tokens, token{kind: tokenComma, text: ","})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			var value strings.Builder
+			for end < len(text) && text[end] != c {
+				// Only the quote and backslash are escaped, so regexes
+				// such as "5\d\d" keep their backslashes
+				if text[end] == '\\' && end+1 < len(text) && (text[end+1] == c || text[end+1] == '\\') {
+					end++
+				}
+				value.WriteByte(text[end])
+				end++
+			}
+			if end >= len(text) {
+				return nil, fmt.Errorf("unterminated string in filter expression")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value.String()})
+			i = end + 1
+		default:
+			if op := operatorAt(text[i:]); op != "" {
+				tokens = append(tokens, token{kind: tokenOperator, text: op})
+				i += len(op)
+				continue
+			}
+			if !inList && followsRegexOperator(tokens) {
+				end := regexWordEnd(text, i)
+				tokens = append(tokens, token{kind: tokenWord, text: text[i:end]})
+				i = end
+				continue
+			}
+			end := i
+			for end < len(text) && !unicode.IsSpace(rune(text[end])) && !strings.ContainsRune("()=!<>~&|\"'", rune(text[end])) {
+				if inList && (text[end] == ',' || text[end] == ']') {
+					break
+				}
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("unexpected %q in filter expression", c)
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: text[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// followsRegexOperator reports whether the last token is =~, ~ or !~, so
+// the next word is a regex
+func followsRegexOperator(tokens []token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	return last.kind == tokenOperator && (last.text == "=~" || last.text == "~" || last.text == "!~")
+}
+
+// regexWordEnd returns the end of the unquoted regex starting at text[i].
+// It ends like any word, except that an alternation such as timeout|refused
+// and a bracketed character class are kept whole, the class including any
+// spaces or operator characters inside it.
+func regexWordEnd(text string, i int) int {
+	end := i
+	for end < len(text) && !unicode.IsSpace(rune(text[end])) && !strings.ContainsRune("()=!<>~&\"'", rune(text[end])) && !strings.HasPrefix(text[end:], "||") {
+		if text[end] == '[' {
+			// A ] right after [ or [^ is a literal member of the class
+			close := end + 1
+			if close < len(text) && text[close] == '^' {
+				close++
+			}
+			if close < len(text) && text[close] == ']' {
+				close++
+			}
+			if next := strings.IndexByte(text[close:], ']'); next >= 0 {
+				end = close + next + 1
+				continue
+			}
+		}
+		end++
+	}
+	return end
+}
+
+// operatorAt returns the operator at the start of text, if any
+func operatorAt(text string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(text, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// parser is a recursive descent parser over expression tokens
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time
+
+	exact     bool // = and != respect case
+	foldRegex bool // regexes ignore case
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	p.pos++
+	return t
+}
+
+// accept consumes the next token if it is one of the given keywords or
+// operators
+func (p *parser) accept(words ...string) bool {
+	if p.done() {
+		return false
+	}
+	t := p.peek()
+	if t.kind != tokenWord && t.kind != tokenOperator {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(t.text, word) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR", "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND", "&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.done() {
+		return nil, fmt.Errorf("incomplete filter expression")
+	}
+	if p.accept("NOT", "!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	if p.peek().kind == tokenOpen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.next().kind != tokenClose {
+			return nil, fmt.Errorf("missing ) in filter expression")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	field := p.next()
+	if field.kind != tokenWord {
+		return nil, fmt.Errorf("expected a field name, got %q", field.text)
+	}
+	if p.accept("IN") {
+		return p.parseList(field.text, false)
+	}
+	if !p.done() && p.peek().kind == tokenWord && strings.EqualFold(p.peek().text, "NOT") {
+		p.next()
+		if !p.accept("IN") {
+			return nil, fmt.Errorf("expected in after %s not", field.text)
+		}
+		return p.parseList(field.text, true)
+	}
+	if p.done() || p.peek().kind != tokenOperator {
+		return nil, fmt.Errorf("expected a comparison after %s", field.text)
+	}
+	op := p.next().text
+	if p.done() || (p.peek().kind != tokenWord && p.peek().kind != tokenString) {
+		return nil, fmt.Errorf("expected a value after %s%s", field.text, op)
+	}
+	return p.newComparison(field.text, op, p.next().text)
+}
+
+// parseList parses the bracketed values of `field in [a, b]`, which
+// matches like field=a OR field=b
+func (p *parser) parseList(field string, negate bool) (node, error) {
+	if p.done() || p.next().kind != tokenListOpen {
+		return nil, fmt.Errorf("expected [ after %s in", field)
+	}
+
+	var list node
+	for {
+		if p.done() || (p.peek().kind != tokenWord && p.peek().kind != tokenString) {
+			return nil, fmt.Errorf("expected a value in the list of %s", field)
+		}
+		c, err := p.newComparison(field, "=", p.next().text)
+		if err != nil {
+			return nil, err
+		}
+		if list == nil {
+			list = c
+		} else {
+			list = orNode{left: list, right: c}
+		}
+
+		if p.done() {
+			return nil, fmt.Errorf("missing ] in the list of %s", field)
+		}
+		if t := p.next(); t.kind == tokenListClose {
+			break
+		} else if t.kind != tokenComma {
+			return nil, fmt.Errorf("expected , or ] in the list of %s, got %q", field, t.text)
+		}
+	}
+
+	if negate {
+		return notNode{inner: list}, nil
+	}
+	return list, nil
+}
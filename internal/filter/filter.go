@@ -0,0 +1,70 @@
+// Package filter selects log entries by time range and field expression
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// Filter keeps entries within a time range that match an expression. Zero
+// bounds and a nil expression do not restrict entries.
+type Filter struct {
+	Since time.Time
+	Until time.Time
+	Where *Expression
+}
+
+// New builds a filter from --since, --until and --where values, any of
+// which may be empty
+func New(since, until, where string, now time.Time) (*Filter, error) {
+	f := &Filter{}
+	if since != "" {
+		t, err := ParseTime(since, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --since: %w", err)
+		}
+		f.Since = t
+	}
+	if until != "" {
+		t, err := ParseTime(until, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --until: %w", err)
+		}
+		f.Until = t
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return nil, fmt.Errorf("--until %s is before --since %s", f.Until.Format(time.RFC3339), f.Since.Format(time.RFC3339))
+	}
+	if where != "" {
+		expr, err := Parse(where)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --where: %w", err)
+		}
+		f.Where = expr
+	}
+	return f, nil
+}
+
+// Active reports whether the filter restricts entries at all
+func (f *Filter) Active() bool {
+	return f != nil && (!f.Since.IsZero() || !f.Until.IsZero() || f.Where != nil)
+}
+
+// Match reports whether an entry passes the filter. Entries without a
+// timestamp never fall within a time range.
+func (f *Filter) Match(entry *common.LogEntry) bool {
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		if entry.Timestamp.IsZero() {
+			return false
+		}
+		if !f.Since.IsZero() && entry.Timestamp.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && entry.Timestamp.After(f.Until) {
+			return false
+		}
+	}
+	return f.Where == nil || f.Where.Match(entry)
+}
@@ -0,0 +1,200 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+func newEntry(level common.LogLevel, service, message string, timestamp time.Time, metadata map[string]string) *common.LogEntry {
+	return &common.LogEntry{
+		LogEntry:   logparser.LogEntry{Timestamp: timestamp, Message: message},
+		LogLevel:   level,
+		Service:    service,
+		LineNumber: 7,
+		Metadata:   metadata,
+	}
+}
+
+func TestExpressionMatch(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entry := newEntry(common.LevelError, "payments", "card declined: insufficient funds", at,
+		map[string]string{"status": "502", "region": "eu-west"})
+	jsonEntry := newEntry(common.LevelInfo, "", "started", at, nil)
+	jsonEntry.Fields = map[string]interface{}{"service": "users", "latency_ms": 87.5}
+
+	tests := []struct {
+		expr  string
+		entry *common.LogEntry
+		want  bool
+	}{
+		{"service=payments AND level>=WARN", entry, true},
+		{"service=payments AND level>ERROR", entry, false},
+		{"service = PAYMENTS", entry, true},
+		{"service!=payments OR level=error", entry, true},
+		{"NOT service=payments", entry, false},
+		{"!(level<WARN) && region=eu-west", entry, true},
+		{"message~'insufficient fun'", entry, true},
+		{`message !~ "^card"`, entry, false},
+		{"status>=500 AND status<600", entry, true},
+		{"metadata.status=502", entry, true},
+		{"line=7", entry, true},
+		{"missing=x", entry, false},
+		{"missing!=x", entry, true},
+		{"timestamp>=2024-01-15T09:00:00Z AND time<'2024-01-15 11:00'", entry, true},
+		{"timestamp>2024-01-15T10:00:00Z", entry, false},
+		{"service=users AND latency_ms>50", jsonEntry, true},
+		{"level=info OR (level=error AND service=payments)", jsonEntry, true},
+		{`metadata.status =~ 5\d\d`, entry, true},
+		{`status~^\d{1,3}$`, entry, true},
+		{`status~"^5\d\d$" AND message~'can\'t|declined'`, entry, true},
+		{"status =~ 5[0-9][0-9] AND service in [payments]", entry, true},
+		{"region ~ ^[a-z]+-[^ ]+$", entry, true},
+		{"message~timeout|declined AND status=502", entry, true},
+		{"message~timeout||status=502", entry, true},
+		{"status !~ [34]0[0-9]", entry, true},
+		{"service in [api, Payments] AND level >= ERROR", entry, true},
+		{"service in [api,'gateway']", entry, false},
+		{"region not in [us-east] AND NOT level in [DEBUG, INFO]", entry, true},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.expr, err)
+		}
+		if got := expr.Match(tt.entry); got != tt.want {
+			t.Errorf("Parse(%q).Match() = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalidExpression(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"service",
+		"service=",
+		"level>=LOUD",
+		"(service=api",
+		"service=api AND",
+		"message~'('",
+		"service=api service=web",
+		"message='unterminated",
+		"timestamp>whenever",
+		"service in api",
+		"service in [api",
+		"service in [api gateway]",
+		"level in [LOUD]",
+		"service not [api]",
+		"service=api | level=ERROR",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"now", now},
+		{"2h", now.Add(-2 * time.Hour)},
+		{"-90m", now.Add(-90 * time.Minute)},
+		{"3d ago", now.AddDate(0, 0, -3)},
+		{"1w12h", now.Add(-(7*24 + 12) * time.Hour)},
+		{"today", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"09:15", time.Date(2024, 1, 15, 9, 15, 0, 0, time.UTC)},
+		{"2024-01-10", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-10 08:00", time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)},
+		{"2024-01-10T08:00:00+02:00", time.Date(2024, 1, 10, 6, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, now)
+		if err != nil {
+			t.Fatalf("ParseTime(%q) failed: %v", tt.value, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	// Every form is an instant on the clock of now, wherever that is
+	cest := time.FixedZone("CEST", 2*60*60)
+	local := time.Date(2024, 1, 15, 14, 30, 0, 0, cest)
+	for value, want := range map[string]time.Time{
+		"now":                  local,
+		"2h":                   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		"12:30":                time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		"3d ago":               local.AddDate(0, 0, -3),
+		"today":                time.Date(2024, 1, 14, 22, 0, 0, 0, time.UTC),
+		"2024-01-15":           time.Date(2024, 1, 14, 22, 0, 0, 0, time.UTC),
+		"2024-01-15T12:30:00Z": time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC),
+	} {
+		got, err := ParseTime(value, local)
+		if err != nil {
+			t.Fatalf("ParseTime(%q) failed: %v", value, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseTime(%q) at %v = %v, want %v", value, local, got, want)
+		}
+	}
+
+	if _, err := ParseTime("last tuesday", now); err == nil {
+		t.Error("ParseTime(\"last tuesday\") succeeded, want error")
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	f, err := New("2024-01-15 10:00", "1h", "level>=WARN", now)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		entry *common.LogEntry
+		want  bool
+	}{
+		{"in range", newEntry(common.LevelError, "", "x", now.Add(-2*time.Hour), nil), true},
+		{"before since", newEntry(common.LevelError, "", "x", now.Add(-6*time.Hour), nil), false},
+		{"after until", newEntry(common.LevelError, "", "x", now.Add(-30*time.Minute), nil), false},
+		{"below level", newEntry(common.LevelInfo, "", "x", now.Add(-2*time.Hour), nil), false},
+		{"no timestamp", newEntry(common.LevelError, "", "x", time.Time{}, nil), false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(tt.entry); got != tt.want {
+			t.Errorf("%s: Match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Relative and clock times agree away from UTC, and entries with a
+	// zone are compared as instants
+	cest := time.FixedZone("CEST", 2*60*60)
+	local := time.Date(2024, 1, 15, 14, 30, 0, 0, cest)
+	for _, since := range []string{"2h", "12:30"} {
+		f, err := New(since, "now", "", local)
+		if err != nil {
+			t.Fatalf("New(%q) failed: %v", since, err)
+		}
+		inside := newEntry(common.LevelInfo, "", "x", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC), nil)
+		outside := newEntry(common.LevelInfo, "", "x", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), nil)
+		if !f.Match(inside) || f.Match(outside) {
+			t.Errorf("--since %s at %v: Match(11:00Z) = %v, Match(10:00Z) = %v, want true, false", since, local, f.Match(inside), f.Match(outside))
+		}
+	}
+
+	if _, err := New("1h", "2h", "", now); err == nil {
+		t.Error("New() with --until before --since succeeded, want error")
+	}
+	if f, err := New("", "", "", now); err != nil || f.Active() {
+		t.Errorf("New() with no bounds = %+v, %v, want inactive filter", f, err)
+	}
+}
@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// absoluteLayouts are the accepted absolute time formats. Times without a
+// zone are taken in the zone of the current time, normally the local one.
+var absoluteLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// clockLayouts are times of day, taken as today
+var clockLayouts = []string{"15:04:05", "15:04"}
+
+// ParseTime parses an absolute time such as "2024-01-15 14:30" or
+// "14:30", or a time relative to now such as "2h", "-90m", "3d ago",
+// "now", "today" or "yesterday". Every form is an instant: relative times
+// count back from now, and absolute times without a zone, including
+// "today", "yesterday" and times of day, are read on now's clock.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "":
+		return time.Time{}, fmt.Errorf("empty time")
+	case "now":
+		return now, nil
+	case "today":
+		return startOfDay(now), nil
+	case "yesterday":
+		return startOfDay(now).AddDate(0, 0, -1), nil
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
+		}
+	}
+
+	if ago, err := ParseDuration(strings.TrimSuffix(strings.TrimPrefix(value, "-"), " ago")); err == nil {
+		return now.Add(-ago), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a date such as 2024-01-15 14:30, a time of day, or a relative time such as 2h", value)
+}
+
+// ParseDuration extends time.ParseDuration with days (d) and weeks (w),
+// e.g. "3d" or "1w12h"
+func ParseDuration(value string) (time.Duration, error) {
+	var total time.Duration
+	rest := strings.TrimSpace(value)
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		if i := strings.Index(rest, unit.suffix); i > 0 {
+			count, err := strconv.Atoi(rest[:i])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			total += time.Duration(count) * unit.size
+			rest = rest[i+1:]
+		}
+	}
+	if rest == "" {
+		return total, nil
+	}
+
+	d, err := time.ParseDuration(rest)
+	if err != nil {
+		return 0, err
+	}
+	return total + d, nil
+}
+
+// startOfDay returns midnight of now's day on now's clock
+func startOfDay(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
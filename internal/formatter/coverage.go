@@ -0,0 +1,74 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yildizm/LogSum/internal/analyzer"
+)
+
+// FormatCoverage renders a pattern coverage report as text, json or markdown
+func FormatCoverage(format string, report *analyzer.CoverageReport) ([]byte, error) {
+	unmatchedColumns := []string{"count", "level", "signature"}
+	unmatched := make([][]interface{}, len(report.Unmatched))
+	for i, group := range report.Unmatched {
+		unmatched[i] = []interface{}{group.Count, group.Level.String(), group.Signature}
+	}
+	unfiredColumns := []string{"id", "name", "type"}
+	unfired := make([][]interface{}, len(report.Unfired))
+	for i, pattern := range report.Unfired {
+		unfired[i] = []interface{}{pattern.ID, pattern.Name, string(pattern.Type)}
+	}
+
+	switch format {
+	case "json":
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(output, '\n'), nil
+	case "markdown", "md":
+		var b strings.Builder
+		b.WriteString("# Pattern Coverage\n\n")
+		fmt.Fprintf(&b, "%s of WARN/ERROR/FATAL entries matched by a pattern (%d of %d, %d entries read).\n",
+			coveragePercent(report.Coverage), report.Matched, report.Problems, report.Entries)
+		if len(unmatched) > 0 {
+			b.WriteString("\n## Top Unmatched Messages\n\n")
+			b.Write(formatTableMarkdown(unmatchedColumns, unmatched))
+		}
+		if len(unfired) > 0 {
+			b.WriteString("\n## Patterns That Never Fired\n\n")
+			b.Write(formatTableMarkdown(unfiredColumns, unfired))
+		}
+		return []byte(b.String()), nil
+	case "text", "":
+		var b strings.Builder
+		fmt.Fprintf(&b, "Pattern coverage: %s (%d of %d WARN/ERROR/FATAL entries matched, %d entries read)\n",
+			coveragePercent(report.Coverage), report.Matched, report.Problems, report.Entries)
+		if len(unmatched) > 0 {
+			table, err := formatTableText(unmatchedColumns, unmatched)
+			if err != nil {
+				return nil, err
+			}
+			b.WriteString("\nTop unmatched messages:\n")
+			b.Write(table)
+		}
+		if len(unfired) > 0 {
+			table, err := formatTableText(unfiredColumns, unfired)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "\nPatterns that never fired (%d):\n", len(unfired))
+			b.Write(table)
+		}
+		return []byte(b.String()), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// coveragePercent formats a coverage ratio as a percentage
+func coveragePercent(coverage float64) string {
+	return fmt.Sprintf("%.1f%%", coverage*100)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/common"
 )
 
 // csvFormatter formats pattern matches as CSV
@@ -61,6 +62,13 @@ func (f *csvFormatter) Format(analysis *analyzer.Analysis) ([]byte, error) {
 		}
 	}
 
+	// Differences from a baseline follow as a second table
+	if analysis.Baseline != nil {
+		if err := f.writeBaseline(writer, analysis.Baseline); err != nil {
+			return nil, err
+		}
+	}
+
 	writer.Flush()
 	if err := writer.Error(); err != nil {
 		return nil, fmt.Errorf("CSV writer error: %w", err)
@@ -69,6 +77,31 @@ func (f *csvFormatter) Format(analysis *analyzer.Analysis) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// writeBaseline writes the changes from a baseline analysis after a blank
+// line
+func (f *csvFormatter) writeBaseline(writer *csv.Writer, diff *common.BaselineDiff) error {
+	records := [][]string{
+		{},
+		{"Baseline Change", "Key", "Subject", "Baseline", "Current", "Details"},
+	}
+	for _, change := range diff.Changes {
+		records = append(records, []string{
+			string(change.Kind),
+			change.Key,
+			escapeCSVString(change.Subject),
+			fmt.Sprintf("%.4g", change.Baseline),
+			fmt.Sprintf("%.4g", change.Current),
+			change.Description,
+		})
+	}
+	for _, record := range records {
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+	return nil
+}
+
 // formatCSVTime formats time for CSV output
 func formatCSVTime(t time.Time) string {
 	if t.IsZero() {
@@ -19,10 +19,15 @@ func NewJSON() Formatter {
 func (f *jsonFormatter) Format(analysis *analyzer.Analysis) ([]byte, error) {
 	// Create enhanced JSON structure as specified in TASK-007
 	output := &EnhancedJSONOutput{
-		Summary:  createSummary(analysis),
-		Patterns: createPatternOutputs(analysis.Patterns),
-		Insights: createInsightOutputs(analysis.Insights),
-		Timeline: createTimelineOutput(analysis.Timeline),
+		Summary:    createSummary(analysis),
+		Patterns:   createPatternOutputs(analysis.Patterns),
+		Insights:   createInsightOutputs(analysis.Insights),
+		Timeline:   createTimelineOutput(analysis.Timeline),
+		Sources:    analysis.Sources,
+		Templates:  analysis.Templates,
+		Suppressed: analysis.Suppressed,
+		Baseline:   analysis.Baseline,
+		Profile:    analysis.Profile,
 	}
 
 	return json.MarshalIndent(output, "", "  ")
@@ -32,10 +37,18 @@ func (f *jsonFormatter) Format(analysis *analyzer.Analysis) ([]byte, error) {
 
 // EnhancedJSONOutput represents the enhanced JSON structure
 type EnhancedJSONOutput struct {
-	Summary  *SummaryOutput   `json:"summary"`
-	Patterns []*PatternOutput `json:"patterns"`
-	Insights []*InsightOutput `json:"insights"`
-	Timeline *TimelineOutput  `json:"timeline,omitempty"`
+	Summary   *SummaryOutput         `json:"summary"`
+	Patterns  []*PatternOutput       `json:"patterns"`
+	Insights  []*InsightOutput       `json:"insights"`
+	Timeline  *TimelineOutput        `json:"timeline,omitempty"`
+	Sources   []common.SourceSummary `json:"sources,omitempty"`
+	Templates []common.LogTemplate   `json:"templates,omitempty"`
+
+	Suppressed []common.SuppressedMatches `json:"suppressed,omitempty"`
+	Baseline   *common.BaselineDiff       `json:"baseline,omitempty"`
+
+	// Profile lets the saved output serve as a baseline for later analyses
+	Profile *common.AnalysisProfile `json:"profile,omitempty"`
 }
 
 // SummaryOutput represents the summary section
@@ -55,21 +68,25 @@ type TimeRange struct {
 
 // PatternOutput represents enhanced pattern match output
 type PatternOutput struct {
-	Pattern       *common.Pattern    `json:"pattern"`
-	Matches       int                `json:"matches"`
-	FirstSeen     time.Time          `json:"first_seen,omitempty"`
-	LastSeen      time.Time          `json:"last_seen,omitempty"`
-	SampleEntries []*common.LogEntry `json:"sample_entries,omitempty"`
+	Pattern       *common.Pattern             `json:"pattern"`
+	Matches       int                         `json:"matches"`
+	FirstSeen     time.Time                   `json:"first_seen,omitempty"`
+	LastSeen      time.Time                   `json:"last_seen,omitempty"`
+	SampleEntries []*common.LogEntry          `json:"sample_entries,omitempty"`
+	Windows       []common.BurstWindow        `json:"windows,omitempty"`
+	Sequences     []common.SequenceOccurrence `json:"sequences,omitempty"`
+	Captures      map[string][]CaptureValue   `json:"captures,omitempty"` // top values per named regex capture
 }
 
 // InsightOutput represents enhanced insight output
 type InsightOutput struct {
-	Type          string  `json:"type"`
-	Severity      int     `json:"severity"`
-	Title         string  `json:"title"`
-	Description   string  `json:"description"`
-	Confidence    float64 `json:"confidence"`
-	EvidenceCount int     `json:"evidence_count"`
+	Type          string                `json:"type"`
+	Severity      int                   `json:"severity"`
+	Title         string                `json:"title"`
+	Description   string                `json:"description"`
+	Confidence    float64               `json:"confidence"`
+	EvidenceCount int                   `json:"evidence_count"`
+	Anomaly       *common.AnomalyDetail `json:"anomaly,omitempty"`
 }
 
 // TimelineOutput represents timeline data
@@ -110,6 +127,14 @@ func createPatternOutputs(patterns []analyzer.PatternMatch) []*PatternOutput {
 			Matches:   match.Count,
 			FirstSeen: match.FirstSeen,
 			LastSeen:  match.LastSeen,
+			Windows:   match.Windows,
+			Sequences: match.Sequences,
+		}
+		for _, capture := range topCaptures(match, 10) {
+			if output.Captures == nil {
+				output.Captures = make(map[string][]CaptureValue)
+			}
+			output.Captures[capture.Name] = capture.Values
 		}
 
 		// Add first 3 sample entries as specified in TASK-007
@@ -139,6 +164,7 @@ func createInsightOutputs(insights []analyzer.Insight) []*InsightOutput {
 			Description:   insight.Description,
 			Confidence:    insight.Confidence,
 			EvidenceCount: len(insight.Evidence),
+			Anomaly:       insight.Anomaly,
 		}
 
 		outputs = append(outputs, output)
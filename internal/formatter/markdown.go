@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/common"
 )
 
 // markdownFormatter formats output as Markdown
@@ -29,11 +30,31 @@ func (f *markdownFormatter) Format(analysis *analyzer.Analysis) ([]byte, error)
 	// Summary with professional table
 	f.writeSummaryTable(&b, analysis)
 
+	// Differences from a baseline analysis
+	if analysis.Baseline != nil {
+		f.writeBaselineTable(&b, analysis.Baseline)
+	}
+
+	// Per-source breakdown when several files were analyzed
+	if len(analysis.Sources) > 0 {
+		f.writeSourcesTable(&b, analysis)
+	}
+
 	// Detected Patterns with enhanced formatting
 	if len(analysis.Patterns) > 0 {
 		f.writePatternSections(&b, analysis.Patterns)
 	}
 
+	// Matches silenced by suppression rules
+	if len(analysis.Suppressed) > 0 {
+		f.writeSuppressedTable(&b, analysis.Suppressed)
+	}
+
+	// Message templates of entries no pattern matched
+	if len(analysis.Templates) > 0 {
+		f.writeTemplatesTable(&b, analysis.Templates)
+	}
+
 	// Insights with confidence indicators
 	if len(analysis.Insights) > 0 {
 		f.writeInsightSections(&b, analysis.Insights)
@@ -58,10 +79,26 @@ func (f *markdownFormatter) writeTableOfContents(b *strings.Builder, analysis *a
 	b.WriteString("## Table of Contents\n")
 	b.WriteString("- [Summary](#summary)\n")
 
+	if analysis.Baseline != nil {
+		b.WriteString("- [Baseline Comparison](#baseline-comparison)\n")
+	}
+
+	if len(analysis.Sources) > 0 {
+		b.WriteString("- [Sources](#sources)\n")
+	}
+
 	if len(analysis.Patterns) > 0 {
 		b.WriteString("- [Detected Patterns](#detected-patterns)\n")
 	}
 
+	if len(analysis.Suppressed) > 0 {
+		b.WriteString("- [Suppressed Matches](#suppressed-matches)\n")
+	}
+
+	if len(analysis.Templates) > 0 {
+		b.WriteString("- [Message Templates](#message-templates)\n")
+	}
+
 	if len(analysis.Insights) > 0 {
 		b.WriteString("- [Insights](#insights)\n")
 	}
@@ -109,6 +146,47 @@ func (f *markdownFormatter) writeSummaryTable(b *strings.Builder, analysis *anal
 	fmt.Fprintf(b, "| Patterns Detected | %d |\n\n", len(analysis.Patterns))
 }
 
+// writeBaselineTable writes the changes from a baseline analysis
+func (f *markdownFormatter) writeBaselineTable(b *strings.Builder, diff *common.BaselineDiff) {
+	b.WriteString("## Baseline Comparison\n\n")
+	fmt.Fprintf(b, "Compared with %s. Rates are %s.\n\n", baselineSummary(diff), diff.RateUnit)
+
+	if len(diff.Changes) == 0 {
+		b.WriteString("No significant changes.\n\n")
+		return
+	}
+
+	b.WriteString("| Change | Subject | Details |\n")
+	b.WriteString("|--------|---------|---------|\n")
+	for _, change := range diff.Changes {
+		fmt.Fprintf(b, "| %s | %s | %s |\n", baselineChangeLabel(change.Kind),
+			strings.ReplaceAll(change.Subject, "|", "\\|"), change.Description)
+	}
+	b.WriteString("\n")
+}
+
+// writeSourcesTable writes the per-source breakdown table
+func (f *markdownFormatter) writeSourcesTable(b *strings.Builder, analysis *analyzer.Analysis) {
+	b.WriteString("## Sources\n\n")
+
+	b.WriteString("| Source | Entries | Errors | Warnings | Top Patterns |\n")
+	b.WriteString("|--------|---------|--------|----------|--------------|\n")
+	for _, summary := range analysis.Sources {
+		var top []string
+		for _, pattern := range topSourcePatterns(summary, analysis.Patterns, 3) {
+			top = append(top, fmt.Sprintf("%s (%d)", pattern.Name, pattern.Count))
+		}
+		topPatterns := "-"
+		if len(top) > 0 {
+			topPatterns = strings.Join(top, ", ")
+		}
+
+		fmt.Fprintf(b, "| `%s` | %s | %d | %d | %s |\n", summary.Source,
+			formatNumber(summary.TotalEntries), summary.ErrorCount, summary.WarnCount, topPatterns)
+	}
+	b.WriteString("\n")
+}
+
 // writePatternSections writes enhanced pattern sections with samples
 func (f *markdownFormatter) writePatternSections(b *strings.Builder, patterns []analyzer.PatternMatch) {
 	b.WriteString("## Detected Patterns\n\n")
@@ -131,6 +209,30 @@ func (f *markdownFormatter) writePatternSections(b *strings.Builder, patterns []
 			fmt.Fprintf(b, "**Description**: %s\n\n", match.Pattern.Description)
 		}
 
+		// Top values of named regex captures
+		if captures := topCaptures(match, 5); len(captures) > 0 {
+			b.WriteString("**Top values**:\n\n")
+			for _, capture := range captures {
+				fmt.Fprintf(b, "- %s: %s\n", capture.Name, formatCaptureValues(capture.Values))
+			}
+			b.WriteString("\n")
+		}
+
+		// Bursts of threshold patterns
+		if bursts := burstSummary(match); bursts != "" {
+			fmt.Fprintf(b, "**Bursts**: %s\n\n", bursts)
+			for _, window := range match.Windows {
+				fmt.Fprintf(b, "- %s\n", formatBurstWindow(window))
+			}
+			b.WriteString("\n")
+		}
+
+		// Occurrences of sequence patterns, step by step
+		if len(match.Sequences) > 0 {
+			f.writeSequences(b, match)
+			continue
+		}
+
 		// Sample entries
 		if len(match.Matches) > 0 {
 			b.WriteString("Sample entries:\n")
@@ -148,6 +250,58 @@ func (f *markdownFormatter) writePatternSections(b *strings.Builder, patterns []
 	}
 }
 
+// writeSequences writes the first occurrences of a sequence pattern with
+// the entry that matched each step
+func (f *markdownFormatter) writeSequences(b *strings.Builder, match analyzer.PatternMatch) {
+	count := 3
+	if len(match.Sequences) < count {
+		count = len(match.Sequences)
+	}
+	for i, occurrence := range match.Sequences[:count] {
+		fmt.Fprintf(b, "Sequence %d", i+1)
+		if occurrence.Key != "" {
+			fmt.Fprintf(b, " (%s)", occurrence.Key)
+		}
+		b.WriteString(":\n```\n")
+		for _, entry := range occurrence.Entries {
+			fmt.Fprintf(b, "%s %s\n", formatTemplateTime(entry.Timestamp), entry.Message)
+		}
+		if occurrence.Missing {
+			fmt.Fprintf(b, "(no final step within %s)\n", formatWindow(match.Pattern.Sequence.MaxGap))
+		}
+		b.WriteString("```\n\n")
+	}
+}
+
+// writeSuppressedTable writes the matches silenced per rule and pattern
+func (f *markdownFormatter) writeSuppressedTable(b *strings.Builder, suppressed []common.SuppressedMatches) {
+	b.WriteString("## Suppressed Matches\n\n")
+	fmt.Fprintf(b, "%s pattern matches were silenced by suppression rules.\n\n", formatNumber(suppressedTotal(suppressed)))
+
+	b.WriteString("| Rule | Pattern | Count |\n")
+	b.WriteString("|------|---------|-------|\n")
+	for _, s := range suppressed {
+		fmt.Fprintf(b, "| %s | %s | %s |\n", s.Rule, s.Pattern, formatNumber(s.Count))
+	}
+	b.WriteString("\n")
+}
+
+// writeTemplatesTable writes the mined message templates with an example
+// of each
+func (f *markdownFormatter) writeTemplatesTable(b *strings.Builder, templates []common.LogTemplate) {
+	b.WriteString("## Message Templates\n\n")
+	b.WriteString("Entries no pattern matched, clustered by message shape.\n\n")
+
+	b.WriteString("| Template | Count | Level | First Seen | Last Seen |\n")
+	b.WriteString("|----------|-------|-------|------------|-----------|\n")
+	for _, template := range templates {
+		fmt.Fprintf(b, "| `%s` | %s | %s | %s | %s |\n",
+			strings.ReplaceAll(template.Template, "|", "\\|"), formatNumber(template.Count), template.Level,
+			formatTemplateTime(template.FirstSeen), formatTemplateTime(template.LastSeen))
+	}
+	b.WriteString("\n")
+}
+
 // writeInsightSections writes enhanced insight sections
 func (f *markdownFormatter) writeInsightSections(b *strings.Builder, insights []analyzer.Insight) {
 	b.WriteString("## Insights\n\n")
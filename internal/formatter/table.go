@@ -0,0 +1,110 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// FormatTable renders a result table, such as the output of a query, as
+// text, json, markdown or csv. JSON output is an array with one object per
+// row, keyed by column name.
+func FormatTable(format string, columns []string, rows [][]interface{}) ([]byte, error) {
+	switch format {
+	case "json":
+		return formatTableJSON(columns, rows)
+	case "markdown", "md":
+		return formatTableMarkdown(columns, rows), nil
+	case "csv":
+		return formatTableCSV(columns, rows)
+	case "text", "":
+		return formatTableText(columns, rows)
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func formatTableJSON(columns []string, rows [][]interface{}) ([]byte, error) {
+	objects := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		object := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			object[column] = row[i]
+		}
+		objects = append(objects, object)
+	}
+	output, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(output, '\n'), nil
+}
+
+func formatTableMarkdown(columns []string, rows [][]interface{}) []byte {
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat("---|", len(columns)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.ReplaceAll(tableCell(cell), "|", "\\|")
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return []byte(b.String())
+}
+
+func formatTableCSV(columns []string, rows [][]interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	writer := csv.NewWriter(&b)
+	if err := writer.Write(columns); err != nil {
+		return nil, fmt.Errorf("failed to write CSV headers: %w", err)
+	}
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = fmt.Sprint(cell)
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("CSV writer error: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+func formatTableText(columns []string, rows [][]interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	writer := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = tableCell(cell)
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// tableCell renders a cell on a single line, with - for empty values
+func tableCell(cell interface{}) string {
+	text := strings.Join(strings.Fields(fmt.Sprint(cell)), " ")
+	if text == "" {
+		return "-"
+	}
+	return text
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/yildizm/LogSum/internal/analyzer"
+	"github.com/yildizm/LogSum/internal/common"
 	"github.com/yildizm/go-termfmt"
 )
 
@@ -31,11 +32,31 @@ func (f *terminalFormatter) Format(analysis *analyzer.Analysis) ([]byte, error)
 	// Statistics section with tree view
 	f.writeStatistics(&b, analysis)
 
+	// Differences from a baseline analysis
+	if analysis.Baseline != nil {
+		f.writeBaseline(&b, analysis.Baseline)
+	}
+
+	// Per-source breakdown when several files were analyzed
+	if len(analysis.Sources) > 0 {
+		f.writeSources(&b, analysis)
+	}
+
 	// Top patterns section
 	if len(analysis.Patterns) > 0 {
 		f.writeTopPatterns(&b, analysis.Patterns)
 	}
 
+	// Matches silenced by suppression rules
+	if len(analysis.Suppressed) > 0 {
+		f.writeSuppressed(&b, analysis.Suppressed)
+	}
+
+	// Message templates of entries no pattern matched
+	if len(analysis.Templates) > 0 {
+		f.writeTopTemplates(&b, analysis.Templates)
+	}
+
 	// Key insights section
 	if len(analysis.Insights) > 0 {
 		f.writeKeyInsights(&b, analysis.Insights)
@@ -82,6 +103,53 @@ func (f *terminalFormatter) writeStatistics(b *strings.Builder, analysis *analyz
 	b.WriteString(tree + "\n\n")
 }
 
+// writeBaseline writes the changes from a baseline analysis
+func (f *terminalFormatter) writeBaseline(b *strings.Builder, diff *common.BaselineDiff) {
+	symbol := termfmt.GetEmoji("scale", f.opts)
+	fmt.Fprintf(b, "%s Compared With Baseline (%s)\n", symbol, baselineSummary(diff))
+
+	items := make([]termfmt.TreeItem, 0, len(diff.Changes))
+	if len(diff.Changes) == 0 {
+		items = append(items, termfmt.TreeItem{Label: "No significant changes", Last: true})
+	}
+	for i, change := range diff.Changes {
+		items = append(items, termfmt.TreeItem{
+			Label: fmt.Sprintf("%s: %s", baselineChangeLabel(change.Kind), truncateTemplate(change.Subject, 80)),
+			Value: change.Description,
+			Last:  i == len(diff.Changes)-1,
+		})
+	}
+
+	tree := termfmt.TreeViewWithOptions(items, f.opts)
+	b.WriteString(tree + "\n\n")
+}
+
+// writeSources writes the per-source breakdown with each source's top patterns
+func (f *terminalFormatter) writeSources(b *strings.Builder, analysis *analyzer.Analysis) {
+	symbol := termfmt.GetEmoji("list", f.opts)
+	b.WriteString(symbol + " Sources\n")
+
+	items := make([]termfmt.TreeItem, 0, len(analysis.Sources))
+	for i, summary := range analysis.Sources {
+		item := termfmt.TreeItem{
+			Label: summary.Source,
+			Value: fmt.Sprintf("%s entries, %d errors, %d warnings",
+				formatNumber(summary.TotalEntries), summary.ErrorCount, summary.WarnCount),
+			Last: i == len(analysis.Sources)-1,
+		}
+		for _, pattern := range topSourcePatterns(summary, analysis.Patterns, 3) {
+			item.Children = append(item.Children, termfmt.TreeItem{
+				Label: fmt.Sprintf("%s (%d)", pattern.Name, pattern.Count),
+				Value: "",
+			})
+		}
+		items = append(items, item)
+	}
+
+	tree := termfmt.TreeViewWithOptions(items, f.opts)
+	b.WriteString(tree + "\n\n")
+}
+
 // writeTopPatterns writes top patterns with visual indicators to match original format
 func (f *terminalFormatter) writeTopPatterns(b *strings.Builder, patterns []analyzer.PatternMatch) {
 	// Use fallback symbol to match original exactly
@@ -107,15 +175,65 @@ func (f *terminalFormatter) writeTopPatterns(b *strings.Builder, patterns []anal
 		pattern := sortedPatterns[i]
 		emoji := getPatternEmoji(pattern.Pattern.Type)
 
+		count := fmt.Sprintf("(%d)", pattern.Count)
+		if bursts := burstSummary(pattern); bursts != "" {
+			count = fmt.Sprintf("(%d, %s)", pattern.Count, bursts)
+		}
+
+		branch, indent := "├─", "│  "
 		if i == maxPatterns-1 {
-			fmt.Fprintf(b, "└─ %s %s (%d)\n", emoji, pattern.Pattern.Name, pattern.Count)
-		} else {
-			fmt.Fprintf(b, "├─ %s %s (%d)\n", emoji, pattern.Pattern.Name, pattern.Count)
+			branch, indent = "└─", "   "
+		}
+		fmt.Fprintf(b, "%s %s %s %s\n", branch, emoji, pattern.Pattern.Name, count)
+		for _, capture := range topCaptures(pattern, 3) {
+			fmt.Fprintf(b, "%s   %s: %s\n", indent, capture.Name, formatCaptureValues(capture.Values))
 		}
 	}
 	b.WriteString("\n")
 }
 
+// writeSuppressed writes the matches silenced per rule and pattern
+func (f *terminalFormatter) writeSuppressed(b *strings.Builder, suppressed []common.SuppressedMatches) {
+	symbol := termfmt.GetEmoji("info", f.opts)
+	fmt.Fprintf(b, "%s Suppressed Matches (%d)\n", symbol, suppressedTotal(suppressed))
+
+	items := make([]termfmt.TreeItem, 0, len(suppressed))
+	for i, s := range suppressed {
+		items = append(items, termfmt.TreeItem{
+			Label: fmt.Sprintf("%s → %s", s.Rule, s.Pattern),
+			Value: fmt.Sprintf("(%d)", s.Count),
+			Last:  i == len(suppressed)-1,
+		})
+	}
+
+	tree := termfmt.TreeViewWithOptions(items, f.opts)
+	b.WriteString(tree + "\n\n")
+}
+
+// writeTopTemplates writes the most frequent mined message templates
+func (f *terminalFormatter) writeTopTemplates(b *strings.Builder, templates []common.LogTemplate) {
+	symbol := termfmt.GetEmoji("list", f.opts)
+	b.WriteString(symbol + " Top Templates\n")
+
+	maxTemplates := 5
+	if len(templates) < maxTemplates {
+		maxTemplates = len(templates)
+	}
+
+	items := make([]termfmt.TreeItem, 0, maxTemplates)
+	for i := 0; i < maxTemplates; i++ {
+		template := templates[i]
+		items = append(items, termfmt.TreeItem{
+			Label: fmt.Sprintf("%s %s", getSeverityEmoji(template.Level), truncateTemplate(template.Template, 80)),
+			Value: fmt.Sprintf("(%d)", template.Count),
+			Last:  i == maxTemplates-1,
+		})
+	}
+
+	tree := termfmt.TreeViewWithOptions(items, f.opts)
+	b.WriteString(tree + "\n\n")
+}
+
 // writeKeyInsights writes key insights with confidence indicators using go-termfmt
 func (f *terminalFormatter) writeKeyInsights(b *strings.Builder, insights []analyzer.Insight) {
 	symbol := termfmt.GetEmoji("insights", f.opts)
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/yildizm/LogSum/internal/analyzer"
 	"github.com/yildizm/LogSum/internal/common"
 )
 
@@ -104,3 +105,40 @@ func TestWriteTopPatterns_EmptyInput(t *testing.T) {
 		t.Errorf("Should only have header line with empty input, got %d lines", len(lines))
 	}
 }
+
+func TestBaselineSection(t *testing.T) {
+	analysis := &analyzer.Analysis{
+		TotalEntries: 10,
+		Patterns:     []common.PatternMatch{},
+		Baseline: &common.BaselineDiff{
+			Baseline:        "yesterday.log",
+			BaselineEntries: 20,
+			RateUnit:        "per minute",
+			Changes: []common.BaselineChange{{
+				Kind:        common.BaselineChangeNewError,
+				Key:         "ConnectionError|db down|level:ERROR",
+				Subject:     "db down",
+				Current:     3,
+				Description: "3 times, never in the baseline",
+			}},
+		},
+	}
+
+	formatters := map[string]Formatter{
+		"text":     NewTerminal(false),
+		"markdown": NewMarkdown(),
+		"json":     NewJSON(),
+		"csv":      NewCSV(),
+	}
+	for name, f := range formatters {
+		output, err := f.Format(analysis)
+		if err != nil {
+			t.Fatalf("%s: Format() failed: %v", name, err)
+		}
+		for _, want := range []string{"db down", "3 times, never in the baseline"} {
+			if !strings.Contains(string(output), want) {
+				t.Errorf("%s output has no %q:\n%s", name, want, output)
+			}
+		}
+	}
+}
@@ -2,6 +2,9 @@ package formatter
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/yildizm/LogSum/internal/analyzer"
 	"github.com/yildizm/LogSum/internal/common"
@@ -102,3 +105,168 @@ func generateRecommendations(analysis *analyzer.Analysis) []string {
 
 	return recommendations
 }
+
+// namedCount is a pattern name with its match count
+type namedCount struct {
+	Name  string
+	Count int
+}
+
+// topSourcePatterns returns the most frequent patterns of a source, using
+// pattern names from the analysis where available
+func topSourcePatterns(summary common.SourceSummary, patterns []analyzer.PatternMatch, limit int) []namedCount {
+	names := make(map[string]string, len(patterns))
+	for _, match := range patterns {
+		names[match.Pattern.ID] = match.Pattern.Name
+	}
+
+	counts := make([]namedCount, 0, len(summary.PatternCounts))
+	for id, count := range summary.PatternCounts {
+		name := names[id]
+		if name == "" {
+			name = id
+		}
+		counts = append(counts, namedCount{Name: name, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts
+}
+
+// truncateTemplate shortens a template to at most maxLen characters
+func truncateTemplate(template string, maxLen int) string {
+	if len(template) <= maxLen {
+		return template
+	}
+	return template[:maxLen-3] + "..."
+}
+
+// formatTemplateTime formats when a template was seen, or - if unknown
+func formatTemplateTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("15:04:05")
+}
+
+// burstSummary describes the bursts of a threshold pattern match, or
+// returns "" for ordinary patterns
+func burstSummary(match analyzer.PatternMatch) string {
+	threshold := match.Pattern.Threshold
+	if threshold == nil || len(match.Windows) == 0 {
+		return ""
+	}
+	noun := "burst"
+	if len(match.Windows) > 1 {
+		noun = "bursts"
+	}
+	return fmt.Sprintf("%d %s of %d+ in %s", len(match.Windows), noun, threshold.Count, formatWindow(threshold.Window))
+}
+
+// formatBurstWindow describes one burst of a threshold pattern
+func formatBurstWindow(window common.BurstWindow) string {
+	text := fmt.Sprintf("%s-%s: %d entries", window.Start.Format("15:04:05"), window.End.Format("15:04:05"), window.Count)
+	if window.Group != "" {
+		text += fmt.Sprintf(" (%s)", window.Group)
+	}
+	return text
+}
+
+// formatWindow formats a window duration without zero units, as 5m
+// rather than 5m0s
+func formatWindow(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// CaptureValue is a value of a regex capture and how often it matched
+type CaptureValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// captureSummary is the most frequent values of one capture
+type captureSummary struct {
+	Name   string
+	Values []CaptureValue
+}
+
+// topCaptures returns the most frequent values of each named capture of
+// a match, captures ordered by name
+func topCaptures(match analyzer.PatternMatch, limit int) []captureSummary {
+	summaries := make([]captureSummary, 0, len(match.Captures))
+	for name, counts := range match.Captures {
+		values := make([]CaptureValue, 0, len(counts))
+		for value, count := range counts {
+			values = append(values, CaptureValue{Value: value, Count: count})
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].Count != values[j].Count {
+				return values[i].Count > values[j].Count
+			}
+			return values[i].Value < values[j].Value
+		})
+		if len(values) > limit {
+			values = values[:limit]
+		}
+		summaries = append(summaries, captureSummary{Name: name, Values: values})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries
+}
+
+// formatCaptureValues formats capture values as "a (3), b (1)"
+func formatCaptureValues(values []CaptureValue) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf("%s (%d)", value.Value, value.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// suppressedTotal sums the matches silenced by suppression rules
+func suppressedTotal(suppressed []common.SuppressedMatches) int {
+	total := 0
+	for _, s := range suppressed {
+		total += s.Count
+	}
+	return total
+}
+
+// baselineChangeLabels names the kinds of baseline changes for display
+var baselineChangeLabels = map[common.BaselineChangeKind]string{
+	common.BaselineChangeVolume:        "Volume",
+	common.BaselineChangeNewPattern:    "New pattern",
+	common.BaselineChangePatternRate:   "Pattern rate",
+	common.BaselineChangeNewError:      "New error",
+	common.BaselineChangeServiceErrors: "Service errors",
+}
+
+// baselineChangeLabel names the kind of a baseline change
+func baselineChangeLabel(kind common.BaselineChangeKind) string {
+	if label, ok := baselineChangeLabels[kind]; ok {
+		return label
+	}
+	return string(kind)
+}
+
+// baselineSummary describes the baseline a diff compares against
+func baselineSummary(diff *common.BaselineDiff) string {
+	return fmt.Sprintf("%s: %s entries, %s errors", diff.Baseline,
+		formatNumber(diff.BaselineEntries), formatNumber(diff.BaselineErrors))
+}
@@ -0,0 +1,75 @@
+package ingest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+// accessLogPattern matches the NCSA common and combined access log
+// formats written by nginx and Apache, ignoring any fields appended after
+// the user agent
+var accessLogPattern = regexp.MustCompile(
+	`^(\S+) (\S+) (\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\d+|-)(?: "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)")?`)
+
+const accessLogTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// accessLogFormat parses nginx and Apache access logs. The level follows
+// the response status: 5xx is an error and 4xx a warning.
+type accessLogFormat struct {
+	name string
+}
+
+// Name returns the name the format is selected by
+func (f accessLogFormat) Name() string {
+	return f.name
+}
+
+// ParseLine parses a common or combined log format line
+func (f accessLogFormat) ParseLine(line string) (*common.LogEntry, error) {
+	match := accessLogPattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil, fmt.Errorf("not an access log line")
+	}
+	timestamp, err := time.Parse(accessLogTimeLayout, match[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid access log timestamp: %w", err)
+	}
+
+	request, status := match[5], match[6]
+	parsed := logparser.LogEntry{
+		Timestamp: timestamp,
+		Level:     statusLevel(status),
+		Message:   strings.TrimSpace(request + " " + status),
+	}
+
+	entry := common.ConvertToCommonLogEntry(&parsed, 0)
+	setMetadata(entry, "client", match[1])
+	setMetadata(entry, "user", match[3])
+	setMetadata(entry, "status", status)
+	setMetadata(entry, "bytes", match[7])
+	setMetadata(entry, "referer", match[8])
+	setMetadata(entry, "user_agent", match[9])
+	if parts := strings.Fields(request); len(parts) == 3 {
+		setMetadata(entry, "method", parts[0])
+		setMetadata(entry, "path", parts[1])
+		setMetadata(entry, "protocol", parts[2])
+	}
+	return entry, nil
+}
+
+// statusLevel maps an HTTP status code onto a log level
+func statusLevel(status string) string {
+	switch status[0] {
+	case '5':
+		return "ERROR"
+	case '4':
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}
@@ -0,0 +1,168 @@
+package ingest
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Open returns an entry source for r, transparently decompressing gzip,
+// bzip2 and zstd input. A tar archive yields the entries of all member
+// files merged by timestamp, with member files treated as separate sources
+// named "<options.Source>:<member path>". The returned function releases
+// decoder resources and archive spool files; it does not close r.
+func Open(r io.Reader, options Options) (Source, func(), error) {
+	decompressed, _, err := Decompress(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { _ = decompressed.Close() }
+
+	buffered := bufio.NewReaderSize(decompressed, 64*1024)
+	if isTar(buffered) {
+		archive, err := newArchiveReader(tar.NewReader(buffered), options)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		return archive, func() { archive.close(); cleanup() }, nil
+	}
+
+	reader, err := NewReader(buffered, options)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return reader, cleanup, nil
+}
+
+// ArchiveReader merges the entries of every regular file in a tar archive
+// by timestamp, like several files given on the command line. A tar stream
+// can only be read one member after another, so members are first copied
+// to temporary files, up to MaxLines non-empty lines across the archive,
+// or as many as are left in a shared Budget.
+type ArchiveReader struct {
+	*MergeReader
+	files     []*os.File
+	truncated bool
+}
+
+func newArchiveReader(archive *tar.Reader, options Options) (*ArchiveReader, error) {
+	if options.MaxLineLength <= 0 {
+		options.MaxLineLength = DefaultMaxLineLength
+	}
+	a := &ArchiveReader{}
+	budget := options.lineBudget()
+
+	var sources []Source
+	for !a.truncated {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			a.close()
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		file, err := a.spoolMember(archive, header.Name, budget, options.MaxLineLength)
+		if err != nil {
+			a.close()
+			return nil, err
+		}
+
+		memberOptions := options
+		memberOptions.Source = memberName(options.Source, header.Name)
+		memberOptions.MaxLines = 0 // already applied while spooling
+		memberOptions.Budget = nil
+		reader, err := NewReader(file, memberOptions)
+		if err != nil {
+			a.close()
+			return nil, err
+		}
+		sources = append(sources, reader)
+	}
+
+	a.MergeReader = NewMergeReader(sources...)
+	return a, nil
+}
+
+// spoolMember copies the current member, decompressing it if needed, to a
+// temporary file, drawing the non-empty lines it copies from budget
+func (a *ArchiveReader) spoolMember(archive *tar.Reader, name string, budget *LineBudget, maxLineLength int) (*os.File, error) {
+	// Members of support bundles are often compressed themselves
+	member, _, err := Decompress(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive member %s: %w", name, err)
+	}
+	defer func() { _ = member.Close() }()
+
+	file, err := os.CreateTemp("", "logsum-archive-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool archive member %s: %w", name, err)
+	}
+	a.files = append(a.files, file)
+
+	if err := a.copyLines(file, member, budget, maxLineLength); err != nil {
+		return nil, fmt.Errorf("failed to spool archive member %s: %w", name, err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to spool archive member %s: %w", name, err)
+	}
+	return file, nil
+}
+
+// copyLines copies lines from r to w until r is exhausted or a non-empty
+// line beyond the budget is reached
+func (a *ArchiveReader) copyLines(w io.Writer, r io.Reader, budget *LineBudget, maxLineLength int) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	writer := bufio.NewWriter(w)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) != "" && !budget.take() {
+			a.truncated = true
+			break
+		}
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// close removes the spool files
+func (a *ArchiveReader) close() {
+	for _, file := range a.files {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}
+	a.files = nil
+}
+
+// memberName names a member source after the archive and the member path
+func memberName(archive, member string) string {
+	member = path.Clean(member)
+	if archive == "" {
+		return member
+	}
+	return archive + ":" + member
+}
+
+// Truncated reports whether part of the archive was left unread because
+// of MaxLines or a spent Budget
+func (a *ArchiveReader) Truncated() bool {
+	return a.truncated
+}
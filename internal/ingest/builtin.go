@@ -0,0 +1,89 @@
+package ingest
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+// builtinFormats are the named line formats that ship with LogSum, keyed by
+// every name they can be selected with
+var builtinFormats = map[string]LineFormat{
+	"combined": accessLogFormat{name: "combined"},
+	"nginx":    accessLogFormat{name: "combined"},
+	"apache":   accessLogFormat{name: "combined"},
+	"syslog":   newSyslogFormat(),
+	"journald": journaldFormat{},
+	"docker":   dockerFormat{},
+	"cri":      criFormat{},
+}
+
+// detectableFormats are the built-in formats tried by auto-detection, most
+// specific first. Generic JSON, logfmt and text are only used when none of
+// them fits.
+var detectableFormats = []string{"journald", "docker", "cri", "syslog", "combined"}
+
+// sourceAnnotator is implemented by formats that derive entry fields from
+// the name of the input, such as the pod a container log belongs to
+type sourceAnnotator interface {
+	annotateSource(entry *common.LogEntry, source string)
+}
+
+// BuiltinFormatNames returns the names built-in line formats can be
+// selected with
+func BuiltinFormatNames() []string {
+	names := make([]string, 0, len(builtinFormats))
+	for name := range builtinFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// detectLineFormat returns the built-in format that parses most of the
+// sample lines, or nil when none parses more than half of them
+func detectLineFormat(samples []string) LineFormat {
+	for _, name := range detectableFormats {
+		format := builtinFormats[name]
+		parsed := 0
+		for _, sample := range samples {
+			if _, err := format.ParseLine(sample); err == nil {
+				parsed++
+			}
+		}
+		if len(samples) > 0 && parsed > len(samples)/2 {
+			return format
+		}
+	}
+	return nil
+}
+
+// messageLevelPatterns find a level keyword near the start of a message
+// that a wrapping format such as syslog or a container runtime carries
+// without a level of its own
+var messageLevelPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)"(?:level|severity|lvl)"\s*:\s*"(\w+)"`),
+	regexp.MustCompile(`(?i)\b(?:level|severity|lvl)=(\w+)`),
+	regexp.MustCompile(`(?i)^(?:\S+\s+){0,3}?[\[(<]?(trace|debug|info|notice|warn|warning|error|err|crit|critical|fatal|panic)[\])>:]?(?:\s|$)`),
+}
+
+// levelFromMessage returns the level named in a message, or INFO
+func levelFromMessage(message string) string {
+	for _, re := range messageLevelPatterns {
+		if match := re.FindStringSubmatch(message); match != nil {
+			return normalizeLevel(match[1])
+		}
+	}
+	return normalizeLevel("info")
+}
+
+// setMetadata stores a value unless it is empty or a "-" placeholder
+func setMetadata(entry *common.LogEntry, key, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "-" {
+		return
+	}
+	entry.Metadata[key] = value
+}
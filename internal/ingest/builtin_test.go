@@ -0,0 +1,219 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func TestBuiltinFormatsParseLine(t *testing.T) {
+	tests := []struct {
+		format    string
+		line      string
+		message   string
+		level     common.LogLevel
+		service   string
+		timestamp time.Time
+		metadata  map[string]string
+	}{
+		{
+			format:    "nginx",
+			line:      `10.0.0.1 - alice [01/Jan/2024:10:00:00 +0000] "GET /api/users HTTP/1.1" 502 157 "-" "curl/8.0"`,
+			message:   "GET /api/users HTTP/1.1 502",
+			level:     common.LevelError,
+			timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			metadata: map[string]string{
+				"client": "10.0.0.1", "user": "alice", "status": "502", "method": "GET",
+				"path": "/api/users", "user_agent": "curl/8.0",
+			},
+		},
+		{
+			format:    "apache",
+			line:      `192.168.1.5 - - [01/Jan/2024:10:00:00 +0000] "POST /login HTTP/1.1" 404 -`,
+			message:   "POST /login HTTP/1.1 404",
+			level:     common.LevelWarn,
+			timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			metadata:  map[string]string{"status": "404", "method": "POST"},
+		},
+		{
+			format:    "syslog",
+			line:      `<11>1 2024-01-01T10:00:00.000Z web01 billing 4242 CHARGE - payment declined`,
+			message:   "payment declined",
+			level:     common.LevelError,
+			service:   "billing",
+			timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			metadata:  map[string]string{"hostname": "web01", "pid": "4242", "severity": "err", "facility": "user"},
+		},
+		{
+			format:    "syslog",
+			line:      `<36>Jan  1 10:00:00 web01 sshd[812]: Invalid user admin`,
+			message:   "Invalid user admin",
+			level:     common.LevelWarn,
+			service:   "sshd",
+			timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			metadata:  map[string]string{"hostname": "web01", "pid": "812", "severity": "warning", "facility": "auth"},
+		},
+		{
+			format:    "syslog",
+			line:      `Jan  1 10:00:00 web01 kernel: error: disk failure on sda`,
+			message:   "error: disk failure on sda",
+			level:     common.LevelError,
+			service:   "kernel",
+			timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			metadata:  map[string]string{"hostname": "web01"},
+		},
+		{
+			format:    "journald",
+			line:      `{"__REALTIME_TIMESTAMP":"1704103200000000","PRIORITY":"2","MESSAGE":"Out of memory","SYSLOG_IDENTIFIER":"kernel","_HOSTNAME":"web01"}`,
+			message:   "Out of memory",
+			level:     common.LevelError,
+			service:   "kernel",
+			timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			metadata:  map[string]string{"hostname": "web01"},
+		},
+		{
+			format:    "docker",
+			line:      `{"log":"level=error msg=\"db down\"\n","stream":"stderr","time":"2024-01-01T10:00:00.123Z","attrs":{"tag":"api"}}`,
+			message:   `level=error msg="db down"`,
+			level:     common.LevelError,
+			service:   "api",
+			timestamp: time.Date(2024, 1, 1, 10, 0, 0, 123000000, time.UTC),
+			metadata:  map[string]string{"stream": "stderr", "tag": "api"},
+		},
+		{
+			format:    "cri",
+			line:      `2024-01-01T10:00:00.5Z stdout F [WARN] cache miss rate high`,
+			message:   "[WARN] cache miss rate high",
+			level:     common.LevelWarn,
+			timestamp: time.Date(2024, 1, 1, 10, 0, 0, 500000000, time.UTC),
+			metadata:  map[string]string{"stream": "stdout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			format := builtinFormats[tt.format]
+			if syslog, ok := format.(syslogFormat); ok {
+				syslog.now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+				format = syslog
+			}
+
+			entry, err := format.ParseLine(tt.line)
+			if err != nil {
+				t.Fatalf("ParseLine() failed: %v", err)
+			}
+			if entry.Message != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, entry.Message)
+			}
+			if entry.LogLevel != tt.level {
+				t.Errorf("Expected level %s, got %s", tt.level, entry.LogLevel)
+			}
+			if entry.Service != tt.service {
+				t.Errorf("Expected service %q, got %q", tt.service, entry.Service)
+			}
+			if !entry.Timestamp.Equal(tt.timestamp) {
+				t.Errorf("Expected timestamp %v, got %v", tt.timestamp, entry.Timestamp)
+			}
+			for key, value := range tt.metadata {
+				if entry.Metadata[key] != value {
+					t.Errorf("Expected metadata %s=%q, got %q", key, value, entry.Metadata[key])
+				}
+			}
+		})
+	}
+}
+
+func TestSyslogYearRollover(t *testing.T) {
+	format := syslogFormat{now: func() time.Time { return time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC) }}
+
+	entry, err := format.ParseLine("Dec 31 23:59:00 web01 cron[1]: job done")
+	if err != nil {
+		t.Fatalf("ParseLine() failed: %v", err)
+	}
+	if entry.Timestamp.Year() != 2023 {
+		t.Errorf("Expected December entry in the previous year, got %v", entry.Timestamp)
+	}
+}
+
+func TestDetectLineFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []string
+		expected string
+	}{
+		{"access log", []string{`1.2.3.4 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 5 "-" "x"`}, "combined"},
+		{"syslog", []string{`Jan  1 10:00:00 web01 sshd[812]: Accepted key`}, "syslog"},
+		{"journald", []string{`{"__REALTIME_TIMESTAMP":"1704103200000000","MESSAGE":"hi"}`}, "journald"},
+		{"docker", []string{`{"log":"hi\n","stream":"stdout","time":"2024-01-01T10:00:00Z"}`}, "docker"},
+		{"cri", []string{`2024-01-01T10:00:00Z stdout F hi`}, "cri"},
+		{"plain json", []string{`{"level":"info","msg":"hi"}`}, ""},
+		{"text", []string{`2024-01-01 10:00:00 [INFO] hi`}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if format := detectLineFormat(tt.samples); format != nil {
+				got = format.Name()
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReaderContainerSourceMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		input    string
+		service  string
+		metadata map[string]string
+	}{
+		{
+			name:     "pod log",
+			source:   "/var/log/pods/shop_checkout-7d9f_0f1e/payments/0.log",
+			input:    "2024-01-01T10:00:00Z stderr F ERROR charge failed\n",
+			service:  "payments",
+			metadata: map[string]string{"namespace": "shop", "pod": "checkout-7d9f"},
+		},
+		{
+			name:     "container symlink",
+			source:   "/var/log/containers/checkout-7d9f_shop_payments-" + strings.Repeat("a", 64) + ".log",
+			input:    "2024-01-01T10:00:00Z stderr F ERROR charge failed\n",
+			service:  "payments",
+			metadata: map[string]string{"namespace": "shop", "pod": "checkout-7d9f"},
+		},
+		{
+			name:     "docker json-file",
+			source:   "/var/lib/docker/containers/" + strings.Repeat("b", 64) + "/" + strings.Repeat("b", 64) + "-json.log",
+			input:    `{"log":"ERROR charge failed\n","stream":"stderr","time":"2024-01-01T10:00:00Z"}` + "\n",
+			service:  strings.Repeat("b", 12),
+			metadata: map[string]string{"container_id": strings.Repeat("b", 12)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := readEntries(t, tt.input, Options{Source: tt.source})
+			if len(entries) != 1 {
+				t.Fatalf("Expected 1 entry, got %d", len(entries))
+			}
+			entry := entries[0]
+			if entry.LogLevel != common.LevelError {
+				t.Errorf("Expected error level, got %s", entry.LogLevel)
+			}
+			if entry.Service != tt.service {
+				t.Errorf("Expected service %q, got %q", tt.service, entry.Service)
+			}
+			for key, value := range tt.metadata {
+				if entry.Metadata[key] != value {
+					t.Errorf("Expected metadata %s=%q, got %q", key, value, entry.Metadata[key])
+				}
+			}
+		})
+	}
+}
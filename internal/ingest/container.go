@@ -0,0 +1,147 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+var (
+	// criPattern matches TIMESTAMP STREAM TAG MESSAGE, where the tag is F
+	// for a full line and P for part of a longer one
+	criPattern = regexp.MustCompile(`^(\S+) (stdout|stderr) ([FP])(?::\S*)? ?(.*)$`)
+
+	// podLogPath matches /var/log/pods/<namespace>_<pod>_<uid>/<container>/<n>.log
+	podLogPath = regexp.MustCompile(`(?:^|[/:])pods/([^_/]+)_([^_/]+)_[^/]+/([^/]+)/\d+\.log`)
+
+	// containerLogPath matches /var/log/containers/<pod>_<namespace>_<container>-<id>.log
+	containerLogPath = regexp.MustCompile(`(?:^|[/:])containers/([^_/]+)_([^_/]+)_(.+)-[0-9a-f]{64}\.log`)
+
+	// dockerLogPath matches /var/lib/docker/containers/<id>/<id>-json.log
+	dockerLogPath = regexp.MustCompile(`(?:^|[/:])containers/([0-9a-f]{64})/[0-9a-f]{64}-json\.log`)
+)
+
+// dockerRecord is one line of Docker's json-file logging driver
+type dockerRecord struct {
+	Log    *string           `json:"log"`
+	Stream string            `json:"stream"`
+	Time   string            `json:"time"`
+	Attrs  map[string]string `json:"attrs"`
+}
+
+// dockerFormat parses logs of Docker's json-file logging driver
+type dockerFormat struct{}
+
+// Name returns the name the format is selected by
+func (dockerFormat) Name() string {
+	return "docker"
+}
+
+// ParseLine parses one json-file record
+func (dockerFormat) ParseLine(line string) (*common.LogEntry, error) {
+	var record dockerRecord
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, fmt.Errorf("invalid docker log record: %w", err)
+	}
+	if record.Log == nil || record.Stream == "" {
+		return nil, fmt.Errorf("not a docker json-file record")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, record.Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker log timestamp: %w", err)
+	}
+
+	entry := newContainerEntry(timestamp, record.Stream, strings.TrimRight(*record.Log, "\r\n"))
+	for key, value := range record.Attrs {
+		setMetadata(entry, key, value)
+	}
+	entry.Service = firstAttr(record.Attrs, "io.kubernetes.container.name", "com.docker.compose.service", "tag")
+	return entry, nil
+}
+
+// annotateSource names the container a json-file log belongs to after its
+// directory, unless the record's attributes already did
+func (dockerFormat) annotateSource(entry *common.LogEntry, source string) {
+	match := dockerLogPath.FindStringSubmatch(source)
+	if match == nil {
+		return
+	}
+	id := match[1][:12]
+	entry.Metadata["container_id"] = id
+	if entry.Service == "" {
+		entry.Service = id
+	}
+}
+
+// criFormat parses Kubernetes container logs written by CRI runtimes such
+// as containerd and CRI-O
+type criFormat struct{}
+
+// Name returns the name the format is selected by
+func (criFormat) Name() string {
+	return "cri"
+}
+
+// ParseLine parses one CRI log line
+func (criFormat) ParseLine(line string) (*common.LogEntry, error) {
+	match := criPattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil, fmt.Errorf("not a CRI log line")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, match[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRI log timestamp: %w", err)
+	}
+
+	entry := newContainerEntry(timestamp, match[2], match[4])
+	if match[3] == "P" {
+		entry.Metadata["partial"] = "true"
+	}
+	return entry, nil
+}
+
+// annotateSource takes the pod, namespace and container of a Kubernetes
+// log from its path
+func (criFormat) annotateSource(entry *common.LogEntry, source string) {
+	if match := podLogPath.FindStringSubmatch(source); match != nil {
+		setKubernetesMetadata(entry, match[1], match[2], match[3])
+	} else if match := containerLogPath.FindStringSubmatch(source); match != nil {
+		setKubernetesMetadata(entry, match[2], match[1], match[3])
+	}
+}
+
+// setKubernetesMetadata records where a container log came from, with the
+// container as the service
+func setKubernetesMetadata(entry *common.LogEntry, namespace, pod, container string) {
+	entry.Service = container
+	entry.Metadata["namespace"] = namespace
+	entry.Metadata["pod"] = pod
+}
+
+// newContainerEntry creates an entry for a line an application wrote to
+// stdout or stderr, taking the level from the line itself
+func newContainerEntry(timestamp time.Time, stream, message string) *common.LogEntry {
+	parsed := logparser.LogEntry{
+		Timestamp: timestamp,
+		Level:     levelFromMessage(message),
+		Message:   message,
+	}
+	entry := common.ConvertToCommonLogEntry(&parsed, 0)
+	entry.Metadata["stream"] = stream
+	return entry
+}
+
+// firstAttr returns the first of the keys set in attrs
+func firstAttr(attrs map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := attrs[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
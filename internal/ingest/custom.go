@@ -0,0 +1,211 @@
+package ingest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+// LineFormat parses the lines of a named log format that go-logparser
+// does not know, such as a user-defined regex
+type LineFormat interface {
+	Name() string
+	ParseLine(line string) (*common.LogEntry, error)
+}
+
+// FormatDefinition declares a custom log format. Exactly one of Regex and
+// Grok is set; the named captures of the expression become entry fields.
+type FormatDefinition struct {
+	Regex           string            // regex with named groups, e.g. (?P<level>\w+)
+	Grok            string            // grok expression, e.g. %{LOGLEVEL:level}
+	TimestampFormat string            // Go time layout of the timestamp capture, guessed when empty
+	Fields          map[string]string // capture name -> entry field, for captures not named after a field
+}
+
+// Entry fields a capture can be mapped onto. Captures mapped to anything
+// else are stored in the entry's Metadata.
+const (
+	FieldTimestamp = "timestamp"
+	FieldLevel     = "level"
+	FieldMessage   = "message"
+	FieldService   = "service"
+	FieldTraceID   = "trace_id"
+)
+
+// fieldAliases maps common capture names onto entry fields
+var fieldAliases = map[string]string{
+	"timestamp": FieldTimestamp,
+	"time":      FieldTimestamp,
+	"ts":        FieldTimestamp,
+	"level":     FieldLevel,
+	"lvl":       FieldLevel,
+	"severity":  FieldLevel,
+	"message":   FieldMessage,
+	"msg":       FieldMessage,
+	"service":   FieldService,
+	"app":       FieldService,
+	"trace_id":  FieldTraceID,
+	"traceid":   FieldTraceID,
+	"trace":     FieldTraceID,
+}
+
+// timestampLayouts are tried in order when a format has no TimestampFormat
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.000000",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05,000",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05.000",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"02/Jan/2006:15:04:05 -0700",
+	"Jan _2 15:04:05.000",
+	"Jan _2 15:04:05",
+	time.RubyDate,
+	time.ANSIC,
+}
+
+// CustomFormat parses lines with a user-defined regex or grok expression
+type CustomFormat struct {
+	name            string
+	regex           *regexp.Regexp
+	fields          []string // entry field or metadata key per capture group
+	timestampFormat string
+}
+
+// NewCustomFormat compiles a format definition
+func NewCustomFormat(name string, definition FormatDefinition) (*CustomFormat, error) {
+	if name == "" {
+		return nil, fmt.Errorf("custom format name is required")
+	}
+
+	expression := definition.Regex
+	switch {
+	case definition.Regex != "" && definition.Grok != "":
+		return nil, fmt.Errorf("format %s: regex and grok are mutually exclusive", name)
+	case definition.Grok != "":
+		expanded, err := ExpandGrok(definition.Grok)
+		if err != nil {
+			return nil, fmt.Errorf("format %s: %w", name, err)
+		}
+		expression = expanded
+	case expression == "":
+		return nil, fmt.Errorf("format %s: a regex or grok expression is required", name)
+	}
+
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("format %s: invalid expression: %w", name, err)
+	}
+
+	format := &CustomFormat{
+		name:            name,
+		regex:           re,
+		fields:          make([]string, len(re.SubexpNames())),
+		timestampFormat: definition.TimestampFormat,
+	}
+	for i, capture := range re.SubexpNames() {
+		if capture == "" {
+			continue
+		}
+		format.fields[i] = captureField(capture, definition.Fields)
+	}
+	return format, nil
+}
+
+// captureField resolves the entry field a named capture is stored in
+func captureField(capture string, mapping map[string]string) string {
+	if field, ok := mapping[capture]; ok {
+		capture = field
+	}
+	if field, ok := fieldAliases[strings.ToLower(capture)]; ok {
+		return field
+	}
+	return capture
+}
+
+// Name returns the name the format is selected by
+func (f *CustomFormat) Name() string {
+	return f.name
+}
+
+// ParseLine parses a line, failing when it does not match the expression
+// or its timestamp capture does not parse. Such a line is skipped rather
+// than given a made-up time, which would skew the analyzed time range.
+func (f *CustomFormat) ParseLine(line string) (*common.LogEntry, error) {
+	match := f.regex.FindStringSubmatch(line)
+	if match == nil {
+		return nil, fmt.Errorf("line does not match format %s", f.name)
+	}
+
+	parsed := logparser.LogEntry{Level: logparser.LevelInfo, Message: line}
+	metadata := make(map[string]string)
+	var service, traceID string
+
+	for i, value := range match {
+		field := f.fields[i]
+		if field == "" || value == "" {
+			continue
+		}
+
+		switch field {
+		case FieldTimestamp:
+			ts, ok := f.parseTimestamp(value)
+			if !ok {
+				return nil, fmt.Errorf("timestamp %q does not match format %s", value, f.name)
+			}
+			parsed.Timestamp = ts
+		case FieldLevel:
+			parsed.Level = normalizeLevel(value)
+		case FieldMessage:
+			parsed.Message = value
+		case FieldService:
+			service = value
+		case FieldTraceID:
+			traceID = value
+		default:
+			metadata[field] = value
+		}
+	}
+
+	entry := common.ConvertToCommonLogEntry(&parsed, 0)
+	entry.Service = service
+	entry.TraceID = traceID
+	entry.Metadata = metadata
+	return entry, nil
+}
+
+// normalizeLevel maps level names, including syslog severities, onto the
+// levels LogSum knows
+func normalizeLevel(value string) string {
+	switch strings.ToUpper(value) {
+	case "TRACE":
+		return "DEBUG"
+	case "CRIT", "CRITICAL", "SEVERE":
+		return "ERROR"
+	case "EMERG", "EMERGENCY", "ALERT", "PANIC":
+		return "FATAL"
+	default:
+		return logparser.ParseLevel(value)
+	}
+}
+
+// parseTimestamp parses a timestamp capture with the configured layout or,
+// without one, the first common layout that fits
+func (f *CustomFormat) parseTimestamp(value string) (time.Time, bool) {
+	if f.timestampFormat != "" {
+		ts, err := time.Parse(f.timestampFormat, value)
+		return ts, err == nil
+	}
+	for _, layout := range timestampLayouts {
+		if ts, err := time.Parse(layout, value); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
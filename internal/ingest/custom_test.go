@@ -0,0 +1,183 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func TestCustomFormatParseLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition FormatDefinition
+		line       string
+		message    string
+		level      common.LogLevel
+		service    string
+		traceID    string
+		metadata   map[string]string
+	}{
+		{
+			name: "regex",
+			definition: FormatDefinition{
+				Regex: `^(?P<timestamp>\S+ \S+) (?P<level>\w+) \[(?P<service>[\w-]+)\] (?P<trace_id>\w+) (?P<message>.*)$`,
+			},
+			line:    "2024-01-01 10:00:00 ERR [billing] abc123 charge failed",
+			message: "charge failed",
+			level:   common.LevelError,
+			service: "billing",
+			traceID: "abc123",
+		},
+		{
+			name: "grok with aliases and metadata",
+			definition: FormatDefinition{
+				Grok: `%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:lvl} %{IPV4:client} status=%{INT:status:int} %{GREEDYDATA:msg}`,
+			},
+			line:     "2024-01-01T10:00:00Z WARN 10.0.0.1 status=503 upstream slow",
+			message:  "upstream slow",
+			level:    common.LevelWarn,
+			metadata: map[string]string{"client": "10.0.0.1", "status": "503"},
+		},
+		{
+			name: "explicit field mapping",
+			definition: FormatDefinition{
+				Grok:            `\[%{HTTPDATE:when}\] %{WORD:app}: %{GREEDYDATA:text}`,
+				TimestampFormat: "02/Jan/2006:15:04:05 -0700",
+				Fields:          map[string]string{"when": "timestamp", "text": "message"},
+			},
+			line:    "[01/Jan/2024:10:00:00 +0000] api: request done",
+			message: "request done",
+			level:   common.LevelInfo,
+			service: "api",
+		},
+	}
+
+	expectedTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := NewCustomFormat("legacy", tt.definition)
+			if err != nil {
+				t.Fatalf("NewCustomFormat() failed: %v", err)
+			}
+
+			entry, err := format.ParseLine(tt.line)
+			if err != nil {
+				t.Fatalf("ParseLine() failed: %v", err)
+			}
+			if entry.Message != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, entry.Message)
+			}
+			if entry.LogLevel != tt.level {
+				t.Errorf("Expected level %s, got %s", tt.level, entry.LogLevel)
+			}
+			if !entry.Timestamp.Equal(expectedTime) {
+				t.Errorf("Expected timestamp %v, got %v", expectedTime, entry.Timestamp)
+			}
+			if entry.Service != tt.service {
+				t.Errorf("Expected service %q, got %q", tt.service, entry.Service)
+			}
+			if entry.TraceID != tt.traceID {
+				t.Errorf("Expected trace ID %q, got %q", tt.traceID, entry.TraceID)
+			}
+			if len(entry.Metadata) != len(tt.metadata) {
+				t.Errorf("Expected metadata %v, got %v", tt.metadata, entry.Metadata)
+			}
+			for key, value := range tt.metadata {
+				if entry.Metadata[key] != value {
+					t.Errorf("Expected metadata %s=%q, got %q", key, value, entry.Metadata[key])
+				}
+			}
+		})
+	}
+}
+
+func TestNewCustomFormatErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition FormatDefinition
+	}{
+		{"no expression", FormatDefinition{}},
+		{"both expressions", FormatDefinition{Regex: `.*`, Grok: `%{GREEDYDATA}`}},
+		{"invalid regex", FormatDefinition{Regex: `(`}},
+		{"unknown grok pattern", FormatDefinition{Grok: `%{NOPE:x}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCustomFormat("legacy", tt.definition); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestReaderCustomFormat(t *testing.T) {
+	format, err := NewCustomFormat("legacy", FormatDefinition{
+		Grok: `%{TIMESTAMP_ISO8601:timestamp} \| %{LOGLEVEL:level} \| %{GREEDYDATA:message}`,
+	})
+	if err != nil {
+		t.Fatalf("NewCustomFormat() failed: %v", err)
+	}
+
+	input := "2024-01-01 10:00:00 | ERROR | disk full\n" +
+		"garbage line\n" +
+		"2024-01-01 10:00:01 | INFO | cleaned up\n"
+	reader, err := NewReader(strings.NewReader(input), Options{
+		Format:  "legacy",
+		Formats: []LineFormat{format},
+		Source:  "app.log",
+	})
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+
+	lineNumbers := readAll(t, reader)
+	if len(lineNumbers) != 2 || lineNumbers[0] != 1 || lineNumbers[1] != 3 {
+		t.Errorf("Expected entries from lines 1 and 3, got %v", lineNumbers)
+	}
+	if reader.Skipped() != 1 {
+		t.Errorf("Expected 1 skipped line, got %d", reader.Skipped())
+	}
+	if reader.FormatName() != "legacy" {
+		t.Errorf("Expected format name legacy, got %s", reader.FormatName())
+	}
+
+	_, err = NewReader(strings.NewReader(input), Options{Format: "missing", Formats: []LineFormat{format}})
+	if err == nil || !strings.Contains(err.Error(), "legacy") {
+		t.Errorf("Expected unknown format error listing custom formats, got %v", err)
+	}
+}
+
+func TestReaderCustomFormatSkipsUnparsedTimestamps(t *testing.T) {
+	format, err := NewCustomFormat("mine", FormatDefinition{
+		Regex: `^(?P<ts>\S+) (?P<level>[A-Z]+) (?P<msg>.*)$`,
+	})
+	if err != nil {
+		t.Fatalf("NewCustomFormat() failed: %v", err)
+	}
+
+	input := "2024-01-01T10:00:00Z INFO start\n" +
+		"garbage ERROR x\n" +
+		"2024-01-01T10:05:00Z ERROR boom\n"
+	reader, err := NewReader(strings.NewReader(input), Options{Format: "mine", Formats: []LineFormat{format}})
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+
+	var messages []string
+	for {
+		entry, err := reader.Next()
+		if err != nil {
+			break
+		}
+		if entry.Timestamp.IsZero() {
+			t.Errorf("entry %q has no timestamp", entry.Message)
+		}
+		messages = append(messages, entry.Message)
+	}
+	if strings.Join(messages, ",") != "start,boom" || reader.Skipped() != 1 {
+		t.Errorf("Expected start and boom with 1 skipped line, got %v and %d skipped", messages, reader.Skipped())
+	}
+}
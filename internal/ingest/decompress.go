@@ -0,0 +1,117 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression identifies how an input stream is compressed
+type Compression int
+
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionBzip2
+	CompressionZstd
+)
+
+// String returns the name of the compression format
+func (c Compression) String() string {
+	switch c {
+	case CompressionGzip:
+		return "gzip"
+	case CompressionBzip2:
+		return "bzip2"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return "none"
+	}
+}
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+const (
+	// tarMagicOffset is where the ustar magic lives in a tar header
+	tarMagicOffset = 257
+
+	// peekSize covers every magic number checked, including tar's
+	peekSize = tarMagicOffset + 5
+)
+
+// DetectCompression identifies the compression of the data at the start of
+// r by its magic bytes, without consuming it
+func DetectCompression(r *bufio.Reader) Compression {
+	header, _ := r.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return CompressionGzip
+	case isBzip2(header):
+		return CompressionBzip2
+	case bytes.HasPrefix(header, zstdMagic):
+		return CompressionZstd
+	default:
+		return CompressionNone
+	}
+}
+
+// isBzip2 reports whether header starts a bzip2 stream: the magic followed
+// by a block size digit from 1 to 9, so a text line starting with "BZh" is
+// not mistaken for one
+func isBzip2(header []byte) bool {
+	if !bytes.HasPrefix(header, bzip2Magic) || len(header) <= len(bzip2Magic) {
+		return false
+	}
+	blockSize := header[len(bzip2Magic)]
+	return blockSize >= '1' && blockSize <= '9'
+}
+
+// isTar reports whether the data at the start of r is a tar archive
+func isTar(r *bufio.Reader) bool {
+	header, err := r.Peek(peekSize)
+	if err != nil {
+		return false
+	}
+	return bytes.HasPrefix(header[tarMagicOffset:], []byte("ustar"))
+}
+
+// Decompress returns a reader yielding the decompressed content of r.
+// Gzip, bzip2 and zstd input is detected by its magic bytes; anything else
+// is returned unchanged. Closing the result releases decoder resources but
+// does not close r.
+func Decompress(r io.Reader) (io.ReadCloser, Compression, error) {
+	buffered := bufio.NewReaderSize(r, 64*1024)
+
+	compression := DetectCompression(buffered)
+	switch compression {
+	case CompressionGzip:
+		decoder, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, compression, fmt.Errorf("failed to read gzip input: %w", err)
+		}
+		return decoder, compression, nil
+
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(buffered)), compression, nil
+
+	case CompressionZstd:
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, compression, fmt.Errorf("failed to read zstd input: %w", err)
+		}
+		return decoder.IOReadCloser(), compression, nil
+
+	default:
+		return io.NopCloser(buffered), compression, nil
+	}
+}
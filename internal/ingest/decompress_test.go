@@ -0,0 +1,184 @@
+package ingest
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const compressedLog = "2024-01-01 10:00:00 [INFO] service started\n" +
+	"2024-01-01 10:00:01 [ERROR] connection refused\n"
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatalf("gzip Write() failed: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("gzip Close() failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zstdBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("zstd.NewWriter() failed: %v", err)
+	}
+	defer encoder.Close()
+	return encoder.EncodeAll([]byte(data), nil)
+}
+
+func TestDetectCompression(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected Compression
+	}{
+		{"plain", []byte(compressedLog), CompressionNone},
+		{"gzip", gzipBytes(t, compressedLog), CompressionGzip},
+		{"bzip2", []byte("BZh91AY&SY"), CompressionBzip2},
+		{"bzip2 magic without block size", []byte("BZh happened\n"), CompressionNone},
+		{"bzip2 magic only", []byte("BZh"), CompressionNone},
+		{"zstd", zstdBytes(t, compressedLog), CompressionZstd},
+		{"empty", nil, CompressionNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectCompression(bufio.NewReader(bytes.NewReader(tt.input)))
+			if got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"plain", []byte(compressedLog)},
+		{"gzip", gzipBytes(t, compressedLog)},
+		{"zstd", zstdBytes(t, compressedLog)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, _, err := Decompress(bytes.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Decompress() failed: %v", err)
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("ReadAll() failed: %v", err)
+			}
+			if string(data) != compressedLog {
+				t.Errorf("Expected %q, got %q", compressedLog, string(data))
+			}
+		})
+	}
+}
+
+// tarArchive returns a gzipped tar archive, as in a .tar.gz bundle, of an
+// api log and a gzipped worker log whose entries interleave in time
+func tarArchive(t *testing.T) []byte {
+	t.Helper()
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	members := []struct {
+		name string
+		data []byte
+	}{
+		{"logs/api.log", []byte("2024-01-01 10:00:00 [ERROR] api failed\n2024-01-01 10:00:02 [INFO] api recovered\n")},
+		{"logs/worker.log.gz", gzipBytes(t, "2024-01-01 10:00:01 [WARN] worker slow\n")},
+	}
+	if err := writer.WriteHeader(&tar.Header{Name: "logs/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatalf("WriteHeader() failed: %v", err)
+	}
+	for _, member := range members {
+		header := &tar.Header{Name: member.name, Mode: 0o644, Size: int64(len(member.data))}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatalf("WriteHeader() failed: %v", err)
+		}
+		if _, err := writer.Write(member.data); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	return gzipBytes(t, archive.String())
+}
+
+func TestOpenTarArchive(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxLines  int
+		expected  []string
+		truncated bool
+	}{
+		{
+			name: "members merged by timestamp",
+			expected: []string{
+				"bundle.tar.gz:logs/api.log api failed",
+				"bundle.tar.gz:logs/worker.log.gz worker slow",
+				"bundle.tar.gz:logs/api.log api recovered",
+			},
+		},
+		{
+			name:     "max lines across the archive",
+			maxLines: 2,
+			expected: []string{
+				"bundle.tar.gz:logs/api.log api failed",
+				"bundle.tar.gz:logs/api.log api recovered",
+			},
+			truncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, release, err := Open(bytes.NewReader(tarArchive(t)), Options{Source: "bundle.tar.gz", MaxLines: tt.maxLines})
+			if err != nil {
+				t.Fatalf("Open() failed: %v", err)
+			}
+			defer release()
+
+			var got []string
+			for {
+				entry, err := source.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Next() failed: %v", err)
+				}
+				got = append(got, entry.Source+" "+entry.Message)
+			}
+
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+			if source.LinesRead() != len(tt.expected) {
+				t.Errorf("Expected %d lines read, got %d", len(tt.expected), source.LinesRead())
+			}
+			if source.Truncated() != tt.truncated {
+				t.Errorf("Expected truncated %v, got %v", tt.truncated, source.Truncated())
+			}
+		})
+	}
+}
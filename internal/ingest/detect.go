@@ -0,0 +1,58 @@
+package ingest
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/yildizm/go-logparser"
+)
+
+// DetectFormat picks a log format from sample lines using the same
+// heuristics as go-logparser's auto-detection. The result is fixed for
+// the rest of the stream so that every batch is parsed consistently.
+func DetectFormat(samples []string) logparser.Format {
+	if len(samples) == 0 {
+		return logparser.FormatText
+	}
+
+	jsonScore, logfmtScore := 0, 0
+	for _, sample := range samples {
+		if isJSONLine(sample) {
+			jsonScore++
+		}
+		if isLogfmtLine(sample) {
+			logfmtScore++
+		}
+	}
+
+	// Text always matches, so it scores one per sample
+	textScore := len(samples)
+
+	if jsonScore > logfmtScore && jsonScore > textScore/2 {
+		return logparser.FormatJSON
+	}
+	if logfmtScore > textScore/2 {
+		return logparser.FormatLogfmt
+	}
+	return logparser.FormatText
+}
+
+// isJSONLine checks if a line is a JSON object
+func isJSONLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") || !strings.HasSuffix(line, "}") {
+		return false
+	}
+
+	var obj map[string]interface{}
+	return json.Unmarshal([]byte(line), &obj) == nil
+}
+
+// isLogfmtLine checks if a line looks like logfmt key=value pairs
+func isLogfmtLine(line string) bool {
+	return strings.Contains(line, "=") &&
+		(strings.Contains(line, "level=") ||
+			strings.Contains(line, "msg=") ||
+			strings.Contains(line, "time=") ||
+			strings.Contains(line, "timestamp="))
+}
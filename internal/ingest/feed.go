@@ -0,0 +1,100 @@
+package ingest
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+// Feeder parses input that arrives in pieces, such as the lines appended
+// to a followed file. A multi-line record may span pieces: the record being
+// assembled is held back until the line starting the next one arrives or
+// Flush is called, e.g. once the input has been idle for a while.
+type Feeder struct {
+	reader *Reader
+}
+
+// NewFeeder creates a feeder parsing with options. MaxLines, Budget and
+// BatchSize are ignored.
+func NewFeeder(options Options) (*Feeder, error) {
+	options.MaxLines = 0
+	options.Budget = nil
+	reader, err := NewReader(strings.NewReader(""), options)
+	if err != nil {
+		return nil, err
+	}
+	reader.hold = true
+	return &Feeder{reader: reader}, nil
+}
+
+// Feed parses text, which holds complete lines, and returns the entries of
+// the records it completes
+func (f *Feeder) Feed(text string) ([]*common.LogEntry, error) {
+	r := f.reader
+	r.scanner = bufio.NewScanner(strings.NewReader(text))
+	r.scanner.Buffer(make([]byte, 64*1024), r.options.MaxLineLength)
+	r.eof = false
+
+	var entries []*common.LogEntry
+	for {
+		entry, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// Flush parses the record still being assembled, if any
+func (f *Feeder) Flush() []*common.LogEntry {
+	r := f.reader
+	completed := r.joiner.flush()
+	if completed == nil {
+		return nil
+	}
+
+	batch := []batchLine{newBatchLine(completed)}
+	if r.parser == nil && r.line == nil {
+		r.resolveFormat(batch)
+	}
+	return r.parseBatch(batch)
+}
+
+// Holding reports whether a record is held back until more input or Flush
+func (f *Feeder) Holding() bool {
+	return f.reader.joiner.pending != nil
+}
+
+// Format returns the resolved log format, FormatAuto until the first
+// record has been parsed
+func (f *Feeder) Format() logparser.Format {
+	return f.reader.Format()
+}
+
+// FormatName returns the name of the resolved format
+func (f *Feeder) FormatName() string {
+	return f.reader.FormatName()
+}
+
+// LinesRead returns the number of non-empty lines fed so far
+func (f *Feeder) LinesRead() int {
+	return f.reader.LinesRead()
+}
+
+// LineNumber returns the number of the last line fed, including empty
+// lines and LineOffset
+func (f *Feeder) LineNumber() int {
+	return f.reader.LineNumber()
+}
+
+// Skipped returns the number of lines that could not be parsed
+func (f *Feeder) Skipped() int {
+	return f.reader.Skipped()
+}
@@ -0,0 +1,64 @@
+package ingest
+
+import (
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+// FilterReader passes on only the entries of a source that keep accepts.
+// Read statistics still describe the whole underlying source.
+type FilterReader struct {
+	source   Source
+	keep     func(*common.LogEntry) bool
+	filtered int
+}
+
+// NewFilterReader wraps source so entries keep rejects are dropped
+func NewFilterReader(source Source, keep func(*common.LogEntry) bool) *FilterReader {
+	return &FilterReader{source: source, keep: keep}
+}
+
+// Next returns the next accepted entry, or io.EOF once the source is
+// exhausted
+func (f *FilterReader) Next() (*common.LogEntry, error) {
+	for {
+		entry, err := f.source.Next()
+		if err != nil {
+			return nil, err
+		}
+		if f.keep(entry) {
+			return entry, nil
+		}
+		f.filtered++
+	}
+}
+
+// Filtered returns the number of entries dropped so far
+func (f *FilterReader) Filtered() int {
+	return f.filtered
+}
+
+// Format returns the format of the underlying source
+func (f *FilterReader) Format() logparser.Format {
+	return f.source.Format()
+}
+
+// FormatName returns the format name of the underlying source
+func (f *FilterReader) FormatName() string {
+	return f.source.FormatName()
+}
+
+// LinesRead returns the number of lines the underlying source consumed
+func (f *FilterReader) LinesRead() int {
+	return f.source.LinesRead()
+}
+
+// Skipped returns the number of unparseable lines in the underlying source
+func (f *FilterReader) Skipped() int {
+	return f.source.Skipped()
+}
+
+// Truncated reports whether the underlying source was left partly unread
+func (f *FilterReader) Truncated() bool {
+	return f.source.Truncated()
+}
@@ -0,0 +1,143 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+// DefaultPollInterval is how often a followed file is checked for new data
+const DefaultPollInterval = 250 * time.Millisecond
+
+// FollowSource parses the lines appended to an input, like tail -f. Instead
+// of io.EOF it waits for more data, reporting io.EOF only once the context
+// is cancelled. A record that could still be continued, such as the last
+// line of a stack trace, is emitted after the input stayed idle for a poll
+// interval, so the latest entry is not held back until the next one.
+type FollowSource struct {
+	ctx          context.Context
+	source       io.Reader
+	pollInterval time.Duration
+	feeder       *Feeder
+	buf          []byte
+	partial      string // trailing data of a line still being written
+	pending      []*common.LogEntry
+	idle         bool // the last poll found no new data
+	done         bool
+}
+
+// NewFollowSource follows source, parsing its lines with options until ctx
+// is cancelled
+func NewFollowSource(ctx context.Context, source io.Reader, pollInterval time.Duration, options Options) (*FollowSource, error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+	feeder, err := NewFeeder(options)
+	if err != nil {
+		return nil, err
+	}
+	return &FollowSource{
+		ctx:          ctx,
+		source:       source,
+		pollInterval: pollInterval,
+		feeder:       feeder,
+		buf:          make([]byte, 64*1024),
+	}, nil
+}
+
+// Next returns the next appended entry, waiting for one if necessary
+func (f *FollowSource) Next() (*common.LogEntry, error) {
+	for len(f.pending) == 0 {
+		if f.done {
+			return nil, io.EOF
+		}
+		if err := f.poll(); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := f.pending[0]
+	f.pending[0] = nil
+	f.pending = f.pending[1:]
+	return entry, nil
+}
+
+// poll parses the data appended since the last poll. Without new data it
+// waits a poll interval, flushing the held record on the second idle poll
+// in a row.
+func (f *FollowSource) poll() error {
+	n, err := f.source.Read(f.buf)
+	if n > 0 {
+		f.idle = false
+		return f.feed(string(f.buf[:n]))
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	if f.idle {
+		f.pending = append(f.pending, f.feeder.Flush()...)
+	}
+	f.idle = true
+
+	select {
+	case <-f.ctx.Done():
+		// Whatever was written last is complete now
+		f.done = true
+		partial := f.partial
+		f.partial = ""
+		if partial != "" {
+			if err := f.feed(partial + "\n"); err != nil {
+				return err
+			}
+		}
+		f.pending = append(f.pending, f.feeder.Flush()...)
+	case <-time.After(f.pollInterval):
+	}
+	return nil
+}
+
+// feed parses the complete lines of the partial line followed by data
+func (f *FollowSource) feed(data string) error {
+	text := f.partial + data
+	cut := strings.LastIndexByte(text, '\n')
+	if cut < 0 {
+		f.partial = text
+		return nil
+	}
+	f.partial = text[cut+1:]
+
+	entries, err := f.feeder.Feed(text[:cut+1])
+	f.pending = append(f.pending, entries...)
+	return err
+}
+
+// Format returns the resolved log format
+func (f *FollowSource) Format() logparser.Format {
+	return f.feeder.Format()
+}
+
+// FormatName returns the name of the resolved format
+func (f *FollowSource) FormatName() string {
+	return f.feeder.FormatName()
+}
+
+// LinesRead returns the number of non-empty lines parsed so far
+func (f *FollowSource) LinesRead() int {
+	return f.feeder.LinesRead()
+}
+
+// Skipped returns the number of lines that could not be parsed
+func (f *FollowSource) Skipped() int {
+	return f.feeder.Skipped()
+}
+
+// Truncated always returns false, as followed input has no line limit
+func (f *FollowSource) Truncated() bool {
+	return false
+}
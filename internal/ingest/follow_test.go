@@ -0,0 +1,61 @@
+package ingest
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFollowSourceWaitsForAppendedData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("2024-01-01 10:00:00 [INFO] first\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := NewFollowSource(ctx, file, 50*time.Millisecond, Options{Format: "text"})
+	if err != nil {
+		t.Fatalf("NewFollowSource() failed: %v", err)
+	}
+
+	// The last line is emitted once the input is idle, without waiting for
+	// the next line
+	entry, err := source.Next()
+	if err != nil || entry.Message != "first" {
+		t.Fatalf("Expected initial entry, got %+v (%v)", entry, err)
+	}
+
+	// A stack trace written in two pieces is still one entry
+	go func() {
+		appender, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return
+		}
+		defer appender.Close()
+		_, _ = appender.WriteString("2024-01-01 10:00:01 [ERROR] request failed\n")
+		time.Sleep(5 * time.Millisecond)
+		_, _ = appender.WriteString("    at handler (app.js:10)\n")
+	}()
+
+	entry, err = source.Next()
+	if err != nil || entry.Message != "request failed" || !strings.Contains(entry.Raw, "app.js:10") {
+		t.Fatalf("Expected appended stack trace, got %+v (%v)", entry, err)
+	}
+
+	cancel()
+	if _, err := source.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after cancel, got %v", err)
+	}
+}
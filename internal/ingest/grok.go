@@ -0,0 +1,112 @@
+package ingest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// grokPatterns are the grok patterns available in format definitions.
+// Patterns may reference each other with %{NAME}.
+var grokPatterns = map[string]string{
+	"WORD":         `\b\w+\b`,
+	"NOTSPACE":     `\S+`,
+	"SPACE":        `\s*`,
+	"DATA":         `.*?`,
+	"GREEDYDATA":   `.*`,
+	"INT":          `[+-]?\d+`,
+	"POSINT":       `\b[1-9]\d*\b`,
+	"NONNEGINT":    `\b\d+\b`,
+	"NUMBER":       `[+-]?(?:\d+(?:\.\d*)?|\.\d+)`,
+	"BASE10NUM":    `%{NUMBER}`,
+	"QUOTEDSTRING": `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`,
+	"UUID":         `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+
+	"USERNAME":     `[a-zA-Z0-9._-]+`,
+	"USER":         `%{USERNAME}`,
+	"EMAILADDRESS": `[a-zA-Z0-9_.+-]+@[a-zA-Z0-9-]+(?:\.[a-zA-Z0-9-]+)*`,
+	"HOSTNAME":     `\b[0-9A-Za-z][0-9A-Za-z-]{0,62}(?:\.[0-9A-Za-z][0-9A-Za-z-]{0,62})*\.?\b`,
+	"IPV4":         `(?:\d{1,3}\.){3}\d{1,3}`,
+	"IPV6":         `[0-9A-Fa-f:]*:[0-9A-Fa-f:.]+`,
+	"IP":           `%{IPV6}|%{IPV4}`,
+	"IPORHOST":     `%{IP}|%{HOSTNAME}`,
+	"HOSTPORT":     `%{IPORHOST}:%{POSINT}`,
+
+	"PATH":         `(?:/[^\s/]*)+`,
+	"URIPATH":      `(?:/[A-Za-z0-9$.+!*'(){},~:;=@#%&_\-]*)+`,
+	"URIPARAM":     `\?[A-Za-z0-9$.+!*'|(){},~@#%&/=:;_?\-\[\]<>]*`,
+	"URIPATHPARAM": `%{URIPATH}(?:%{URIPARAM})?`,
+	"URI":          `[A-Za-z][A-Za-z0-9+.-]*://\S+`,
+
+	"LOGLEVEL": `(?i:trace|debug|dbg|info|inf|notice|warn|warning|wrn|error|err|crit|critical|fatal|ftl|severe|emerg|alert)`,
+
+	"YEAR":       `\d{4}`,
+	"MONTHNUM":   `0?[1-9]|1[0-2]`,
+	"MONTHDAY":   `(?:0[1-9])|(?:[12][0-9])|(?:3[01])|[1-9]`,
+	"MONTH":      `\b(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-z]*\b`,
+	"DAY":        `\b(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun)[a-z]*\b`,
+	"HOUR":       `2[0123]|[01]?[0-9]`,
+	"MINUTE":     `[0-5][0-9]`,
+	"SECOND":     `(?:[0-5]?[0-9]|60)(?:[:.,][0-9]+)?`,
+	"TIME":       `%{HOUR}:%{MINUTE}(?::%{SECOND})?`,
+	"ISO8601_TZ": `Z|[+-]%{HOUR}(?::?%{MINUTE})`,
+
+	"TIMESTAMP_ISO8601": `%{YEAR}-%{MONTHNUM}-%{MONTHDAY}[T ]%{HOUR}:?%{MINUTE}(?::?%{SECOND})?(?:%{ISO8601_TZ})?`,
+	"DATE_US":           `%{MONTHNUM}[/-]%{MONTHDAY}[/-]%{YEAR}`,
+	"DATE_EU":           `%{MONTHDAY}[./-]%{MONTHNUM}[./-]%{YEAR}`,
+	"HTTPDATE":          `%{MONTHDAY}/%{MONTH}/%{YEAR}:%{TIME} [+-]\d{4}`,
+	"SYSLOGTIMESTAMP":   `%{MONTH} +%{MONTHDAY} %{TIME}`,
+}
+
+// grokReference matches %{PATTERN} and %{PATTERN:field}, with an optional
+// trailing type such as :int which is accepted and ignored
+var grokReference = regexp.MustCompile(`%\{(\w+)(?::([\w.@-]+))?(?::\w+)?\}`)
+
+// maxGrokDepth bounds pattern references to catch cycles
+const maxGrokDepth = 10
+
+// ExpandGrok turns a grok expression into a regular expression. Named
+// references such as %{LOGLEVEL:level} become named capture groups.
+func ExpandGrok(expression string) (string, error) {
+	return expandGrok(expression, 0)
+}
+
+func expandGrok(expression string, depth int) (string, error) {
+	if depth > maxGrokDepth {
+		return "", fmt.Errorf("grok patterns nested too deeply")
+	}
+
+	var expandErr error
+	expanded := grokReference.ReplaceAllStringFunc(expression, func(reference string) string {
+		if expandErr != nil {
+			return reference
+		}
+
+		parts := grokReference.FindStringSubmatch(reference)
+		pattern, ok := grokPatterns[parts[1]]
+		if !ok {
+			expandErr = fmt.Errorf("unknown grok pattern %s", parts[1])
+			return reference
+		}
+
+		inner, err := expandGrok(pattern, depth+1)
+		if err != nil {
+			expandErr = err
+			return reference
+		}
+
+		if parts[2] == "" {
+			return "(?:" + inner + ")"
+		}
+		return "(?P<" + captureName(parts[2]) + ">" + inner + ")"
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return expanded, nil
+}
+
+// captureName makes a grok field name usable as a regex group name
+func captureName(field string) string {
+	return strings.NewReplacer(".", "_", "@", "_", "-", "_").Replace(field)
+}
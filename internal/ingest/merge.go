@@ -0,0 +1,141 @@
+package ingest
+
+import (
+	"container/heap"
+	"errors"
+	"io"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+// Source is a stream of parsed entries together with reading statistics.
+// Both Reader and MergeReader implement it.
+type Source interface {
+	Next() (*common.LogEntry, error)
+	Format() logparser.Format
+	FormatName() string
+	LinesRead() int
+	Skipped() int
+	Truncated() bool
+}
+
+// MergeReader merges several sources into a single stream ordered by
+// timestamp. Each source is assumed to be in timestamp order already, so
+// only the next entry of every source is held in memory.
+type MergeReader struct {
+	sources []Source
+	heads   mergeHeap
+	started bool
+}
+
+// mergeHead is the next unread entry of one source
+type mergeHead struct {
+	entry  *common.LogEntry
+	source int
+}
+
+// mergeHeap orders heads by timestamp, then by source position so that
+// entries with equal timestamps keep the input order
+type mergeHeap []mergeHead
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	if !h[i].entry.Timestamp.Equal(h[j].entry.Timestamp) {
+		return h[i].entry.Timestamp.Before(h[j].entry.Timestamp)
+	}
+	return h[i].source < h[j].source
+}
+func (h mergeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(mergeHead)) }
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	head := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return head
+}
+
+// NewMergeReader creates a reader that interleaves sources by timestamp
+func NewMergeReader(sources ...Source) *MergeReader {
+	return &MergeReader{sources: sources}
+}
+
+// Next returns the earliest pending entry across all sources, or io.EOF
+// once every source is exhausted
+func (m *MergeReader) Next() (*common.LogEntry, error) {
+	if !m.started {
+		m.started = true
+		for i := range m.sources {
+			if err := m.advance(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if m.heads.Len() == 0 {
+		return nil, io.EOF
+	}
+
+	head := heap.Pop(&m.heads).(mergeHead)
+	if err := m.advance(head.source); err != nil {
+		return nil, err
+	}
+	return head.entry, nil
+}
+
+// advance reads the next entry of source i onto the heap
+func (m *MergeReader) advance(i int) error {
+	entry, err := m.sources[i].Next()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	heap.Push(&m.heads, mergeHead{entry: entry, source: i})
+	return nil
+}
+
+// Format returns the format of the first source
+func (m *MergeReader) Format() logparser.Format {
+	if len(m.sources) == 0 {
+		return logparser.FormatAuto
+	}
+	return m.sources[0].Format()
+}
+
+// FormatName returns the format name of the first source
+func (m *MergeReader) FormatName() string {
+	if len(m.sources) == 0 {
+		return logparser.FormatAuto.String()
+	}
+	return m.sources[0].FormatName()
+}
+
+// LinesRead returns the number of non-empty lines consumed across all sources
+func (m *MergeReader) LinesRead() int {
+	total := 0
+	for _, source := range m.sources {
+		total += source.LinesRead()
+	}
+	return total
+}
+
+// Skipped returns the number of unparseable lines across all sources
+func (m *MergeReader) Skipped() int {
+	total := 0
+	for _, source := range m.sources {
+		total += source.Skipped()
+	}
+	return total
+}
+
+// Truncated reports whether any source was left partly unread
+func (m *MergeReader) Truncated() bool {
+	for _, source := range m.sources {
+		if source.Truncated() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package ingest
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMergeReaderOrdersByTimestamp(t *testing.T) {
+	api := "2024-01-01 10:00:00 [INFO] api started\n" +
+		"2024-01-01 10:00:03 [ERROR] api failed\n"
+	worker := "2024-01-01 10:00:01 [INFO] worker started\n" +
+		"2024-01-01 10:00:03 [WARN] worker slow\n" +
+		"2024-01-01 10:00:04 [INFO] worker done\n"
+
+	var sources []Source
+	for _, input := range []struct{ name, text string }{{"api.log", api}, {"worker.log", worker}} {
+		reader, err := NewReader(strings.NewReader(input.text), Options{Source: input.name})
+		if err != nil {
+			t.Fatalf("NewReader() failed: %v", err)
+		}
+		sources = append(sources, reader)
+	}
+
+	merged := NewMergeReader(sources...)
+	var got []string
+	for {
+		entry, err := merged.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() failed: %v", err)
+		}
+		got = append(got, entry.Source+":"+entry.Message)
+	}
+
+	// Equal timestamps keep the argument order
+	expected := []string{
+		"api.log:api started",
+		"worker.log:worker started",
+		"api.log:api failed",
+		"worker.log:worker slow",
+		"worker.log:worker done",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d: %v", len(expected), len(got), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Entry %d: expected %q, got %q", i, expected[i], got[i])
+		}
+	}
+
+	if merged.LinesRead() != 5 {
+		t.Errorf("Expected 5 lines read, got %d", merged.LinesRead())
+	}
+}
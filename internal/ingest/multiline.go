@@ -0,0 +1,131 @@
+package ingest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxRecordLines bounds how many lines are joined into a single entry, so
+// that a run of indented lines cannot grow one entry without limit
+const maxRecordLines = 1000
+
+// MultilineOptions configures how continuation lines, such as the frames
+// of a stack trace, are joined to the entry they belong to
+type MultilineOptions struct {
+	Disabled bool // treat every non-empty line as its own entry
+
+	// StartPatterns are regexes matching the first line of a record. When
+	// set, every line not matching one of them continues the previous
+	// record and the built-in continuation rules are not used.
+	StartPatterns []string
+}
+
+// continuationPatterns recognise unindented lines that belong to the
+// previous record in Java, Python, Go and Node traces
+var continuationPatterns = []*regexp.Regexp{
+	// Java: exception headers of wrapped and suppressed causes
+	regexp.MustCompile(`^(Caused by|Suppressed): `),
+	// Java, Python and Node: an exception class line following a log line
+	regexp.MustCompile(`^([A-Za-z_$][\w$]*\.)*[A-Z][\w$]*(Exception|Error|Throwable)(: |:?$)`),
+	// Python: traceback headers and chained exception separators
+	regexp.MustCompile(`^Traceback \(most recent call last\):$`),
+	regexp.MustCompile(`^(During handling of the above exception|The above exception was the direct cause)`),
+	// Go: goroutine headers, function frames and goroutine origins
+	regexp.MustCompile(`^goroutine \d+ \[[^\]]+\]:$`),
+	regexp.MustCompile(`^[\w./*()\[\]-]+\(.*\)$`),
+	regexp.MustCompile(`^created by `),
+}
+
+// record is one entry's worth of input lines
+type record struct {
+	lines  []string
+	number int // line number of the first line
+	indent int // indentation width of the first line
+}
+
+// text returns the first line, which is what gets parsed
+func (r *record) text() string {
+	return strings.TrimSpace(r.lines[0])
+}
+
+// raw returns every line of the record
+func (r *record) raw() string {
+	return strings.Join(r.lines, "\n")
+}
+
+// lineJoiner groups physical lines into records. A record is only complete
+// once the line starting the next one has been seen, so the last record is
+// held back until flush.
+type lineJoiner struct {
+	disabled bool
+	starts   []*regexp.Regexp
+	pending  *record
+}
+
+// newLineJoiner compiles the multi-line options
+func newLineJoiner(options MultilineOptions) (*lineJoiner, error) {
+	joiner := &lineJoiner{disabled: options.Disabled}
+	for _, pattern := range options.StartPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multiline start pattern %q: %w", pattern, err)
+		}
+		joiner.starts = append(joiner.starts, re)
+	}
+	return joiner, nil
+}
+
+// add takes the next non-empty line and returns the record it completes,
+// if any
+func (j *lineJoiner) add(line string, number int) *record {
+	if j.pending != nil && len(j.pending.lines) < maxRecordLines && j.continues(line) {
+		j.pending.lines = append(j.pending.lines, line)
+		return nil
+	}
+
+	completed := j.pending
+	j.pending = &record{lines: []string{line}, number: number, indent: indentWidth(line)}
+	if j.disabled {
+		completed, j.pending = j.pending, nil
+	}
+	return completed
+}
+
+// flush returns the record still being assembled, if any
+func (j *lineJoiner) flush() *record {
+	completed := j.pending
+	j.pending = nil
+	return completed
+}
+
+// continues reports whether line belongs to the pending record
+func (j *lineJoiner) continues(line string) bool {
+	if j.disabled {
+		return false
+	}
+	if len(j.starts) > 0 {
+		for _, re := range j.starts {
+			if re.MatchString(line) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Indented deeper than the record's first line
+	if indentWidth(line) > j.pending.indent {
+		return true
+	}
+	for _, re := range continuationPatterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// indentWidth returns the number of leading spaces and tabs in line
+func indentWidth(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -0,0 +1,154 @@
+package ingest
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yildizm/LogSum/internal/common"
+)
+
+func readEntries(t *testing.T, input string, options Options) []*common.LogEntry {
+	t.Helper()
+	reader, err := NewReader(strings.NewReader(input), options)
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+
+	var entries []*common.LogEntry
+	for {
+		entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+		if err != nil {
+			t.Fatalf("Next() failed: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func TestReaderJoinsStackTraces(t *testing.T) {
+	tests := []struct {
+		name  string
+		trace string
+	}{
+		{
+			name: "java",
+			trace: "2024-01-01 10:00:01 [ERROR] request failed\n" +
+				"java.lang.IllegalStateException: boom\n" +
+				"\tat com.example.Service.run(Service.java:42)\n" +
+				"\tat com.example.Main.main(Main.java:10)\n" +
+				"Caused by: java.io.IOException: closed\n" +
+				"\t... 2 more\n",
+		},
+		{
+			name: "python",
+			trace: "2024-01-01 10:00:01 [ERROR] request failed\n" +
+				"Traceback (most recent call last):\n" +
+				"  File \"app.py\", line 3, in <module>\n" +
+				"    run()\n" +
+				"\n" +
+				"During handling of the above exception, another exception occurred:\n" +
+				"\n" +
+				"ValueError: bad input\n",
+		},
+		{
+			name: "go",
+			trace: "2024-01-01 10:00:01 [ERROR] panic: runtime error: index out of range\n" +
+				"\n" +
+				"goroutine 1 [running]:\n" +
+				"main.(*Server).handle(0xc000010000)\n" +
+				"\t/app/server.go:42 +0x1d\n" +
+				"created by main.main\n" +
+				"\t/app/main.go:10 +0x25\n",
+		},
+		{
+			name: "node",
+			trace: "2024-01-01 10:00:01 [ERROR] request failed\n" +
+				"TypeError: Cannot read properties of undefined\n" +
+				"    at handler (/app/index.js:10:5)\n" +
+				"    at process.processTicksAndRejections (node:internal/process/task_queues:95:5)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "2024-01-01 10:00:00 [INFO] started\n" + tt.trace +
+				"2024-01-01 10:00:02 [INFO] recovered\n"
+
+			entries := readEntries(t, input, Options{})
+			if len(entries) != 3 {
+				t.Fatalf("Expected 3 entries, got %d", len(entries))
+			}
+
+			trace := entries[1]
+			firstLine := strings.SplitN(tt.trace, "\n", 2)[0]
+			if !strings.Contains(firstLine, trace.Message) {
+				t.Errorf("Expected message from the first line, got %q", trace.Message)
+			}
+			if trace.LineNumber != 2 {
+				t.Errorf("Expected line 2, got %d", trace.LineNumber)
+			}
+			if !strings.HasPrefix(trace.Raw, firstLine) || strings.Count(trace.Raw, "\n") < 3 {
+				t.Errorf("Expected raw to hold the full trace, got %q", trace.Raw)
+			}
+			if entries[2].Message != "recovered" {
+				t.Errorf("Expected next entry after the trace, got %q", entries[2].Message)
+			}
+		})
+	}
+}
+
+func TestReaderMultilineIndentedInput(t *testing.T) {
+	// Uniformly indented lines are separate entries
+	input := "  2024-01-01 10:00:00 [INFO] started\n" +
+		"  2024-01-01 10:00:01 [INFO] ready\n"
+
+	entries := readEntries(t, input, Options{})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Raw != "" {
+		t.Errorf("Expected no raw text for a single-line entry, got %q", entries[0].Raw)
+	}
+}
+
+func TestReaderMultilineStartPatterns(t *testing.T) {
+	input := "2024-01-01 10:00:00 [ERROR] query failed\n" +
+		"SELECT *\n" +
+		"FROM users\n" +
+		"2024-01-01 10:00:01 [INFO] done\n"
+
+	entries := readEntries(t, input, Options{Multiline: MultilineOptions{
+		StartPatterns: []string{`^\d{4}-\d{2}-\d{2}`},
+	}})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if !strings.HasSuffix(entries[0].Raw, "FROM users") {
+		t.Errorf("Expected continuation lines in raw, got %q", entries[0].Raw)
+	}
+
+	if _, err := NewReader(strings.NewReader(input), Options{Multiline: MultilineOptions{
+		StartPatterns: []string{"("},
+	}}); err == nil {
+		t.Error("Expected error for invalid start pattern")
+	}
+}
+
+func TestReaderMultilineDisabled(t *testing.T) {
+	input := "2024-01-01 10:00:00 [ERROR] request failed\n" +
+		"\tat com.example.Service.run(Service.java:42)\n"
+
+	entries := readEntries(t, input, Options{Multiline: MultilineOptions{Disabled: true}})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Raw != "" {
+			t.Errorf("Expected no joined entries, got raw %q", entry.Raw)
+		}
+	}
+}
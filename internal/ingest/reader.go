@@ -0,0 +1,376 @@
+package ingest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+const (
+	// DefaultBatchSize is the number of lines parsed together
+	DefaultBatchSize = 1000
+
+	// DefaultMaxLineLength is the longest line the reader accepts
+	DefaultMaxLineLength = 1024 * 1024 // 1MB
+
+	// detectSampleSize is the number of lines used for format auto-detection
+	detectSampleSize = 10
+)
+
+// Options configures how entries are read from an input stream
+type Options struct {
+	Format        string      // auto, json, logfmt, text, a built-in line format, or the name of one of Formats
+	MaxLines      int         // maximum non-empty lines to read, 0 means unlimited
+	Budget        *LineBudget // non-empty lines left to read, shared by several inputs; overrides MaxLines
+	BatchSize     int         // lines parsed per batch
+	MaxLineLength int         // maximum accepted line length in bytes
+	LineOffset    int         // number of input lines already consumed before this reader
+	Source        string      // name stored in every entry's Source field, e.g. the file path
+	Multiline     MultilineOptions
+	Formats       []LineFormat // additional named formats, e.g. user-defined ones, which take precedence over built-in ones
+}
+
+// LineBudget is a number of non-empty lines that several readers draw from,
+// so that a --max-lines limit covers all inputs of one run together
+type LineBudget struct {
+	remaining int
+}
+
+// NewLineBudget creates a budget of n lines, or nil, meaning unlimited,
+// when n is 0 or less
+func NewLineBudget(n int) *LineBudget {
+	if n <= 0 {
+		return nil
+	}
+	return &LineBudget{remaining: n}
+}
+
+// take uses up one line, reporting false once the budget is spent. A nil
+// budget never runs out.
+func (b *LineBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	if b.remaining == 0 {
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+// lineBudget returns the shared budget, or a budget of MaxLines lines for
+// this input alone
+func (o Options) lineBudget() *LineBudget {
+	if o.Budget != nil {
+		return o.Budget
+	}
+	return NewLineBudget(o.MaxLines)
+}
+
+// Reader parses log entries incrementally from an io.Reader.
+// Only one batch of lines is held in memory at a time.
+type Reader struct {
+	scanner   *bufio.Scanner
+	joiner    *lineJoiner
+	options   Options
+	budget    *LineBudget
+	format    logparser.Format
+	parser    logparser.Parser
+	line      LineFormat // set instead of parser for named line formats
+	pending   []*common.LogEntry
+	lineNum   int
+	linesRead int
+	skipped   int
+	truncated bool
+	eof       bool
+	hold      bool // keep the last record at end of input, for a Feeder
+}
+
+// batchLine is a record to parse with its position in the input
+type batchLine struct {
+	text   string
+	raw    string // every line of a multi-line record, empty otherwise
+	number int
+}
+
+// NewReader creates a streaming entry reader
+func NewReader(r io.Reader, options Options) (*Reader, error) {
+	format, line, err := lookupFormat(options.Format, options.Formats)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.MaxLineLength <= 0 {
+		options.MaxLineLength = DefaultMaxLineLength
+	}
+
+	joiner, err := newLineJoiner(options.Multiline)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), options.MaxLineLength)
+
+	reader := &Reader{
+		scanner: scanner,
+		joiner:  joiner,
+		options: options,
+		budget:  options.lineBudget(),
+		format:  format,
+		line:    line,
+		lineNum: options.LineOffset,
+	}
+	if format != logparser.FormatAuto && line == nil {
+		reader.parser = logparser.NewWithFormat(format)
+	}
+
+	return reader, nil
+}
+
+// ParseFormat converts a format name to a logparser format
+func ParseFormat(name string) (logparser.Format, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return logparser.FormatAuto, nil
+	case "json":
+		return logparser.FormatJSON, nil
+	case "logfmt":
+		return logparser.FormatLogfmt, nil
+	case "text":
+		return logparser.FormatText, nil
+	default:
+		return logparser.FormatAuto, fmt.Errorf("unknown format %s. Available formats: json, logfmt, text", name)
+	}
+}
+
+// lookupFormat resolves a format name to a go-logparser format or, failing
+// that, to one of the given or built-in line formats
+func lookupFormat(name string, formats []LineFormat) (logparser.Format, LineFormat, error) {
+	format, err := ParseFormat(name)
+	if err == nil {
+		return format, nil, nil
+	}
+
+	names := append([]string{"json", "logfmt", "text"}, BuiltinFormatNames()...)
+	for _, line := range formats {
+		if line.Name() == name {
+			return logparser.FormatText, line, nil
+		}
+		names = append(names, line.Name())
+	}
+	if line, ok := builtinFormats[strings.ToLower(name)]; ok {
+		return logparser.FormatText, line, nil
+	}
+	return logparser.FormatAuto, nil, fmt.Errorf("unknown format %s. Available formats: %s", name, strings.Join(names, ", "))
+}
+
+// Next returns the next parsed entry, or io.EOF when the input is exhausted
+func (r *Reader) Next() (*common.LogEntry, error) {
+	for len(r.pending) == 0 {
+		if r.eof {
+			return nil, io.EOF
+		}
+		if err := r.fill(); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := r.pending[0]
+	r.pending[0] = nil
+	r.pending = r.pending[1:]
+	return entry, nil
+}
+
+// Format returns the resolved log format. It is FormatAuto until the
+// first batch has been read, and FormatText for named line formats.
+func (r *Reader) Format() logparser.Format {
+	return r.format
+}
+
+// FormatName returns the name of the resolved format, which selects the
+// same format again when passed as Options.Format
+func (r *Reader) FormatName() string {
+	if r.line != nil {
+		return r.line.Name()
+	}
+	return r.format.String()
+}
+
+// LinesRead returns the number of non-empty lines consumed so far
+func (r *Reader) LinesRead() int {
+	return r.linesRead
+}
+
+// LineNumber returns the number of the last input line consumed,
+// including empty lines and LineOffset
+func (r *Reader) LineNumber() int {
+	return r.lineNum
+}
+
+// Skipped returns the number of lines that could not be parsed
+func (r *Reader) Skipped() int {
+	return r.skipped
+}
+
+// Truncated reports whether input was left unread because of MaxLines or
+// a spent Budget
+func (r *Reader) Truncated() bool {
+	return r.truncated
+}
+
+// fill reads and parses the next batch of records. Continuation lines are
+// joined to the record they belong to, so a record is only added to the
+// batch once the line starting the next one has been read.
+func (r *Reader) fill() error {
+	batch := make([]batchLine, 0, r.options.BatchSize)
+
+	for len(batch) < r.options.BatchSize {
+		if !r.scanner.Scan() {
+			r.eof = true
+			break
+		}
+
+		r.lineNum++
+		line := strings.TrimRight(r.scanner.Text(), " \t\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if !r.budget.take() {
+			r.truncated = true
+			r.eof = true
+			break
+		}
+
+		r.linesRead++
+		if completed := r.joiner.add(line, r.lineNum); completed != nil {
+			batch = append(batch, newBatchLine(completed))
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return fmt.Errorf("scanner error: %w", err)
+	}
+
+	if r.eof && !r.hold {
+		if completed := r.joiner.flush(); completed != nil {
+			batch = append(batch, newBatchLine(completed))
+		}
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if r.parser == nil && r.line == nil {
+		r.resolveFormat(batch)
+	}
+
+	r.pending = r.parseBatch(batch)
+	return nil
+}
+
+// newBatchLine prepares a record for parsing. Only the first line is
+// parsed; the full text of a multi-line record is kept as its raw form.
+func newBatchLine(rec *record) batchLine {
+	line := batchLine{text: rec.text(), number: rec.number}
+	if len(rec.lines) > 1 {
+		line.raw = rec.raw()
+	}
+	return line
+}
+
+// resolveFormat auto-detects the format from the first lines of the stream,
+// preferring a built-in line format over generic JSON, logfmt and text
+func (r *Reader) resolveFormat(batch []batchLine) {
+	sampleSize := minInt(len(batch), detectSampleSize)
+	samples := make([]string, sampleSize)
+	for i := 0; i < sampleSize; i++ {
+		samples[i] = batch[i].text
+	}
+
+	if line := detectLineFormat(samples); line != nil {
+		r.format = logparser.FormatText
+		r.line = line
+		return
+	}
+
+	r.format = DetectFormat(samples)
+	r.parser = logparser.NewWithFormat(r.format)
+}
+
+// parseBatch parses a batch of lines, falling back to line-by-line parsing
+// so that a single malformed line does not discard the whole batch
+func (r *Reader) parseBatch(batch []batchLine) []*common.LogEntry {
+	if r.line != nil {
+		return r.parseLines(batch)
+	}
+
+	texts := make([]string, len(batch))
+	for i, line := range batch {
+		texts[i] = line.text
+	}
+
+	parsed, err := r.parser.ParseString(strings.Join(texts, "\n"))
+	if err == nil && len(parsed) == len(batch) {
+		entries := make([]*common.LogEntry, len(parsed))
+		for i := range parsed {
+			entries[i] = r.convert(&parsed[i], batch[i])
+		}
+		return entries
+	}
+
+	entries := make([]*common.LogEntry, 0, len(batch))
+	for _, line := range batch {
+		single, err := r.parser.ParseString(line.text)
+		if err != nil || len(single) == 0 {
+			r.skipped++
+			continue
+		}
+		entries = append(entries, r.convert(&single[0], line))
+	}
+	return entries
+}
+
+// parseLines parses a batch with a named line format, one line at a time
+func (r *Reader) parseLines(batch []batchLine) []*common.LogEntry {
+	entries := make([]*common.LogEntry, 0, len(batch))
+	for _, line := range batch {
+		entry, err := r.line.ParseLine(line.text)
+		if err != nil {
+			r.skipped++
+			continue
+		}
+		entry.LineNumber = line.number
+		entry.Source = r.options.Source
+		entry.Raw = line.raw
+		if annotator, ok := r.line.(sourceAnnotator); ok {
+			annotator.annotateSource(entry, r.options.Source)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// convert turns a parsed entry into a LogSum entry tagged with the source
+func (r *Reader) convert(parsed *logparser.LogEntry, line batchLine) *common.LogEntry {
+	entry := common.ConvertToCommonLogEntry(parsed, line.number)
+	entry.Source = r.options.Source
+	entry.Raw = line.raw
+	return entry
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
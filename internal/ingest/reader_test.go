@@ -0,0 +1,131 @@
+package ingest
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yildizm/go-logparser"
+)
+
+func readAll(t *testing.T, reader *Reader) []int {
+	t.Helper()
+	var lineNumbers []int
+	for {
+		entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return lineNumbers
+		}
+		if err != nil {
+			t.Fatalf("Next() failed: %v", err)
+		}
+		lineNumbers = append(lineNumbers, entry.LineNumber)
+	}
+}
+
+func TestReaderStreamsInBatches(t *testing.T) {
+	input := "2024-01-01 10:00:00 [INFO] started\n\n" +
+		"2024-01-01 10:00:01 [ERROR] failed\n" +
+		"2024-01-01 10:00:02 [WARN] slow\n"
+
+	reader, err := NewReader(strings.NewReader(input), Options{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+
+	lineNumbers := readAll(t, reader)
+	expected := []int{1, 3, 4}
+	if len(lineNumbers) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(lineNumbers))
+	}
+	for i, want := range expected {
+		if lineNumbers[i] != want {
+			t.Errorf("Entry %d: expected line %d, got %d", i, want, lineNumbers[i])
+		}
+	}
+
+	if reader.Format() != logparser.FormatText {
+		t.Errorf("Expected text format, got %s", reader.Format())
+	}
+	if reader.Truncated() {
+		t.Error("Reader should not report truncation")
+	}
+}
+
+func TestReaderMaxLines(t *testing.T) {
+	input := "a\nb\nc\nd\n"
+
+	reader, err := NewReader(strings.NewReader(input), Options{MaxLines: 2})
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+
+	if got := len(readAll(t, reader)); got != 2 {
+		t.Errorf("Expected 2 entries, got %d", got)
+	}
+	if !reader.Truncated() {
+		t.Error("Reader should report truncation")
+	}
+}
+
+func TestReaderSkipsMalformedJSON(t *testing.T) {
+	input := `{"level":"error","msg":"one"}
+{"level":"info","msg":"two"}
+not json at all
+{"level":"warn","msg":"three"}`
+
+	reader, err := NewReader(strings.NewReader(input), Options{Format: "json"})
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+
+	if got := len(readAll(t, reader)); got != 3 {
+		t.Errorf("Expected 3 entries, got %d", got)
+	}
+	if reader.Skipped() != 1 {
+		t.Errorf("Expected 1 skipped line, got %d", reader.Skipped())
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []string
+		expected logparser.Format
+	}{
+		{"json", []string{`{"msg":"a"}`, `{"msg":"b"}`}, logparser.FormatJSON},
+		{"logfmt", []string{`level=info msg=a`, `level=warn msg=b`}, logparser.FormatLogfmt},
+		{"text", []string{`plain line`, `[ERROR] another`}, logparser.FormatText},
+		{"empty", nil, logparser.FormatText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.samples); got != tt.expected {
+				t.Errorf("DetectFormat() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseFormatUnknown(t *testing.T) {
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestReaderLineOffset(t *testing.T) {
+	reader, err := NewReader(strings.NewReader("x\ny\n"), Options{Format: "text", LineOffset: 10})
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+
+	lineNumbers := readAll(t, reader)
+	if len(lineNumbers) != 2 || lineNumbers[0] != 11 || lineNumbers[1] != 12 {
+		t.Errorf("Expected line numbers [11 12], got %v", lineNumbers)
+	}
+	if reader.LineNumber() != 12 {
+		t.Errorf("Expected LineNumber() 12, got %d", reader.LineNumber())
+	}
+}
@@ -0,0 +1,219 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+var (
+	// rfc5424Pattern matches <PRI>1 TIMESTAMP HOST APP PROCID MSGID SD [MSG]
+	rfc5424Pattern = regexp.MustCompile(
+		`^<(\d{1,3})>1 (\S+) (\S+) (\S+) (\S+) (\S+) (-|(?:\[(?:[^\]\\]|\\.)*\])+)(?: (.*))?$`)
+
+	// rfc3164Pattern matches [<PRI>]Mmm dd hh:mm:ss HOST TAG[PID]: MSG, the
+	// format of /var/log/syslog and /var/log/messages
+	rfc3164Pattern = regexp.MustCompile(
+		`^(?:<(\d{1,3})>)?([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}) (\S+) ([^\s:\[]+)(?:\[(\d+)\])?: ?(.*)$`)
+)
+
+// syslogSeverities names the syslog severities by numeric value
+var syslogSeverities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// syslogFacilities names the syslog facilities by numeric value
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// syslogFormat parses RFC 5424 and RFC 3164 syslog lines. The severity in
+// the priority sets the level; lines without a priority fall back to a
+// level named in the message.
+type syslogFormat struct {
+	now func() time.Time // reference for the year RFC 3164 timestamps omit
+}
+
+func newSyslogFormat() syslogFormat {
+	return syslogFormat{now: time.Now}
+}
+
+// Name returns the name the format is selected by
+func (f syslogFormat) Name() string {
+	return "syslog"
+}
+
+// ParseLine parses an RFC 5424 or RFC 3164 line
+func (f syslogFormat) ParseLine(line string) (*common.LogEntry, error) {
+	if match := rfc5424Pattern.FindStringSubmatch(line); match != nil {
+		return f.parse5424(match)
+	}
+	if match := rfc3164Pattern.FindStringSubmatch(line); match != nil {
+		return f.parse3164(match)
+	}
+	return nil, fmt.Errorf("not a syslog line")
+}
+
+// parse5424 builds an entry from an RFC 5424 match
+func (f syslogFormat) parse5424(match []string) (*common.LogEntry, error) {
+	var timestamp time.Time
+	if match[2] != "-" {
+		ts, err := time.Parse(time.RFC3339Nano, match[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid syslog timestamp: %w", err)
+		}
+		timestamp = ts
+	}
+
+	message := strings.TrimPrefix(match[8], "\ufeff")
+	entry, err := newSyslogEntry(match[1], timestamp, message)
+	if err != nil {
+		return nil, err
+	}
+	if match[4] != "-" {
+		entry.Service = match[4]
+	}
+	setMetadata(entry, "hostname", match[3])
+	setMetadata(entry, "pid", match[5])
+	setMetadata(entry, "msgid", match[6])
+	setMetadata(entry, "structured_data", match[7])
+	return entry, nil
+}
+
+// parse3164 builds an entry from an RFC 3164 match
+func (f syslogFormat) parse3164(match []string) (*common.LogEntry, error) {
+	timestamp, err := time.Parse(time.Stamp, match[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog timestamp: %w", err)
+	}
+
+	entry, err := newSyslogEntry(match[1], f.withYear(timestamp), match[6])
+	if err != nil {
+		return nil, err
+	}
+	entry.Service = match[4]
+	setMetadata(entry, "hostname", match[3])
+	setMetadata(entry, "pid", match[5])
+	return entry, nil
+}
+
+// withYear dates an RFC 3164 timestamp in the current year, or the one
+// before when that would put it in the future, as after a new year
+func (f syslogFormat) withYear(timestamp time.Time) time.Time {
+	now := f.now()
+	dated := timestamp.AddDate(now.Year(), 0, 0)
+	if dated.After(now.AddDate(0, 0, 1)) {
+		dated = dated.AddDate(-1, 0, 0)
+	}
+	return dated
+}
+
+// newSyslogEntry creates an entry with the level of the priority value, if
+// any, and records the facility and severity names
+func newSyslogEntry(priority string, timestamp time.Time, message string) (*common.LogEntry, error) {
+	parsed := logparser.LogEntry{Timestamp: timestamp, Message: message}
+	facility, severity := -1, -1
+
+	if priority != "" {
+		value, err := strconv.Atoi(priority)
+		if err != nil || value > 191 {
+			return nil, fmt.Errorf("invalid syslog priority %s", priority)
+		}
+		facility, severity = value/8, value%8
+		parsed.Level = severityLevel(severity)
+	} else {
+		parsed.Level = levelFromMessage(message)
+	}
+
+	entry := common.ConvertToCommonLogEntry(&parsed, 0)
+	if severity >= 0 {
+		entry.Metadata["facility"] = syslogFacilities[facility]
+		entry.Metadata["severity"] = syslogSeverities[severity]
+	}
+	return entry, nil
+}
+
+// severityLevel maps a syslog severity onto a log level
+func severityLevel(severity int) string {
+	switch {
+	case severity <= 1:
+		return "FATAL"
+	case severity <= 3:
+		return "ERROR"
+	case severity == 4:
+		return "WARN"
+	case severity == 7:
+		return "DEBUG"
+	default:
+		return "INFO"
+	}
+}
+
+// journaldFormat parses the JSON export of journalctl -o json
+type journaldFormat struct{}
+
+// Name returns the name the format is selected by
+func (journaldFormat) Name() string {
+	return "journald"
+}
+
+// ParseLine parses one journal record. Records without a realtime
+// timestamp or a text message are rejected.
+func (journaldFormat) ParseLine(line string) (*common.LogEntry, error) {
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, fmt.Errorf("invalid journald record: %w", err)
+	}
+
+	realtime, ok := record["__REALTIME_TIMESTAMP"].(string)
+	if !ok {
+		return nil, fmt.Errorf("journald record has no __REALTIME_TIMESTAMP")
+	}
+	micros, err := strconv.ParseInt(realtime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid journald timestamp: %w", err)
+	}
+	message, ok := record["MESSAGE"].(string)
+	if !ok {
+		return nil, fmt.Errorf("journald record has no text MESSAGE")
+	}
+
+	parsed := logparser.LogEntry{
+		Timestamp: time.UnixMicro(micros).UTC(),
+		Level:     levelFromMessage(message),
+		Message:   message,
+	}
+	if priority, ok := record["PRIORITY"].(string); ok {
+		if severity, err := strconv.Atoi(priority); err == nil && severity >= 0 && severity < len(syslogSeverities) {
+			parsed.Level = severityLevel(severity)
+		}
+	}
+
+	entry := common.ConvertToCommonLogEntry(&parsed, 0)
+	entry.Service = firstString(record, "SYSLOG_IDENTIFIER", "_SYSTEMD_UNIT", "_COMM")
+	for key, field := range map[string]string{
+		"unit":      "_SYSTEMD_UNIT",
+		"hostname":  "_HOSTNAME",
+		"pid":       "_PID",
+		"container": "CONTAINER_NAME",
+	} {
+		setMetadata(entry, key, firstString(record, field))
+	}
+	return entry, nil
+}
+
+// firstString returns the first of the keys holding a string value
+func firstString(record map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := record[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package query
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/LogSum/internal/filter"
+)
+
+// Result is the table a query produces. Count columns hold ints; all other
+// cells are strings.
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// group is the running count of one group and time bucket
+type group struct {
+	values []string
+	bucket time.Time
+	count  int
+	order  int // first-seen order, to keep ties stable
+}
+
+// Executor runs a query over entries added one at a time
+type Executor struct {
+	query  *Query
+	rows   [][]interface{}
+	groups map[string]*group
+}
+
+// NewExecutor creates an executor for q
+func NewExecutor(q *Query) *Executor {
+	return &Executor{query: q, groups: make(map[string]*group)}
+}
+
+// Add feeds an entry to the query
+func (e *Executor) Add(entry *common.LogEntry) {
+	for _, expr := range e.query.Where {
+		if !expr.Match(entry) {
+			return
+		}
+	}
+
+	if e.query.Aggregation == AggregateNone {
+		if !e.Done() {
+			e.rows = append(e.rows, e.listRow(entry))
+		}
+		return
+	}
+
+	var bucket time.Time
+	if e.query.Bucket > 0 {
+		// Entries without a timestamp cannot be placed in a time bucket
+		if entry.Timestamp.IsZero() {
+			return
+		}
+		bucket = entry.Timestamp.Truncate(e.query.Bucket)
+	}
+
+	values := make([]string, len(e.query.GroupBy))
+	for i, field := range e.query.GroupBy {
+		values[i], _ = filter.FieldValue(entry, field)
+	}
+	key := bucket.String() + "\x00" + strings.Join(values, "\x00")
+
+	g, ok := e.groups[key]
+	if !ok {
+		g = &group{values: values, bucket: bucket, order: len(e.groups)}
+		e.groups[key] = g
+	}
+	g.count++
+}
+
+// Done reports whether further entries can no longer change the result,
+// as when a listing has reached its limit
+func (e *Executor) Done() bool {
+	return e.query.Aggregation == AggregateNone && e.query.Limit > 0 && len(e.rows) >= e.query.Limit
+}
+
+// listRow returns the selected fields of an entry
+func (e *Executor) listRow(entry *common.LogEntry) []interface{} {
+	row := make([]interface{}, len(e.query.Fields))
+	for i, field := range e.query.Fields {
+		value, _ := filter.FieldValue(entry, field)
+		row[i] = value
+	}
+	return row
+}
+
+// Result returns the query result for the entries added so far. Counts
+// are ordered by time bucket, then by descending count.
+func (e *Executor) Result() *Result {
+	if e.query.Aggregation == AggregateNone {
+		return &Result{Columns: e.query.Fields, Rows: e.rows}
+	}
+
+	var columns []string
+	if e.query.Bucket > 0 {
+		columns = append(columns, "time")
+	}
+	columns = append(columns, e.query.GroupBy...)
+	columns = append(columns, "count")
+
+	groups := make([]*group, 0, len(e.groups))
+	for _, g := range e.groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if !a.bucket.Equal(b.bucket) {
+			return a.bucket.Before(b.bucket)
+		}
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		return a.order < b.order
+	})
+
+	// A plain count of nothing still reports zero
+	if len(groups) == 0 && len(e.query.GroupBy) == 0 && e.query.Bucket == 0 {
+		return &Result{Columns: columns, Rows: [][]interface{}{{0}}}
+	}
+
+	if e.query.Limit > 0 && len(groups) > e.query.Limit {
+		groups = groups[:e.query.Limit]
+	}
+
+	rows := make([][]interface{}, 0, len(groups))
+	for _, g := range groups {
+		row := make([]interface{}, 0, len(columns))
+		if e.query.Bucket > 0 {
+			row = append(row, g.bucket.Format(time.RFC3339))
+		}
+		for _, value := range g.values {
+			row = append(row, value)
+		}
+		rows = append(rows, append(row, g.count))
+	}
+	return &Result{Columns: columns, Rows: rows}
+}
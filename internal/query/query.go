@@ -0,0 +1,248 @@
+// Package query implements the small pipeline language of logsum query,
+// e.g. `where level>=ERROR | count by service per 1m`
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/filter"
+)
+
+// Aggregation selects how matching entries are summarized
+type Aggregation int
+
+const (
+	// AggregateNone lists the matching entries themselves
+	AggregateNone Aggregation = iota
+	// AggregateCount counts entries, optionally per group and time bucket
+	AggregateCount
+	// AggregateTop counts entries per group and keeps the largest groups
+	AggregateTop
+)
+
+// defaultFields are the columns listed when a query does not aggregate
+var defaultFields = []string{"timestamp", "level", "service", "message"}
+
+// Query is a parsed query. Stages are separated by | with whitespace around
+// it and may appear in any order:
+//
+//	where EXPR                    keep entries matching a filter expression
+//	count [by F[, F...]] [per D]  count entries per group and time bucket
+//	top N F[, F...]               the N most frequent values of the fields
+//	fields F[, F...]              columns to list when not aggregating
+//	limit N                       at most N result rows
+//
+// A query that does not start with a stage keyword is taken as a where
+// expression.
+type Query struct {
+	Where       []*filter.Expression
+	Aggregation Aggregation
+	GroupBy     []string
+	Bucket      time.Duration
+	Fields      []string
+	Limit       int
+}
+
+// Parse parses a query
+func Parse(text string) (*Query, error) {
+	q := &Query{}
+	for i, stage := range splitStages(text) {
+		keyword, rest := splitKeyword(stage)
+		var err error
+		switch strings.ToLower(keyword) {
+		case "where":
+			err = q.parseWhere(rest)
+		case "count":
+			err = q.parseCount(rest)
+		case "top":
+			err = q.parseTop(rest)
+		case "fields":
+			q.Fields, err = parseFieldList(rest)
+		case "limit":
+			err = q.parseLimit(rest)
+		default:
+			if i > 0 || stage == "" {
+				return nil, fmt.Errorf("unknown query stage %q: use where, count, top, fields or limit", stage)
+			}
+			err = q.parseWhere(stage)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", stage, err)
+		}
+	}
+
+	if q.Aggregation != AggregateNone && len(q.Fields) > 0 {
+		return nil, fmt.Errorf("fields cannot be combined with count or top")
+	}
+	if q.Aggregation == AggregateNone && len(q.Fields) == 0 {
+		q.Fields = defaultFields
+	}
+	return q, nil
+}
+
+func (q *Query) parseWhere(text string) error {
+	expr, err := filter.Parse(text)
+	if err != nil {
+		return err
+	}
+	q.Where = append(q.Where, expr)
+	return nil
+}
+
+// parseCount parses `[by F[, F...]] [per D]`
+func (q *Query) parseCount(text string) error {
+	if err := q.setAggregation(AggregateCount); err != nil {
+		return err
+	}
+
+	if before, after, found := cutWord(text, "per"); found {
+		bucket, err := filter.ParseDuration(after)
+		if err != nil || bucket <= 0 {
+			return fmt.Errorf("invalid time bucket %q", after)
+		}
+		q.Bucket = bucket
+		text = before
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	keyword, rest := splitKeyword(text)
+	if !strings.EqualFold(keyword, "by") {
+		return fmt.Errorf("expected by or per, got %q", keyword)
+	}
+	fields, err := parseFieldList(rest)
+	if err != nil {
+		return err
+	}
+	q.GroupBy = fields
+	return nil
+}
+
+// parseTop parses `N F[, F...]`
+func (q *Query) parseTop(text string) error {
+	if err := q.setAggregation(AggregateTop); err != nil {
+		return err
+	}
+	number, rest := splitKeyword(text)
+	n, err := parseCount(number)
+	if err != nil {
+		return err
+	}
+	fields, err := parseFieldList(rest)
+	if err != nil {
+		return err
+	}
+	q.GroupBy = fields
+	if q.Limit == 0 || n < q.Limit {
+		q.Limit = n
+	}
+	return nil
+}
+
+// parseLimit parses `N`. Alongside top, the smaller of the two limits
+// applies whichever stage comes first.
+func (q *Query) parseLimit(text string) error {
+	n, err := parseCount(text)
+	if err != nil {
+		return err
+	}
+	if q.Aggregation == AggregateTop && q.Limit > 0 && q.Limit < n {
+		return nil
+	}
+	q.Limit = n
+	return nil
+}
+
+func (q *Query) setAggregation(aggregation Aggregation) error {
+	if q.Aggregation != AggregateNone {
+		return fmt.Errorf("a query can only have one count or top stage")
+	}
+	q.Aggregation = aggregation
+	return nil
+}
+
+// stageKeywords are the words that start a stage
+var stageKeywords = map[string]bool{"where": true, "count": true, "top": true, "fields": true, "limit": true}
+
+// splitStages splits a query on | characters outside quotes that have
+// whitespace on both sides and are followed by a stage keyword. Any other
+// |, such as in message~timeout|refused or the || operator, belongs to the
+// filter expression.
+func splitStages(text string) []string {
+	var stages []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '|' && isStageBreak(text, i):
+			stages = append(stages, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	return append(stages, strings.TrimSpace(text[start:]))
+}
+
+// isStageBreak reports whether the | at text[i] separates two stages
+func isStageBreak(text string, i int) bool {
+	if i == 0 || i+1 >= len(text) || !isBlank(text[i-1]) || !isBlank(text[i+1]) {
+		return false
+	}
+	keyword, _ := splitKeyword(text[i+1:])
+	return stageKeywords[strings.ToLower(keyword)]
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// splitKeyword splits off the first word of text
+func splitKeyword(text string) (keyword, rest string) {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexFunc(text, func(r rune) bool { return r == ' ' || r == '\t' }); i >= 0 {
+		return text[:i], strings.TrimSpace(text[i+1:])
+	}
+	return text, ""
+}
+
+// cutWord splits text around the last standalone occurrence of word
+func cutWord(text, word string) (before, after string, found bool) {
+	words := strings.Fields(text)
+	for i := len(words) - 1; i >= 0; i-- {
+		if strings.EqualFold(words[i], word) {
+			return strings.Join(words[:i], " "), strings.Join(words[i+1:], " "), true
+		}
+	}
+	return text, "", false
+}
+
+// parseFieldList parses a comma or space separated list of field names
+func parseFieldList(text string) ([]string, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("expected field names")
+	}
+	return fields, nil
+}
+
+// parseCount parses a positive row count
+func parseCount(text string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("expected a positive number, got %q", text)
+	}
+	return n, nil
+}
@@ -0,0 +1,157 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yildizm/LogSum/internal/common"
+	"github.com/yildizm/go-logparser"
+)
+
+func testEntries() []*common.LogEntry {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entry := func(offset time.Duration, level common.LogLevel, service, message, trace string) *common.LogEntry {
+		return &common.LogEntry{
+			LogEntry: logparser.LogEntry{Timestamp: base.Add(offset), Message: message},
+			LogLevel: level,
+			Service:  service,
+			TraceID:  trace,
+		}
+	}
+	return []*common.LogEntry{
+		entry(5*time.Second, common.LevelInfo, "payments", "start", "t1"),
+		entry(30*time.Second, common.LevelError, "payments", "card declined", "t1"),
+		entry(70*time.Second, common.LevelError, "users", "timeout", "t2"),
+		entry(100*time.Second, common.LevelError, "payments", "card declined", "t1"),
+		entry(110*time.Second, common.LevelWarn, "payments", "retry", "t1"),
+	}
+}
+
+func TestQueryResults(t *testing.T) {
+	tests := []struct {
+		query   string
+		columns []string
+		rows    [][]interface{}
+	}{
+		{
+			query:   "count",
+			columns: []string{"count"},
+			rows:    [][]interface{}{{5}},
+		},
+		{
+			query:   "level=FATAL | count",
+			columns: []string{"count"},
+			rows:    [][]interface{}{{0}},
+		},
+		{
+			query:   "count by level",
+			columns: []string{"level", "count"},
+			rows:    [][]interface{}{{"ERROR", 3}, {"INFO", 1}, {"WARN", 1}},
+		},
+		{
+			query:   "level>=ERROR | count by service per 1m",
+			columns: []string{"time", "service", "count"},
+			rows: [][]interface{}{
+				{"2024-01-15T10:00:00Z", "payments", 1},
+				{"2024-01-15T10:01:00Z", "users", 1},
+				{"2024-01-15T10:01:00Z", "payments", 1},
+			},
+		},
+		{
+			query:   "where trace_id=t1 | top 1 message",
+			columns: []string{"message", "count"},
+			rows:    [][]interface{}{{"card declined", 2}},
+		},
+		{
+			query:   "where service=payments || level=WARN | fields message | limit 2",
+			columns: []string{"message"},
+			rows:    [][]interface{}{{"start"}, {"card declined"}},
+		},
+		{
+			query:   "where message~declined|timeout | count",
+			columns: []string{"count"},
+			rows:    [][]interface{}{{3}},
+		},
+		{
+			query:   "message~'declined|timeout'",
+			columns: []string{"timestamp", "level", "service", "message"},
+			rows: [][]interface{}{
+				{"2024-01-15T10:00:30Z", "ERROR", "payments", "card declined"},
+				{"2024-01-15T10:01:10Z", "ERROR", "users", "timeout"},
+				{"2024-01-15T10:01:40Z", "ERROR", "payments", "card declined"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.query, err)
+		}
+		executor := NewExecutor(q)
+		for _, entry := range testEntries() {
+			executor.Add(entry)
+		}
+		result := executor.Result()
+		if !reflect.DeepEqual(result.Columns, tt.columns) {
+			t.Errorf("%q: columns = %v, want %v", tt.query, result.Columns, tt.columns)
+		}
+		if !reflect.DeepEqual(result.Rows, tt.rows) {
+			t.Errorf("%q: rows = %v, want %v", tt.query, result.Rows, tt.rows)
+		}
+	}
+}
+
+func TestParseInvalidQuery(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"count | top 3 message",
+		"count by",
+		"count per forever",
+		"top ten message",
+		"top 3",
+		"limit 0",
+		"count | fields message",
+		"where level>=LOUD",
+		"count | sort message",
+	} {
+		if _, err := Parse(query); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", query)
+		}
+	}
+}
+
+func TestTopLimit(t *testing.T) {
+	for query, want := range map[string]int{
+		"top 2 message | limit 20": 2,
+		"limit 20 | top 2 message": 2,
+		"top 5 message | limit 1":  1,
+		"limit 1 | top 5 message":  1,
+	} {
+		q, err := Parse(query)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", query, err)
+		}
+		if q.Limit != want {
+			t.Errorf("Parse(%q).Limit = %d, want %d", query, q.Limit, want)
+		}
+	}
+}
+
+func TestExecutorDone(t *testing.T) {
+	q, err := Parse("limit 2")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	executor := NewExecutor(q)
+	for _, entry := range testEntries()[:2] {
+		if executor.Done() {
+			t.Fatal("Done() before the limit was reached")
+		}
+		executor.Add(entry)
+	}
+	if !executor.Done() {
+		t.Error("Done() = false after the limit was reached")
+	}
+}
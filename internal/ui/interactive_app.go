@@ -41,6 +41,10 @@ type InteractiveModel struct {
 	ready     bool
 	quitting  bool
 
+	// Follow mode state
+	updates    <-chan LiveUpdate
+	lastUpdate time.Time
+
 	// Navigation state
 	currentView   InteractiveViewState
 	selectedIndex int
@@ -78,6 +82,16 @@ func NewInteractiveModel(entries []*common.LogEntry, patterns []*common.Pattern)
 
 // Init initializes the interactive model
 func (m *InteractiveModel) Init() tea.Cmd {
+	if m.updates != nil {
+		m.analyzing = true
+		m.analysisStep = emoji.GetEmoji("pattern") + " Reading log file..."
+		return tea.Batch(
+			tea.EnterAltScreen,
+			waitForLiveUpdate(m.updates),
+			tick(),
+		)
+	}
+
 	return tea.Batch(
 		tea.EnterAltScreen,
 		m.startAnalysis(),
@@ -100,6 +114,8 @@ func (m *InteractiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleAnalysisError(msg)
 	case analysisProgressMsg:
 		return m.handleAnalysisProgress(msg)
+	case liveUpdateMsg:
+		return m.handleLiveUpdate(msg)
 	}
 
 	return m, nil
@@ -260,6 +276,10 @@ func (m *InteractiveModel) renderMainMenu() string {
 		len(m.analysis.Insights),
 	)
 
+	if m.updates != nil {
+		stats += fmt.Sprintf(" • following (updated %s)", m.lastUpdate.Format("15:04:05"))
+	}
+
 	statsStyled := lipgloss.NewStyle().
 		Foreground(m.secondaryColor).
 		Render(stats)
@@ -830,6 +850,24 @@ func (m *InteractiveModel) handleAnalysisComplete(msg analysisCompleteMsg) (tea.
 	return m, nil
 }
 
+// handleLiveUpdate replaces the analysis with a refreshed one in follow mode
+func (m *InteractiveModel) handleLiveUpdate(msg liveUpdateMsg) (tea.Model, tea.Cmd) {
+	m.analysis = msg.update.Analysis
+	m.entries = msg.update.Entries
+	m.analyzing = false
+	m.lastUpdate = time.Now()
+
+	if m.currentView == InteractiveViewAnalyzing {
+		m.currentView = InteractiveViewMainMenu
+	}
+	m.updateMaxIndex()
+	if m.selectedIndex > m.maxIndex {
+		m.selectedIndex = m.maxIndex
+	}
+
+	return m, waitForLiveUpdate(m.updates)
+}
+
 // handleAnalysisError handles analysis errors
 func (m *InteractiveModel) handleAnalysisError(_ analysisErrorMsg) (tea.Model, tea.Cmd) {
 	m.analyzing = false
@@ -850,3 +888,13 @@ func InteractiveRun(entries []*common.LogEntry, patterns []*common.Pattern) erro
 	_, err := p.Run()
 	return err
 }
+
+// InteractiveFollowRun runs the interactive TUI in follow mode, refreshing
+// the displayed analysis whenever an update arrives on the channel
+func InteractiveFollowRun(updates <-chan LiveUpdate, patterns []*common.Pattern) error {
+	model := NewInteractiveModel(nil, patterns)
+	model.updates = updates
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
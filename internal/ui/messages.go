@@ -18,6 +18,27 @@ type analysisErrorMsg struct {
 	err error
 }
 
+// LiveUpdate carries a refreshed analysis while a growing log is followed
+type LiveUpdate struct {
+	Analysis *analyzer.Analysis
+	Entries  []*common.LogEntry // most recent entries, oldest first
+}
+
+type liveUpdateMsg struct {
+	update LiveUpdate
+}
+
+// waitForLiveUpdate creates a tea command that delivers the next live update
+func waitForLiveUpdate(updates <-chan LiveUpdate) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return liveUpdateMsg{update: update}
+	}
+}
+
 // CreateAnalysisCommand creates a tea command that performs analysis
 func CreateAnalysisCommand(entries []*common.LogEntry, patterns []*common.Pattern) tea.Cmd {
 	return func() tea.Msg {